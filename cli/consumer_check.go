@@ -0,0 +1,147 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/jsm.go/monitor"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// consumerStallIssue is a problem found by comparing two samples of a consumer state
+type consumerStallIssue struct {
+	Consumer string
+	Critical bool
+	Problem  string
+}
+
+// checkConsumerProgress compares two samples of a consumer and reports signs that it is stalled
+func checkConsumerProgress(before api.ConsumerInfo, after api.ConsumerInfo, ackPendingWarn int) []consumerStallIssue {
+	var issues []consumerStallIssue
+
+	add := func(critical bool, format string, a ...any) {
+		issues = append(issues, consumerStallIssue{Consumer: after.Name, Critical: critical, Problem: fmt.Sprintf(format, a...)})
+	}
+
+	if after.NumRedelivered > before.NumRedelivered && after.AckFloor.Stream == before.AckFloor.Stream {
+		add(true, "redelivering %s messages while the ack floor stays at %s", f(after.NumRedelivered), f(after.AckFloor.Stream))
+	}
+
+	if after.Config.DeliverSubject == "" && !after.Paused && after.NumWaiting == 0 && after.NumPending > before.NumPending {
+		add(false, "no pull requests waiting while unprocessed messages grew from %s to %s", f(before.NumPending), f(after.NumPending))
+	}
+
+	if after.Config.MaxAckPending > 0 {
+		pct := after.NumAckPending * 100 / after.Config.MaxAckPending
+		switch {
+		case after.NumAckPending >= after.Config.MaxAckPending:
+			add(true, "ack pending %s reached max ack pending %s", f(after.NumAckPending), f(after.Config.MaxAckPending))
+		case ackPendingWarn > 0 && pct >= ackPendingWarn:
+			add(false, "ack pending %s is %d%% of max ack pending %s", f(after.NumAckPending), pct, f(after.Config.MaxAckPending))
+		}
+	}
+
+	return issues
+}
+
+func (c *consumerCmd) checkAction(_ *fisk.ParseContext) error {
+	if c.checkInterval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+
+	c.connectAndSetup(true, false)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	before, err := c.consumerStates(stream)
+	if err != nil {
+		return err
+	}
+
+	if c.checkFormat == "report" {
+		fmt.Printf("Sampling %s consumers on Stream %s over %s\n\n", f(len(before)), stream.Name(), f(c.checkInterval))
+	}
+
+	time.Sleep(c.checkInterval)
+
+	after, err := c.consumerStates(stream)
+	if err != nil {
+		return err
+	}
+
+	names := iu.MapKeys(after)
+	slices.Sort(names)
+
+	var issues []consumerStallIssue
+	stalled := make(map[string]bool)
+	for _, name := range names {
+		prev, ok := before[name]
+		if !ok {
+			continue
+		}
+
+		found := checkConsumerProgress(prev, after[name], c.checkAckPendingWarn)
+		for _, issue := range found {
+			stalled[issue.Consumer] = true
+		}
+		issues = append(issues, found...)
+	}
+
+	if c.checkFormat != "report" {
+		setCheckRenderFormat(c.checkFormat)
+
+		check := &monitor.Result{Name: stream.Name(), Check: "consumer_stalled", NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+		for _, issue := range issues {
+			if issue.Critical {
+				check.Criticalf("%s: %s", issue.Consumer, issue.Problem)
+			} else {
+				check.Warnf("%s: %s", issue.Consumer, issue.Problem)
+			}
+		}
+		check.Pd(
+			&monitor.PerfDataItem{Name: "consumers", Value: float64(len(after)), Help: "The number of consumers that were checked"},
+			&monitor.PerfDataItem{Name: "stalled", Value: float64(len(stalled)), Help: "The number of consumers that appear stalled"},
+		)
+		check.OkIfNoWarningsOrCriticalsf("%d consumers", len(after))
+
+		exitCheck(check, renderCheck(check))
+		return nil
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("No stalled consumers found on Stream %s\n", stream.Name())
+		return nil
+	}
+
+	table := iu.NewTableWriterf(opts(), "Stalled consumers on Stream %s", stream.Name())
+	table.AddHeaders("Consumer", "Severity", "Problem")
+	for _, issue := range issues {
+		severity := "warning"
+		if issue.Critical {
+			severity = "critical"
+		}
+		table.AddRow(issue.Consumer, severity, issue.Problem)
+	}
+	fmt.Println(table.Render())
+
+	return fmt.Errorf("%d of %d consumers on Stream %s appear stalled", len(stalled), len(after), stream.Name())
+}
@@ -16,6 +16,7 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -41,6 +43,7 @@ import (
 	"github.com/nats-io/jsm.go/api"
 	"github.com/nats-io/jsm.go/balancer"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 
 	"github.com/choria-io/fisk"
 
@@ -79,6 +82,8 @@ type consumerCmd struct {
 	deliveryGroup       string
 	pull                bool
 	pullCount           int
+	pullBatch           int
+	nextOutputDir       string
 	replayPolicy        string
 	reportLeaderDistrib bool
 	samplePct           int
@@ -103,33 +108,76 @@ type consumerCmd struct {
 	metadata            map[string]string
 	pauseUntil          string
 
-	dryRun             bool
-	mgr                *jsm.Manager
-	nc                 *nats.Conn
-	nak                bool
-	fPull              bool
-	fPush              bool
-	fBound             bool
-	fWaiting           int
-	fAckPending        int
-	fPending           uint64
-	fIdle              time.Duration
-	fCreated           time.Duration
-	fReplicas          uint
-	fInvert            bool
-	fExpression        string
-	fLeader            string
-	interactive        bool
-	pinnedGroups       []string
-	pinnedTTL          time.Duration
-	overflowGroups     []string
-	prioritizedGroups  []string
-	groupName          string
-	fPinned            bool
-	placementPreferred string
-	apiLevel           int
-	resetSeq           uint64
-	resetSeqIsSet      bool
+	dryRun              bool
+	mgr                 *jsm.Manager
+	nc                  *nats.Conn
+	nak                 bool
+	fPull               bool
+	fPush               bool
+	fBound              bool
+	fWaiting            int
+	fAckPending         int
+	fPending            uint64
+	fIdle               time.Duration
+	fCreated            time.Duration
+	fReplicas           uint
+	fInvert             bool
+	fExpression         string
+	fLeader             string
+	interactive         bool
+	pinnedGroups        []string
+	pinnedTTL           time.Duration
+	overflowGroups      []string
+	prioritizedGroups   []string
+	groupName           string
+	groupMinPending     int64
+	groupMinAckPending  int64
+	fPinned             bool
+	placementPreferred  string
+	apiLevel            int
+	resetSeq            uint64
+	resetSeqIsSet       bool
+	startNew            bool
+	getSeq              uint64
+	watchInterval       time.Duration
+	watchSamples        int
+	watchCount          int
+	targetStream        string
+	targetContext       string
+	filterTransforms    []string
+	fFilterExpr         string
+	reportSort          string
+	reportSortReverse   bool
+	reportTop           int
+	csv                 bool
+	resetSince          time.Duration
+	resetDeliverAll     bool
+	resetRecreate       bool
+	resetWaitClients    time.Duration
+	sampleDuration      time.Duration
+	samplePercent       int
+	sampleEnable        bool
+	graphRecordFile     string
+	graphDuration       time.Duration
+	checkInterval       time.Duration
+	checkAckPendingWarn int
+	checkFormat         string
+	profile             string
+	profileBackoff      []time.Duration
+	subParallel         int
+	subCount            int
+	subOrdered          bool
+	definitionsDir      string
+	eventsPeek          bool
+	eventsCount         int
+	latencyDuration     time.Duration
+	latencySubject      string
+	latencyRate         int
+	drainTimeout        time.Duration
+	drainDelete         bool
+	showProgress        bool
+	stateOutput         string
+	stateCompare        string
 }
 
 func configureConsumerCommand(app commandHost) {
@@ -201,15 +249,21 @@ func configureConsumerCommand(app commandHost) {
 	consAdd.Flag("config", "JSON file to read configuration from").ExistingFileVar(&c.inputFile)
 	consAdd.Flag("validate", "Only validates the configuration against the official Schema").UnNegatableBoolVar(&c.validateOnly)
 	consAdd.Flag("output", "Save configuration instead of creating").PlaceHolder("FILE").StringVar(&c.outFile)
+	consAdd.Flag("profile", "Uses a named profile from the configuration directory as defaults").PlaceHolder("PROFILE").StringVar(&c.profile)
 	addCreateFlags(consAdd, false)
 	consAdd.Flag("defaults", "Accept default values for all prompts").UnNegatableBoolVar(&c.acceptDefaults)
 
 	edit := cons.Command("edit", "Edits the configuration of a consumer").Alias("update").Action(c.editAction)
 	edit.Tag("scope:user", "impact:rw")
+	edit.HelpLong(`Shows the differences between the current and new configuration before editing.
+
+Some settings, like the ack policy or deliver policy, can not be changed on an
+existing consumer. Changing them requires the consumer to be removed and created
+again which loses its delivery state, this is only done when --force is given.`)
 	edit.Arg("stream", "Stream name").StringVar(&c.stream)
 	edit.Arg("consumer", "Consumer name").StringVar(&c.consumer)
 	edit.Flag("config", "JSON file to read configuration from").ExistingFileVar(&c.inputFile)
-	edit.Flag("force", "Force removal without prompting").Short('f').UnNegatableBoolVar(&c.force)
+	edit.Flag("force", "Edit without prompting, recreating the consumer when required").Short('f').UnNegatableBoolVar(&c.force)
 	edit.Flag("interactive", "Edit the configuring using your editor").Short('i').BoolVar(&c.interactive)
 	edit.Flag("dry-run", "Only shows differences, do not edit the stream").UnNegatableBoolVar(&c.dryRun)
 	addCreateFlags(edit, true)
@@ -252,6 +306,20 @@ func configureConsumerCommand(app commandHost) {
 	consState.Arg("consumer", "Consumer name").StringVar(&c.consumer)
 	consState.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
 	consState.Flag("no-select", "Do not select streams from a list").Default("false").UnNegatableBoolVar(&c.force)
+	consState.Flag("output", "Produce a point in time state snapshot in this format, only the redelivered message count is recorded as redelivery maps are not available").PlaceHolder("FORMAT").EnumVar(&c.stateOutput, "json")
+	consState.Flag("compare", "Compares the current state with a snapshot saved using --output json").PlaceHolder("FILE").ExistingFileVar(&c.stateCompare)
+
+	consGet := cons.Command("get", "Retrieves a message by its position in the consumer").Action(c.getAction)
+	consGet.HelpLong(`Retrieves the message the consumer delivers at a given consumer sequence.
+
+The message is found by replaying the stream using the consumer filters and
+start policy, this does not affect the consumer state. Redeliveries are not
+taken into account.`)
+	consGet.Tag("scope:user", "impact:ro")
+	consGet.Arg("stream", "Stream name").Required().StringVar(&c.stream)
+	consGet.Arg("consumer", "Consumer name").Required().StringVar(&c.consumer)
+	consGet.Arg("sequence", "Consumer sequence to retrieve").Required().Uint64Var(&c.getSeq)
+	consGet.Flag("raw", "Show only the message").Short('r').UnNegatableBoolVar(&c.raw)
 
 	consRm := cons.Command("rm", "Removes a consumer").Alias("delete").Alias("del").Action(c.rmAction)
 	consRm.Tag("scope:user", "impact:rw")
@@ -263,14 +331,35 @@ func configureConsumerCommand(app commandHost) {
 	consReset.Tag("scope:user", "impact:rw")
 	consReset.Arg("stream", "Stream name").StringVar(&c.stream)
 	consReset.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	consReset.HelpLong(`Resetting to a sequence uses the server consumer reset API, keeping the consumer
+in place.
+
+Resetting to a point in time using --since, to the start of the stream using
+--deliver-all or when --recreate is given removes the consumer and creates it
+again with identical configuration but a new start position. Clients will have
+to reconnect, use --wait-clients to wait for them to do so.`)
 	consReset.Flag("sequence", "Sequence to reset to").IsSetByUser(&c.resetSeqIsSet).Uint64Var(&c.resetSeq)
+	consReset.Flag("since", "Recreates the consumer delivering messages received since a duration ago").PlaceHolder("DURATION").DurationVar(&c.resetSince)
+	consReset.Flag("deliver-all", "Recreates the consumer delivering all messages in the stream").UnNegatableBoolVar(&c.resetDeliverAll)
+	consReset.Flag("recreate", "Recreates the consumer rather than resetting it in place").UnNegatableBoolVar(&c.resetRecreate)
+	consReset.Flag("wait-clients", "Wait up to this long for clients to reconnect to a recreated consumer").PlaceHolder("DURATION").DurationVar(&c.resetWaitClients)
 	consReset.Flag("force", "Force reset without prompting").Short('f').UnNegatableBoolVar(&c.force)
 
-	consCp := cons.Command("copy", "Creates a new consumer based on the configuration of another").Alias("cp").Action(c.cpAction)
+	consCp := cons.Command("copy", "Creates a new consumer based on the configuration of another").Alias("cp").Alias("clone").Action(c.cpAction)
 	consCp.Tag("scope:user", "impact:rw")
 	consCp.Arg("stream", "Stream name").Required().StringVar(&c.stream)
 	consCp.Arg("source", "Source consumer name").Required().StringVar(&c.consumer)
 	consCp.Arg("destination", "Destination consumer name").Required().StringVar(&c.destination)
+	consCp.HelpLong(`The new consumer is created on the same stream unless --target-stream or
+--target-context are given, allowing definitions to be copied to disaster
+recovery streams in other accounts or clusters.
+
+Filter subjects can be adjusted for the target stream using one or more
+--transform SOURCE:DESTINATION subject transforms, unmatched filters are kept.`)
+	consCp.Flag("start-new", "Deliver only new messages regardless of the source start policy").UnNegatableBoolVar(&c.startNew)
+	consCp.Flag("target-stream", "Creates the consumer on a different stream").PlaceHolder("STREAM").StringVar(&c.targetStream)
+	consCp.Flag("target-context", "Creates the consumer using a different context").PlaceHolder("CONTEXT").StringVar(&c.targetContext)
+	consCp.Flag("transform", "Rewrites filter subjects using a SOURCE:DESTINATION subject transform (pass multiple times)").PlaceHolder("TRANSFORM").StringsVar(&c.filterTransforms)
 	addCreateFlags(consCp, false)
 
 	consNext := cons.Command("next", "Retrieves messages from Pull consumers without interactive prompts").Action(c.nextAction)
@@ -283,6 +372,8 @@ func configureConsumerCommand(app commandHost) {
 	consNext.Flag("raw", "Show only the message").Short('r').UnNegatableBoolVar(&c.raw)
 	consNext.Flag("wait", "Wait up to this period to acknowledge messages").DurationVar(&c.ackWait)
 	consNext.Flag("count", "Number of messages to try to fetch from the pull consumer").Default("1").IntVar(&c.pullCount)
+	consNext.Flag("batch", "Fetch messages in batches of this size").Default("1").IntVar(&c.pullBatch)
+	consNext.Flag("output-dir", "Writes each message and its metadata to files in a directory").PlaceHolder("DIR").StringVar(&c.nextOutputDir)
 
 	consSub := cons.Command("sub", "Retrieves messages from consumers").Action(c.subAction).Hidden()
 	consSub.Tag("scope:user", "impact:ro")
@@ -291,11 +382,64 @@ func configureConsumerCommand(app commandHost) {
 	consSub.Flag("ack", "Acknowledge received message").Default("true").BoolVar(&c.ack)
 	consSub.Flag("raw", "Show only the message").Short('r').UnNegatableBoolVar(&c.raw)
 	consSub.Flag("deliver-group", "Deliver group of the consumer").StringVar(&c.deliveryGroup)
+	consSub.Flag("parallel", "Number of concurrent workers fetching from a Pull consumer").Default("1").IntVar(&c.subParallel)
+	consSub.Flag("count", "Stop after receiving this many messages when using parallel workers or an ordered consumer").PlaceHolder("COUNT").IntVar(&c.subCount)
+	consSub.Flag("wait", "Wait up to this period to acknowledge messages when using parallel workers").DurationVar(&c.ackWait)
+	consSub.Flag("ordered", "Reads the messages using an ordered consumer without modifying the consumer state").UnNegatableBoolVar(&c.subOrdered)
 
 	graph := cons.Command("graph", "View a graph of consumer activity").Action(c.graphAction)
 	graph.Tag("scope:user", "impact:ro")
 	graph.Arg("stream", "Stream name").StringVar(&c.stream)
 	graph.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	graph.Flag("record", "Writes every sampled datapoint to a CSV file").PlaceHolder("FILE").StringVar(&c.graphRecordFile)
+	graph.Flag("duration", "Stops sampling after a period, without a terminal only data is recorded").PlaceHolder("DURATION").DurationVar(&c.graphDuration)
+
+	consWatch := cons.Command("watch", "Continuously shows the lag of consumers").Action(c.watchAction)
+	consWatch.Tag("scope:user", "impact:ro")
+	consWatch.HelpLong(`Shows the unprocessed and pending messages, redeliveries and delivery rate of
+all consumers on a stream, or a single consumer, refreshing regularly.
+
+Consumers whose unprocessed message count grew on every one of the recent
+samples are highlighted.`)
+	consWatch.Arg("stream", "Stream name").StringVar(&c.stream)
+	consWatch.Arg("consumer", "Consumer name, shows all consumers when not set").StringVar(&c.consumer)
+	consWatch.Flag("interval", "How often to refresh the consumer state").Default("5s").DurationVar(&c.watchInterval)
+	consWatch.Flag("samples", "Number of samples of growing lag before highlighting a consumer").Default("3").IntVar(&c.watchSamples)
+	consWatch.Flag("count", "Stop after refreshing this many times").PlaceHolder("COUNT").IntVar(&c.watchCount)
+
+	consCheck := cons.Command("check", "Checks for stalled consumers").Action(c.checkAction)
+	consCheck.Tag("scope:user", "impact:ro")
+	consCheck.HelpLong(`Samples the consumers on a stream twice and reports consumers that appear
+stalled:
+
+  * Consumers redelivering more messages while the ack floor does not move
+  * Pull consumers without waiting pull requests while their backlog grows
+  * Consumers nearing or at their maximum ack pending limit
+
+The result can be rendered as a report or as a monitoring check using --format.`)
+	consCheck.Arg("stream", "Stream name").StringVar(&c.stream)
+	consCheck.Arg("consumer", "Consumer name, checks all consumers when not set").StringVar(&c.consumer)
+	consCheck.Flag("interval", "Time between the two samples").Default("5s").DurationVar(&c.checkInterval)
+	consCheck.Flag("ack-pending-warn", "Warn when ack pending reaches this percentage of max ack pending").Default("80").IntVar(&c.checkAckPendingWarn)
+	consCheck.Flag("format", "Render the result as a report or as a check (report, nagios, json, prometheus, text, zabbix, checkmk)").Default("report").EnumVar(&c.checkFormat, "report", "nagios", "json", "prometheus", "text", "zabbix", "checkmk")
+
+	consSample := cons.Command("sample", "Analyses acknowledgement samples of a consumer").Action(c.sampleAction)
+	consSample.Tag("scope:user", "impact:ro")
+	consSample.HelpLong(`Listens for acknowledgement samples published by the server for a consumer and
+reports on delivery latency, redeliveries and the subjects seen.
+
+When the consumer does not have sampling enabled it is enabled for the duration
+of the analysis and restored afterwards.
+
+Subjects are looked up once sampling completes, messages that were removed from
+the stream by then, like those acknowledged on work queue or interest streams,
+are reported as removed from stream.`)
+	consSample.Arg("stream", "Stream name").StringVar(&c.stream)
+	consSample.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	consSample.Flag("duration", "How long to gather samples for").Default("1m").DurationVar(&c.sampleDuration)
+	consSample.Flag("enable", "Temporarily enable sampling when the consumer is not sampled").Default("true").BoolVar(&c.sampleEnable)
+	consSample.Flag("percent", "Sampling percentage to use when temporarily enabling sampling").Default("100").IntVar(&c.samplePercent)
+	consSample.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
 
 	conPause := cons.Command("pause", "Pause a consumer until a later time").Action(c.pauseAction)
 	conPause.Tag("scope:user", "impact:rw")
@@ -311,17 +455,109 @@ func configureConsumerCommand(app commandHost) {
 	conUnpin.Arg("group", "The group to unpin").StringVar(&c.groupName)
 	conUnpin.Flag("force", "Force unpin without prompting").Short('f').UnNegatableBoolVar(&c.force)
 
+	conGroup := cons.Command("group", "Manage consumer Priority Groups and Pinned Clients")
+	conGroup.HelpLong("Priority Groups are configured using the --pinned-groups, --overflow-groups or --prioritized-groups options when adding a consumer")
+
+	conGroupLs := conGroup.Command("ls", "List Priority Groups and their Pinned Clients").Alias("list").Action(c.groupListAction)
+	conGroupLs.Tag("scope:user", "impact:ro")
+	conGroupLs.Arg("stream", "Stream name").StringVar(&c.stream)
+	conGroupLs.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	conGroupLs.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+
+	conGroupUnpin := conGroup.Command("unpin", "Unpin the current Pinned Client from a Priority Group").Action(c.unpinAction)
+	conGroupUnpin.Tag("scope:user", "impact:rw")
+	conGroupUnpin.Arg("stream", "Stream name").StringVar(&c.stream)
+	conGroupUnpin.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	conGroupUnpin.Arg("group", "The group to unpin").StringVar(&c.groupName)
+	conGroupUnpin.Flag("force", "Force unpin without prompting").Short('f').UnNegatableBoolVar(&c.force)
+
+	conGroupOverflow := conGroup.Command("overflow", "Shows if Overflow clients would currently receive messages").Action(c.groupOverflowAction)
+	conGroupOverflow.Tag("scope:user", "impact:ro")
+	conGroupOverflow.Arg("stream", "Stream name").StringVar(&c.stream)
+	conGroupOverflow.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	conGroupOverflow.Flag("min-pending", "The minimum pending messages overflow clients request").PlaceHolder("MESSAGES").Int64Var(&c.groupMinPending)
+	conGroupOverflow.Flag("min-ack-pending", "The minimum pending acknowledgements overflow clients request").PlaceHolder("MESSAGES").Int64Var(&c.groupMinAckPending)
+	conGroupOverflow.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+
 	conResume := cons.Command("resume", "Resume a paused consumer").Action(c.resumeAction)
 	conResume.Tag("scope:user", "impact:rw")
 	conResume.Arg("stream", "Stream name").StringVar(&c.stream)
 	conResume.Arg("consumer", "Consumer name").StringVar(&c.consumer)
 	conResume.Flag("force", "Force resume without prompting").Short('f').UnNegatableBoolVar(&c.force)
 
+	conEvents := cons.Command("events", "Shows delivery advisories for a consumer").Action(c.eventsAction)
+	conEvents.Tag("scope:user", "impact:ro")
+	conEvents.HelpLong(`Shows a live feed of the negative acknowledgement, terminate and maximum
+deliveries exceeded advisories for a consumer, annotated with the subject of the
+message and how often it was seen before.
+
+When stopped a summary of the messages with the most advisories is shown to help
+identify poison messages.`)
+	conEvents.Arg("stream", "Stream name").StringVar(&c.stream)
+	conEvents.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	conEvents.Flag("peek", "Show the start of the message body for each advisory").UnNegatableBoolVar(&c.eventsPeek)
+	conEvents.Flag("count", "Stop after receiving this many advisories").PlaceHolder("COUNT").IntVar(&c.eventsCount)
+
+	conLatency := cons.Command("latency", "Measures publish to delivery latency through a consumer").Action(c.latencyAction)
+	conLatency.Tag("scope:user", "impact:rw")
+	conLatency.HelpLong(`Publishes timestamped probe messages into the stream on a subject matched by the
+consumer filter and reports the latency between publishing and delivery.
+
+Probes are received through a temporary consumer that is filtered to the probe
+subject and stored and replicated like the measured consumer, messages are not
+taken from the measured consumer. Probe messages remain in the stream and are
+delivered to the clients of the measured consumer.`)
+	conLatency.Arg("stream", "Stream name").StringVar(&c.stream)
+	conLatency.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	conLatency.Flag("duration", "How long to publish probes for").Default("30s").DurationVar(&c.latencyDuration)
+	conLatency.Flag("subject", "The subject to publish probes to, defaults to the consumer filter when it has no wildcards").PlaceHolder("SUBJECT").StringVar(&c.latencySubject)
+	conLatency.Flag("rate", "Probes to publish per second").Default("10").IntVar(&c.latencyRate)
+	conLatency.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+
+	conDrain := cons.Command("drain", "Waits for a Pull consumer to process all messages currently in the stream").Action(c.drainAction)
+	conDrain.Tag("scope:user", "impact:rw")
+	conDrain.HelpLong(`Waits until the acknowledgement floor of a Pull consumer reaches the last
+message in the stream at the time the drain started, optionally deleting the
+consumer once drained.
+
+This is useful when decommissioning workers, stop any new publishing first to
+ensure the drain completes.`)
+	conDrain.Arg("stream", "Stream name").StringVar(&c.stream)
+	conDrain.Arg("consumer", "Consumer name").StringVar(&c.consumer)
+	conDrain.Flag("max-wait", "How long to wait for the consumer to drain").Default("5m").DurationVar(&c.drainTimeout)
+	conDrain.Flag("delete", "Deletes the consumer once drained").UnNegatableBoolVar(&c.drainDelete)
+	conDrain.Flag("force", "Delete the consumer without prompting").Short('f').UnNegatableBoolVar(&c.force)
+	conDrain.Flag("progress", "Enables or disables progress reporting using a progress bar").Default("true").BoolVar(&c.showProgress)
+
+	conBackup := cons.Command("backup", "Saves the configuration of all consumers on a stream").Action(c.backupDefinitionsAction)
+	conBackup.Tag("scope:user", "impact:ro")
+	conBackup.HelpLong(`Writes the configuration of every durable consumer on a stream to a JSON file
+per consumer in the target directory. Only configuration is saved, delivery
+state is not.
+
+Use 'nats consumer restore' to create the consumers again, for example after
+restoring a stream backup into a new cluster.`)
+	conBackup.Arg("stream", "Stream name").Required().StringVar(&c.stream)
+	conBackup.Arg("target", "Directory to write consumer configurations to").Required().StringVar(&c.definitionsDir)
+
+	conRestore := cons.Command("restore", "Creates consumers from configurations saved using backup").Action(c.restoreDefinitionsAction)
+	conRestore.Tag("scope:user", "impact:rw")
+	conRestore.HelpLong(`Creates a consumer for every JSON configuration in a directory written by
+'nats consumer backup', consumers that already exist are left unchanged.`)
+	conRestore.Arg("stream", "Stream name").Required().StringVar(&c.stream)
+	conRestore.Arg("source", "Directory holding consumer configurations").Required().ExistingDirVar(&c.definitionsDir)
+
 	conReport := cons.Command("report", "Reports on consumer statistics").Action(c.reportAction)
 	conReport.Tag("scope:user", "impact:ro")
 	conReport.Arg("stream", "Stream name").StringVar(&c.stream)
 	conReport.Flag("raw", "Show un-formatted numbers").Short('r').UnNegatableBoolVar(&c.raw)
 	conReport.Flag("leaders", "Show details about the leaders").Short('l').UnNegatableBoolVar(&c.reportLeaderDistrib)
+	conReport.Flag("sort", "Sorts by name or, largest first, by ack-pending, unprocessed or redelivered").Default("name").EnumVar(&c.reportSort, "name", "ack-pending", "unprocessed", "redelivered")
+	conReport.Flag("reverse", "Reverse the sort order").Short('R').UnNegatableBoolVar(&c.reportSortReverse)
+	conReport.Flag("top", "Limit the report to the first N consumers").PlaceHolder("N").IntVar(&c.reportTop)
+	conReport.Flag("filter-expr", "Include only consumers matching an expression like 'consumer find --expression', supports duration and size literals").PlaceHolder("EXPRESSION").StringVar(&c.fFilterExpr)
+	conReport.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+	conReport.Flag("csv", "Produce CSV output").UnNegatableBoolVar(&c.csv)
 
 	conCluster := cons.Command("cluster", "Manages a clustered consumer").Alias("c")
 
@@ -355,8 +591,27 @@ func init() {
 }
 
 func (c *consumerCmd) resetAction(_ *fisk.ParseContext) error {
+	positions := 0
+	for _, set := range []bool{c.resetSeqIsSet, c.resetSince > 0, c.resetDeliverAll} {
+		if set {
+			positions++
+		}
+	}
+	if positions > 1 {
+		return fmt.Errorf("--sequence, --since and --deliver-all are mutually exclusive")
+	}
+
+	recreate := c.resetRecreate || c.resetSince > 0 || c.resetDeliverAll
+	if c.resetWaitClients > 0 && !recreate {
+		return fmt.Errorf("--wait-clients requires the consumer to be recreated")
+	}
+
 	c.connectAndSetup(true, true)
 
+	if recreate {
+		return c.recreateConsumer()
+	}
+
 	if !c.force {
 		ok, err := askConfirmation("Really reset the consumer", false)
 		fisk.FatalIfError(err, "could not obtain confirmation")
@@ -377,6 +632,109 @@ func (c *consumerCmd) resetAction(_ *fisk.ParseContext) error {
 	return nil
 }
 
+// recreateConsumer removes the selected consumer and creates it with the same configuration and a new start position
+func (c *consumerCmd) recreateConsumer() error {
+	cfg := c.selectedConsumer.Configuration()
+	cfg.OptStartSeq = 0
+	cfg.OptStartTime = nil
+
+	switch {
+	case c.resetSince > 0:
+		c.setStartPolicy(&cfg, c.resetSince.String())
+	case c.resetSeqIsSet:
+		c.setStartPolicy(&cfg, strconv.FormatUint(c.resetSeq, 10))
+	default:
+		c.setStartPolicy(&cfg, "all")
+	}
+
+	if !c.force {
+		ok, err := askConfirmation(fmt.Sprintf("Really remove and recreate Consumer %s > %s, connected clients will be interrupted", c.stream, c.consumer), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	consumer, err := c.replaceConsumer(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Recreated Consumer %s > %s with deliver policy %s\n", c.stream, c.consumer, consumer.DeliverPolicy())
+
+	if c.resetWaitClients > 0 {
+		fmt.Printf("Waiting up to %s for clients to reconnect\n", f(c.resetWaitClients))
+		if c.waitConsumerClients(consumer) {
+			fmt.Println("Clients reconnected")
+		} else {
+			fmt.Printf("No clients reconnected within %s\n", f(c.resetWaitClients))
+		}
+	}
+
+	fmt.Println()
+
+	c.showStateOnly = true
+	c.showConsumer(consumer)
+
+	return nil
+}
+
+// replaceConsumer removes the selected consumer and creates it again using cfg
+func (c *consumerCmd) replaceConsumer(cfg api.ConsumerConfig) (*jsm.Consumer, error) {
+	// keep a copy of the configuration so the consumer can be restored by hand should creation fail
+	backup, err := os.CreateTemp("", fmt.Sprintf("%s-%s-*.json", c.stream, c.consumer))
+	if err != nil {
+		return nil, err
+	}
+	defer backup.Close()
+
+	cj, err := json.MarshalIndent(c.selectedConsumer.Configuration(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	_, err = backup.Write(cj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.selectedConsumer.Delete()
+	if err != nil {
+		os.Remove(backup.Name())
+		return nil, fmt.Errorf("could not remove consumer: %w", err)
+	}
+
+	consumer, err := c.mgr.NewConsumerFromDefault(c.stream, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not recreate consumer, the original configuration was saved in %s: %w", backup.Name(), err)
+	}
+	os.Remove(backup.Name())
+
+	return consumer, nil
+}
+
+// waitConsumerClients waits for a push subscriber to bind or a pull request to arrive
+func (c *consumerCmd) waitConsumerClients(consumer *jsm.Consumer) bool {
+	ctx, cancel := context.WithTimeout(ctx, c.resetWaitClients)
+	defer cancel()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		state, err := consumer.State()
+		if err == nil && (state.PushBound || state.NumWaiting > 0) {
+			return true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
 func (c *consumerCmd) unpinAction(_ *fisk.ParseContext) error {
 	c.connectAndSetup(true, true)
 
@@ -504,17 +862,23 @@ func (c *consumerCmd) findAction(_ *fisk.ParseContext) error {
 }
 
 func (c *consumerCmd) graphAction(_ *fisk.ParseContext) error {
-	if !iu.IsTerminal() {
-		return fmt.Errorf("can only graph data on an interactive terminal")
+	headless := !iu.IsTerminal()
+	if headless && c.graphRecordFile == "" {
+		return fmt.Errorf("can only graph data on an interactive terminal, use --record to capture data without one")
 	}
 
-	width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
-	if err != nil {
-		return fmt.Errorf("failed to get terminal dimensions: %w", err)
-	}
+	var width, height int
+	var err error
 
-	if width < 20 || height < 20 {
-		return fmt.Errorf("please increase terminal dimensions")
+	if !headless {
+		width, height, err = terminal.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to get terminal dimensions: %w", err)
+		}
+
+		if width < 20 || height < 20 {
+			return fmt.Errorf("please increase terminal dimensions")
+		}
 	}
 
 	c.connectAndSetup(true, true)
@@ -527,6 +891,27 @@ func (c *consumerCmd) graphAction(_ *fisk.ParseContext) error {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
 
+	if c.graphDuration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.graphDuration)
+		defer cancel()
+	}
+
+	var recorder *csv.Writer
+	if c.graphRecordFile != "" {
+		rf, err := os.Create(c.graphRecordFile)
+		if err != nil {
+			return err
+		}
+		defer rf.Close()
+
+		recorder = csv.NewWriter(rf)
+		err = recorder.Write([]string{"time", "delivered_rate", "acked_rate", "ack_pending", "num_pending", "redelivered"})
+		if err != nil {
+			return err
+		}
+		recorder.Flush()
+	}
+
 	nfo, err := consumer.State()
 	if err != nil {
 		return err
@@ -553,25 +938,28 @@ func (c *consumerCmd) graphAction(_ *fisk.ParseContext) error {
 	lastAckedSeq := nfo.AckFloor.Consumer
 	lastDeliveredSeq := nfo.Delivered.Consumer
 	lastStateTs := time.Now()
+	var samples int
 
 	ticker := time.NewTicker(time.Second)
 	for {
 		select {
 		case <-ticker.C:
-			width, height, err = terminal.GetSize(int(os.Stdout.Fd()))
-			if err != nil {
-				height = 40
-				width = 80
-			}
-			if width > 15 {
-				width -= 10
-			}
-			if height > 10 {
-				height -= 5
-			}
+			if !headless {
+				width, height, err = terminal.GetSize(int(os.Stdout.Fd()))
+				if err != nil {
+					height = 40
+					width = 80
+				}
+				if width > 15 {
+					width -= 10
+				}
+				if height > 10 {
+					height -= 5
+				}
 
-			if width < 20 || height < 20 {
-				return fmt.Errorf("please increase terminal dimensions")
+				if width < 20 || height < 20 {
+					return fmt.Errorf("please increase terminal dimensions")
+				}
 			}
 
 			nfo, err := consumer.State()
@@ -579,14 +967,42 @@ func (c *consumerCmd) graphAction(_ *fisk.ParseContext) error {
 				continue
 			}
 
-			deliveredRates = append(deliveredRates, calculateRate(float64(nfo.Delivered.Consumer), float64(lastDeliveredSeq), time.Since(lastStateTs)))
-			ackedRates = append(ackedRates, calculateRate(float64(nfo.AckFloor.Consumer), float64(lastAckedSeq), time.Since(lastStateTs)))
-			unprocessedMessages = append(unprocessedMessages, float64(nfo.NumPending))
-			outstandingMessages = append(outstandingMessages, float64(nfo.NumAckPending))
+			deliveredRate := calculateRate(float64(nfo.Delivered.Consumer), float64(lastDeliveredSeq), time.Since(lastStateTs))
+			ackedRate := calculateRate(float64(nfo.AckFloor.Consumer), float64(lastAckedSeq), time.Since(lastStateTs))
+
 			lastDeliveredSeq = nfo.Delivered.Consumer
 			lastAckedSeq = nfo.AckFloor.Consumer
 			lastStateTs = time.Now()
 
+			if recorder != nil {
+				err = recorder.Write([]string{
+					lastStateTs.UTC().Format(time.RFC3339),
+					strconv.FormatFloat(deliveredRate, 'f', 2, 64),
+					strconv.FormatFloat(ackedRate, 'f', 2, 64),
+					strconv.Itoa(nfo.NumAckPending),
+					strconv.FormatUint(nfo.NumPending, 10),
+					strconv.Itoa(nfo.NumRedelivered),
+				})
+				if err != nil {
+					return err
+				}
+				recorder.Flush()
+				if recorder.Error() != nil {
+					return recorder.Error()
+				}
+				samples++
+			}
+
+			// without a terminal nothing is plotted so the graph data is not kept
+			if headless {
+				continue
+			}
+
+			deliveredRates = append(deliveredRates, deliveredRate)
+			ackedRates = append(ackedRates, ackedRate)
+			unprocessedMessages = append(unprocessedMessages, float64(nfo.NumPending))
+			outstandingMessages = append(outstandingMessages, float64(nfo.NumAckPending))
+
 			deliveredRates = resizeData(deliveredRates, width)
 			ackedRates = resizeData(ackedRates, width)
 			unprocessedMessages = resizeData(unprocessedMessages, width)
@@ -641,7 +1057,12 @@ func (c *consumerCmd) graphAction(_ *fisk.ParseContext) error {
 			fmt.Println(deliveredPlot)
 
 		case <-ctx.Done():
-			iu.ClearScreen()
+			if !headless {
+				iu.ClearScreen()
+			}
+			if recorder != nil {
+				fmt.Printf("Recorded %s samples to %s\n", f(samples), c.graphRecordFile)
+			}
 			return nil
 		}
 	}
@@ -976,8 +1397,7 @@ func (c *consumerCmd) editAction(pc *fisk.ParseContext) error {
 	t.Metadata = iu.RemoveReservedMetadata(t.Metadata)
 	ncfg.Metadata = iu.RemoveReservedMetadata(ncfg.Metadata)
 
-	diff := cmp.Diff(t, *ncfg, sorter)
-	if diff == "" {
+	if cmp.Diff(t, *ncfg, sorter) == "" {
 		if !c.dryRun {
 			fmt.Println("No difference in configuration")
 		}
@@ -985,11 +1405,35 @@ func (c *consumerCmd) editAction(pc *fisk.ParseContext) error {
 		return nil
 	}
 
-	fmt.Printf("Differences (-old +new):\n%s", diff)
+	oj, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	nj, err := json.MarshalIndent(ncfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(iu.UnifiedDiff("current", "new", string(oj), string(nj), 3))
+	fmt.Println()
+
+	inPlace, recreate := consumerConfigChanges(t, *ncfg)
+	if len(inPlace) > 0 {
+		fmt.Printf("Updated in place: %s\n", strings.Join(inPlace, ", "))
+	}
+	if len(recreate) > 0 {
+		fmt.Printf("Requires recreating the consumer: %s\n", strings.Join(recreate, ", "))
+	}
+	fmt.Println()
+
 	if c.dryRun {
 		os.Exit(1)
 	}
 
+	if len(recreate) > 0 && !c.force {
+		return fmt.Errorf("changing %s requires recreating Consumer %s > %s which loses its delivery state, use --force to recreate it", strings.Join(recreate, ", "), c.stream, c.consumer)
+	}
+
 	if !c.force {
 		ok, err := askConfirmation(fmt.Sprintf("Really edit Consumer %s > %s", c.stream, c.consumer), false)
 		fisk.FatalIfError(err, "could not obtain confirmation")
@@ -1004,9 +1448,18 @@ func (c *consumerCmd) editAction(pc *fisk.ParseContext) error {
 		return err
 	}
 
-	cons, err := c.mgr.NewConsumerFromDefault(c.stream, *ncfg)
-	if err != nil {
-		return err
+	var cons *jsm.Consumer
+	if len(recreate) > 0 {
+		cons, err = c.replaceConsumer(*ncfg)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recreated Consumer %s > %s\n\n", c.stream, c.consumer)
+	} else {
+		cons, err = c.mgr.NewConsumerFromDefault(c.stream, *ncfg)
+		if err != nil {
+			return err
+		}
 	}
 
 	c.showConsumer(cons)
@@ -1014,6 +1467,43 @@ func (c *consumerCmd) editAction(pc *fisk.ParseContext) error {
 	return nil
 }
 
+// consumerConfigChanges lists the changed configuration keys that can be updated in place and those that require the consumer to be recreated
+func consumerConfigChanges(current api.ConsumerConfig, updated api.ConsumerConfig) (inPlace []string, recreate []string) {
+	immutable := []string{"name", "durable_name", "deliver_policy", "opt_start_seq", "opt_start_time", "ack_policy", "replay_policy", "idle_heartbeat", "flow_control", "max_waiting"}
+
+	var ckeys, ukeys map[string]json.RawMessage
+	cj, _ := json.Marshal(current)
+	json.Unmarshal(cj, &ckeys)
+	uj, _ := json.Marshal(updated)
+	json.Unmarshal(uj, &ukeys)
+
+	keys := iu.MapKeys(ckeys)
+	for k := range ukeys {
+		if _, ok := ckeys[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if bytes.Equal(ckeys[k], ukeys[k]) {
+			continue
+		}
+
+		switch {
+		case slices.Contains(immutable, k):
+			recreate = append(recreate, k)
+		case k == "deliver_subject" && (current.DeliverSubject == "" || updated.DeliverSubject == ""):
+			// switching between push and pull
+			recreate = append(recreate, k)
+		default:
+			inPlace = append(inPlace, k)
+		}
+	}
+
+	return inPlace, recreate
+}
+
 func (c *consumerCmd) backoffPolicy() ([]time.Duration, error) {
 	if c.backoffMode == "none" {
 		return nil, nil
@@ -1357,6 +1847,10 @@ func (c *consumerCmd) showInfo(config api.ConsumerConfig, state api.ConsumerInfo
 }
 
 func (c *consumerCmd) stateAction(pc *fisk.ParseContext) error {
+	if c.stateOutput != "" || c.stateCompare != "" {
+		return c.stateSnapshotAction(pc)
+	}
+
 	c.showStateOnly = true
 	return c.infoAction(pc)
 }
@@ -1467,14 +1961,25 @@ func (c *consumerCmd) cpAction(pc *fisk.ParseContext) (err error) {
 		cfg.SampleFrequency = c.sampleFreqFromInt(c.samplePct)
 	}
 
+	if c.startNew {
+		if c.startPolicy != "" {
+			return fmt.Errorf("--start-new and --deliver are mutually exclusive")
+		}
+		c.startPolicy = "new"
+	}
+
 	if c.startPolicy != "" {
+		cfg.OptStartSeq = 0
+		cfg.OptStartTime = nil
 		c.setStartPolicy(&cfg, c.startPolicy)
 	}
 
 	if c.ephemeral {
 		cfg.Durable = ""
+		cfg.Name = ""
 	} else {
 		cfg.Durable = c.destination
+		cfg.Name = c.destination
 	}
 
 	if c.delivery != "" {
@@ -1570,13 +2075,42 @@ func (c *consumerCmd) cpAction(pc *fisk.ParseContext) (err error) {
 		cfg.HeadersOnly = c.hdrsOnly
 	}
 
-	consumer, err := c.mgr.NewConsumerFromDefault(c.stream, cfg)
+	if len(c.filterTransforms) > 0 {
+		transforms, err := parseSubjectTransforms(c.filterTransforms)
+		if err != nil {
+			return err
+		}
+
+		cfg.FilterSubject, _ = transformSubject(transforms, cfg.FilterSubject)
+		for i, subj := range cfg.FilterSubjects {
+			cfg.FilterSubjects[i], _ = transformSubject(transforms, subj)
+		}
+	}
+
+	mgr := c.mgr
+	if c.targetContext != "" {
+		tnc, tmgr, _, err := connectToContext(c.targetContext)
+		if err != nil {
+			return fmt.Errorf("could not connect to context %s: %w", c.targetContext, err)
+		}
+		defer tnc.Close()
+
+		mgr = tmgr
+	}
+
+	stream := c.stream
+	if c.targetStream != "" {
+		stream = c.targetStream
+	}
+
+	consumer, err := mgr.NewConsumerFromDefault(stream, cfg)
 	fisk.FatalIfError(err, "Consumer creation failed")
 
 	if cfg.Durable == "" {
 		return nil
 	}
 
+	c.stream = stream
 	c.consumer = cfg.Durable
 
 	c.showConsumer(consumer)
@@ -1865,23 +2399,27 @@ func (c *consumerCmd) prepareConfig() (cfg *api.ConsumerConfig, err error) {
 	}
 	cfg.HeadersOnly = c.hdrsOnly
 
-	if cfg.AckPolicy != api.AckNone && !c.acceptDefaults && c.backoffMode == "" {
+	if cfg.AckPolicy != api.AckNone && !c.acceptDefaults && c.backoffMode == "" && len(c.profileBackoff) == 0 {
 		err = c.askBackoffPolicy()
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if c.backoffMode == "" && len(c.profileBackoff) > 0 {
+		cfg.BackOff = c.profileBackoff
+	}
+
 	if c.backoffMode != "" {
 		cfg.BackOff, err = c.backoffPolicy()
 		if err != nil {
 			return nil, fmt.Errorf("could not determine backoff policy: %v", err)
 		}
+	}
 
-		// hopefully this is just to work around a temporary bug in the server
-		if c.maxDeliver == -1 && len(cfg.BackOff) > 0 {
-			c.maxDeliver = len(cfg.BackOff) + 1
-		}
+	// hopefully this is just to work around a temporary bug in the server
+	if c.maxDeliver == -1 && len(cfg.BackOff) > 0 {
+		c.maxDeliver = len(cfg.BackOff) + 1
 	}
 
 	if c.maxAckPending == -1 {
@@ -1943,7 +2481,7 @@ func (c *consumerCmd) prepareConfig() (cfg *api.ConsumerConfig, err error) {
 		cfg.PinnedTTL = c.pinnedTTL
 	case len(c.overflowGroups) > 0:
 		cfg.PriorityPolicy = api.PriorityOverflow
-		cfg.PriorityGroups = c.pinnedGroups
+		cfg.PriorityGroups = c.overflowGroups
 	case len(c.prioritizedGroups) > 0:
 		cfg.PriorityPolicy = api.PriorityPrioritized
 		cfg.PriorityGroups = c.prioritizedGroups
@@ -2132,6 +2670,17 @@ func (c *consumerCmd) validateCfg(cfg *api.ConsumerConfig) (bool, []byte, []stri
 }
 
 func (c *consumerCmd) createAction(pc *fisk.ParseContext) (err error) {
+	if c.profile != "" {
+		if c.inputFile != "" {
+			return fmt.Errorf("--profile and --config cannot be combined")
+		}
+
+		err = c.applyConsumerProfile(c.profile)
+		if err != nil {
+			return err
+		}
+	}
+
 	c.connectAndSetup(true, false)
 	cfg, err := c.prepareConfig()
 	if err != nil {
@@ -2225,6 +2774,22 @@ func (c *consumerCmd) getNextMsgDirect(stream string, consumer string) error {
 		}
 	}
 
+	c.checkNextAckFlags()
+
+	msg, err := sub.NextMsg(opts().Timeout)
+	if err != nil {
+		fatalIfNotPull()
+	}
+	fisk.FatalIfError(err, "no message received")
+
+	if msg.Header != nil && msg.Header.Get("Status") == "503" {
+		fatalIfNotPull()
+	}
+
+	return c.handleNextMsg(msg)
+}
+
+func (c *consumerCmd) checkNextAckFlags() {
 	if c.term {
 		if !c.ackSetByUser {
 			c.ack = false
@@ -2238,18 +2803,17 @@ func (c *consumerCmd) getNextMsgDirect(stream string, consumer string) error {
 			fisk.Fatalf("can not both Acknowledge and NaK message")
 		}
 	}
+}
 
-	msg, err := sub.NextMsg(opts().Timeout)
-	if err != nil {
-		fatalIfNotPull()
-	}
-	fisk.FatalIfError(err, "no message received")
-
-	if msg.Header != nil && msg.Header.Get("Status") == "503" {
-		fatalIfNotPull()
-	}
+func (c *consumerCmd) handleNextMsg(msg *nats.Msg) error {
+	var err error
 
-	if !c.raw {
+	if c.nextOutputDir != "" {
+		err = c.writeNextMsg(msg)
+		if err != nil {
+			return err
+		}
+	} else if !c.raw {
 		info, err := jsm.ParseJSMsgMetadata(msg)
 		if err != nil {
 			if msg.Reply == "" {
@@ -2287,7 +2851,9 @@ func (c *consumerCmd) getNextMsgDirect(stream string, consumer string) error {
 		err = msg.Term()
 		fisk.FatalIfError(err, "could not Terminate message")
 		c.nc.Flush()
-		fmt.Println("\nTerminated message")
+		if c.nextOutputDir == "" {
+			fmt.Println("\nTerminated message")
+		}
 	}
 
 	if c.ack || c.nak {
@@ -2314,7 +2880,7 @@ func (c *consumerCmd) getNextMsgDirect(stream string, consumer string) error {
 		fisk.FatalIfError(err, "could not Acknowledge message")
 		c.nc.Flush()
 
-		if !c.raw {
+		if !c.raw && c.nextOutputDir == "" {
 			neg := ""
 			if c.nak {
 				neg = "Negative "
@@ -2431,7 +2997,19 @@ func (c *consumerCmd) subAction(_ *fisk.ParseContext) error {
 		c.ack = false
 	}
 
+	if c.subParallel > 1 && !consumer.IsPullMode() {
+		return fmt.Errorf("parallel workers require a Pull consumer")
+	}
+
+	if c.subParallel > 1 && c.subOrdered {
+		return fmt.Errorf("parallel workers can not be used with an ordered consumer")
+	}
+
 	switch {
+	case c.subOrdered:
+		return c.orderedSubscribe(consumer)
+	case consumer.IsPullMode() && c.subParallel > 1:
+		return c.parallelSubscribe(consumer)
 	case consumer.IsPullMode():
 		return c.getNextMsgDirect(consumer.StreamName(), consumer.Name())
 	case consumer.IsPushMode():
@@ -2441,11 +3019,146 @@ func (c *consumerCmd) subAction(_ *fisk.ParseContext) error {
 	}
 }
 
+// orderedConsumerConfig creates an ordered consumer configuration that sees the same messages as the consumer described by nfo
+func orderedConsumerConfig(nfo api.ConsumerInfo) (jetstream.OrderedConsumerConfig, error) {
+	cfg := nfo.Config
+	ocfg := jetstream.OrderedConsumerConfig{
+		FilterSubjects: cfg.FilterSubjects,
+		DeliverPolicy:  jetstream.DeliverPolicy(cfg.DeliverPolicy),
+		OptStartSeq:    cfg.OptStartSeq,
+		OptStartTime:   cfg.OptStartTime,
+		HeadersOnly:    cfg.HeadersOnly,
+	}
+	if cfg.FilterSubject != "" {
+		ocfg.FilterSubjects = []string{cfg.FilterSubject}
+	}
+
+	switch cfg.DeliverPolicy {
+	case api.DeliverNew:
+		// new messages are those received after the consumer was created
+		ocfg.DeliverPolicy = jetstream.DeliverByStartTimePolicy
+		ocfg.OptStartTime = &nfo.Created
+	case api.DeliverLast:
+		return ocfg, fmt.Errorf("consumers delivering from the last message are not supported")
+	}
+
+	return ocfg, nil
+}
+
+func (c *consumerCmd) getAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, true)
+
+	if c.getSeq == 0 {
+		return fmt.Errorf("sequence must be greater than 0")
+	}
+
+	nfo, err := c.selectedConsumer.LatestState()
+	if err != nil {
+		return err
+	}
+
+	ocfg, err := orderedConsumerConfig(nfo)
+	if err != nil {
+		return err
+	}
+
+	js, err := newJetStreamWithOptions(c.nc, opts())
+	if err != nil {
+		return err
+	}
+
+	cons, err := js.OrderedConsumer(ctx, c.stream, ocfg)
+	if err != nil {
+		return err
+	}
+
+	var seen, lastSeq uint64
+	for seen < c.getSeq {
+		batch := int(min(c.getSeq-seen, 1000))
+		msgs, err := cons.FetchNoWait(batch)
+		if err != nil {
+			return err
+		}
+
+		var received int
+		for msg := range msgs.Messages() {
+			received++
+
+			// the ordered consumer might replay messages when it recreates itself
+			meta, err := msg.Metadata()
+			if err != nil {
+				return err
+			}
+			if meta.Sequence.Stream <= lastSeq {
+				continue
+			}
+			lastSeq = meta.Sequence.Stream
+			seen++
+
+			if seen == c.getSeq {
+				return c.renderConsumerMsg(msg)
+			}
+		}
+
+		if msgs.Error() != nil {
+			return msgs.Error()
+		}
+
+		// a short batch means the end of the stream was reached
+		if received < batch {
+			break
+		}
+	}
+
+	return fmt.Errorf("consumer %s > %s has no message at sequence %d", c.stream, c.consumer, c.getSeq)
+}
+
+func (c *consumerCmd) renderConsumerMsg(msg jetstream.Msg) error {
+	if c.raw {
+		fmt.Println(string(msg.Data()))
+		return nil
+	}
+
+	meta, err := msg.Metadata()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Item: %s > %s#%d received %v (%s) on Subject %s\n\n", c.stream, c.consumer, c.getSeq, meta.Timestamp, f(time.Since(meta.Timestamp)), msg.Subject())
+	fmt.Printf("  Stream Sequence: %d\n", meta.Sequence.Stream)
+	fmt.Printf("Consumer Sequence: %d\n\n", c.getSeq)
+
+	if len(msg.Headers()) > 0 {
+		fmt.Println("Headers:")
+		for k, vals := range msg.Headers() {
+			for _, val := range vals {
+				fmt.Printf("  %s: %s\n", k, val)
+			}
+		}
+		fmt.Println()
+	}
+
+	outPutMSGBody(msg.Data(), "", msg.Subject(), c.stream)
+
+	return nil
+}
+
 func (c *consumerCmd) nextAction(_ *fisk.ParseContext) error {
 	c.connectAndSetup(false, false, nats.UseOldRequestStyle())
 
 	var err error
 
+	if c.nextOutputDir != "" {
+		err = os.MkdirAll(c.nextOutputDir, 0700)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.pullBatch > 1 {
+		return c.getNextMsgBatches(c.stream, c.consumer)
+	}
+
 	for i := 0; i < c.pullCount; i++ {
 		err = c.getNextMsgDirect(c.stream, c.consumer)
 		if err != nil {
@@ -2482,6 +3195,10 @@ func (c *consumerCmd) connectAndSetup(askStream bool, askConsumer bool, opts ...
 }
 
 func (c *consumerCmd) reportAction(_ *fisk.ParseContext) error {
+	if c.json && c.csv {
+		return fmt.Errorf("--json and --csv are mutually exclusive")
+	}
+
 	c.connectAndSetup(true, false)
 
 	s, err := c.mgr.LoadStream(c.stream)
@@ -2494,22 +3211,28 @@ func (c *consumerCmd) reportAction(_ *fisk.ParseContext) error {
 		return err
 	}
 
+	var matched map[string]bool
+	if c.fFilterExpr != "" {
+		matched, err = consumersMatchingExpression(s, c.fFilterExpr)
+		if err != nil {
+			return err
+		}
+	}
+
 	leaders := make(map[string]*raftLeader)
+	states := []api.ConsumerInfo{}
 
-	table := iu.NewTableWriterf(opts(), "Consumer report for %s with %s consumers", c.stream, f(ss.Consumers))
-	table.AddHeaders("Consumer", "Mode", "Ack Policy", "Ack Wait", "Ack Pending", "Redelivered", "Unprocessed", "Ack Floor", "API Level", "Cluster")
 	missing, offline, err := s.EachConsumer(func(cons *jsm.Consumer) {
+		if matched != nil && !matched[cons.Name()] {
+			return
+		}
+
 		cs, err := cons.LatestState()
 		if err != nil {
 			log.Printf("Could not obtain consumer state for %s: %s", cons.Name(), err)
 			return
 		}
 
-		mode := "Push"
-		if cons.IsPullMode() {
-			mode = "Pull"
-		}
-
 		if cs.Cluster != nil {
 			if cs.Cluster.Leader != "" {
 				_, ok := leaders[cs.Cluster.Leader]
@@ -2520,13 +3243,32 @@ func (c *consumerCmd) reportAction(_ *fisk.ParseContext) error {
 			}
 		}
 
-		apiLevel := cs.Config.Metadata[api.JsMetaRequiredServerLevel]
-		if apiLevel == "" {
-			apiLevel = "0"
-		}
+		states = append(states, cs)
+	})
+	if err != nil {
+		return err
+	}
+
+	sortConsumerReport(states, c.reportSort, c.reportSortReverse)
+	if c.reportTop > 0 && len(states) > c.reportTop {
+		states = states[:c.reportTop]
+	}
+
+	switch {
+	case c.json:
+		return iu.PrintJSON(states)
+	case c.csv:
+		return renderConsumerReportCSV(os.Stdout, states)
+	}
+
+	table := iu.NewTableWriterf(opts(), "Consumer report for %s with %s consumers", c.stream, f(len(states)))
+	table.AddHeaders("Consumer", "Mode", "Ack Policy", "Ack Wait", "Ack Pending", "Redelivered", "Unprocessed", "Ack Floor", "API Level", "Cluster")
+	for _, cs := range states {
+		mode := consumerReportMode(cs)
+		apiLevel := consumerReportAPILevel(cs)
 
 		if c.raw {
-			table.AddRow(cons.Name(), mode, cons.AckPolicy().String(), cons.AckWait(), cs.NumAckPending, cs.NumRedelivered, cs.NumPending, cs.AckFloor.Stream, apiLevel, renderCluster(cs.Cluster))
+			table.AddRow(cs.Name, mode, cs.Config.AckPolicy.String(), cs.Config.AckWait, cs.NumAckPending, cs.NumRedelivered, cs.NumPending, cs.AckFloor.Stream, apiLevel, renderCluster(cs.Cluster))
 		} else {
 			unprocessed := "0"
 			if cs.NumPending > 0 {
@@ -2537,11 +3279,8 @@ func (c *consumerCmd) reportAction(_ *fisk.ParseContext) error {
 				unprocessed = fmt.Sprintf("%s / %0.0f%%", f(cs.NumPending), upct)
 			}
 
-			table.AddRow(cons.Name(), mode, cons.AckPolicy().String(), f(cons.AckWait()), f(cs.NumAckPending), f(cs.NumRedelivered), unprocessed, f(cs.AckFloor.Stream), apiLevel, renderCluster(cs.Cluster))
+			table.AddRow(cs.Name, mode, cs.Config.AckPolicy.String(), f(cs.Config.AckWait), f(cs.NumAckPending), f(cs.NumRedelivered), unprocessed, f(cs.AckFloor.Stream), apiLevel, renderCluster(cs.Cluster))
 		}
-	})
-	if err != nil {
-		return err
 	}
 
 	fmt.Println(table.Render())
@@ -2557,6 +3296,109 @@ func (c *consumerCmd) reportAction(_ *fisk.ParseContext) error {
 	return nil
 }
 
+func consumerReportMode(cs api.ConsumerInfo) string {
+	if cs.Config.DeliverSubject == "" {
+		return "Pull"
+	}
+
+	return "Push"
+}
+
+func consumerReportAPILevel(cs api.ConsumerInfo) string {
+	apiLevel := cs.Config.Metadata[api.JsMetaRequiredServerLevel]
+	if apiLevel == "" {
+		apiLevel = "0"
+	}
+
+	return apiLevel
+}
+
+// sortConsumerReport sorts states by name or, largest first, by one of the counters
+func sortConsumerReport(states []api.ConsumerInfo, by string, reverse bool) {
+	value := func(cs api.ConsumerInfo) uint64 {
+		switch by {
+		case "ack-pending":
+			return uint64(cs.NumAckPending)
+		case "unprocessed":
+			return cs.NumPending
+		case "redelivered":
+			return uint64(cs.NumRedelivered)
+		default:
+			return 0
+		}
+	}
+
+	sort.SliceStable(states, func(i, j int) bool {
+		vi, vj := value(states[i]), value(states[j])
+		if vi == vj {
+			if reverse {
+				return states[i].Name > states[j].Name
+			}
+			return states[i].Name < states[j].Name
+		}
+
+		if reverse {
+			return vi < vj
+		}
+		return vi > vj
+	})
+}
+
+func renderConsumerReportCSV(out io.Writer, states []api.ConsumerInfo) error {
+	w := csv.NewWriter(out)
+	err := w.Write([]string{"consumer", "mode", "ack_policy", "ack_wait", "ack_pending", "redelivered", "unprocessed", "ack_floor", "api_level", "leader"})
+	if err != nil {
+		return err
+	}
+
+	for _, cs := range states {
+		leader := ""
+		if cs.Cluster != nil {
+			leader = cs.Cluster.Leader
+		}
+
+		err = w.Write([]string{
+			cs.Name,
+			consumerReportMode(cs),
+			cs.Config.AckPolicy.String(),
+			cs.Config.AckWait.String(),
+			strconv.Itoa(cs.NumAckPending),
+			strconv.Itoa(cs.NumRedelivered),
+			strconv.FormatUint(cs.NumPending, 10),
+			strconv.FormatUint(cs.AckFloor.Stream, 10),
+			consumerReportAPILevel(cs),
+			leader,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// consumersMatchingExpression finds the names of consumers on stream matching expression, expression supports duration and size literals
+func consumersMatchingExpression(stream *jsm.Stream, expression string) (map[string]bool, error) {
+	expanded, err := expandExpressionLiterals(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	found, err := stream.QueryConsumers(jsm.ConsumerQueryExpression(expanded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	matched := make(map[string]bool, len(found))
+	for _, cons := range found {
+		matched[cons.Name()] = true
+	}
+
+	return matched, nil
+}
+
 func (c *consumerCmd) renderMissing(out io.Writer, missing []string, offline map[string]string) {
 	toany := func(items []string) (res []any) {
 		for _, i := range items {
@@ -0,0 +1,156 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+func (c *consumerCmd) backupDefinitionsAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, false)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(c.definitionsDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	var saved, skipped []string
+	var werr error
+
+	_, _, err = stream.EachConsumer(func(consumer *jsm.Consumer) {
+		if werr != nil {
+			return
+		}
+
+		if !consumer.IsDurable() {
+			skipped = append(skipped, consumer.Name())
+			return
+		}
+
+		cfg := consumer.Configuration()
+		cfg.Metadata = iu.RemoveReservedMetadata(cfg.Metadata)
+
+		cj, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			werr = err
+			return
+		}
+
+		werr = os.WriteFile(filepath.Join(c.definitionsDir, consumer.Name()+".json"), cj, 0600)
+		if werr != nil {
+			return
+		}
+
+		saved = append(saved, consumer.Name())
+	})
+	if err != nil {
+		return err
+	}
+	if werr != nil {
+		return werr
+	}
+
+	if len(skipped) > 0 {
+		slices.Sort(skipped)
+		fmt.Printf("Skipped %s ephemeral consumers: %s\n", f(len(skipped)), f(skipped))
+	}
+
+	fmt.Printf("Saved %s consumer configurations from Stream %s to %s\n", f(len(saved)), stream.Name(), c.definitionsDir)
+
+	return nil
+}
+
+func (c *consumerCmd) restoreDefinitionsAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, false)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(c.definitionsDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no consumer configurations found in %s", c.definitionsDir)
+	}
+	slices.Sort(files)
+
+	var created, failed int
+
+	table := iu.NewTableWriterf(opts(), "Consumers restored to Stream %s", stream.Name())
+	table.AddHeaders("Consumer", "Result")
+
+	for _, file := range files {
+		cfg, err := c.loadConfigFile(file)
+		if err != nil {
+			table.AddRow(filepath.Base(file), fmt.Sprintf("invalid configuration: %v", err))
+			failed++
+			continue
+		}
+
+		name := cfg.Durable
+		if name == "" {
+			name = cfg.Name
+		}
+
+		known, err := c.mgr.IsKnownConsumer(stream.Name(), name)
+		if err != nil {
+			return err
+		}
+		if known {
+			table.AddRow(name, "exists, unchanged")
+			continue
+		}
+
+		cfg.Metadata = iu.RemoveReservedMetadata(cfg.Metadata)
+
+		err = c.checkConfigLevel(cfg)
+		if err == nil {
+			_, err = c.mgr.NewConsumerFromDefault(stream.Name(), *cfg)
+		}
+		if err != nil {
+			table.AddRow(name, fmt.Sprintf("failed: %v", err))
+			failed++
+			continue
+		}
+
+		table.AddRow(name, "created")
+		created++
+	}
+
+	fmt.Println(table.Render())
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d consumers could not be restored", failed, len(files))
+	}
+
+	fmt.Printf("Created %s consumers on Stream %s\n", f(created), stream.Name())
+
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/jedib0t/go-pretty/v6/progress"
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// consumerDrained determines if all messages up to target were processed, the ack floor of filtered consumers
+// might never reach the target so a consumer without any outstanding work is also considered drained
+func consumerDrained(state api.ConsumerInfo, target uint64) bool {
+	if state.AckFloor.Stream >= target {
+		return true
+	}
+
+	return state.NumPending == 0 && state.NumAckPending == 0
+}
+
+func (c *consumerCmd) drainAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, true)
+
+	if !c.selectedConsumer.IsPullMode() {
+		return fmt.Errorf("only Pull consumers can be drained")
+	}
+
+	if c.drainDelete && !c.force {
+		ok, err := askConfirmation(fmt.Sprintf("Really delete Consumer %s > %s once drained", c.stream, c.consumer), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	streamState, err := stream.State()
+	if err != nil {
+		return err
+	}
+	target := streamState.LastSeq
+
+	state, err := c.selectedConsumer.State()
+	if err != nil {
+		return err
+	}
+	startFloor := state.AckFloor.Stream
+
+	var progbar progress.Writer
+	var tracker *progress.Tracker
+	if c.showProgress && target > startFloor && !consumerDrained(state, target) {
+		progbar, tracker, err = iu.NewProgress(opts(), &progress.Tracker{
+			Total: int64(target - startFloor),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	stopProgress := func() {
+		if tracker != nil {
+			time.Sleep(300 * time.Millisecond) // let it draw
+			progbar.Stop()
+			fmt.Println()
+		}
+	}
+
+	timeout := time.NewTimer(c.drainTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !consumerDrained(state, target) {
+		if tracker != nil {
+			tracker.SetValue(int64(min(state.AckFloor.Stream, target) - startFloor))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			stopProgress()
+			return fmt.Errorf("consumer %s > %s did not drain within %s, %s messages are unprocessed and %s are awaiting acknowledgement", c.stream, c.consumer, f(c.drainTimeout), f(state.NumPending), f(state.NumAckPending))
+		case <-ctx.Done():
+			stopProgress()
+			return ctx.Err()
+		}
+
+		state, err = c.selectedConsumer.State()
+		if err != nil {
+			stopProgress()
+			return err
+		}
+	}
+
+	if tracker != nil {
+		tracker.SetValue(tracker.Total)
+		tracker.MarkAsDone()
+	}
+	stopProgress()
+
+	fmt.Printf("Consumer %s > %s drained up to stream sequence %d\n", c.stream, c.consumer, target)
+
+	if !c.drainDelete {
+		return nil
+	}
+
+	err = c.selectedConsumer.Delete()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted Consumer %s > %s\n", c.stream, c.consumer)
+
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/jsm.go/api/jetstream/advisory"
+	"github.com/nats-io/nats.go"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+const (
+	consumerNakAdvisoryPre        = api.JSAdvisoryPrefix + ".CONSUMER.MSG_NAKED"
+	consumerTerminatedAdvisoryPre = api.JSAdvisoryPrefix + ".CONSUMER.MSG_TERMINATED"
+)
+
+// consumerEventMessage correlates the advisories received for a single stream message
+type consumerEventMessage struct {
+	seq           uint64
+	subject       string
+	peek          string
+	naks          int
+	terms         int
+	maxDeliveries int
+	deliveries    uint64
+}
+
+func (m *consumerEventMessage) total() int {
+	return m.naks + m.terms + m.maxDeliveries
+}
+
+func (c *consumerCmd) eventsAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, true)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	msgs := make(chan *nats.Msg, 1000)
+	for _, pre := range []string{consumerNakAdvisoryPre, consumerTerminatedAdvisoryPre, api.JSAdvisoryConsumerMaxDeliveryExceedPre} {
+		sub, err := c.nc.ChanSubscribe(fmt.Sprintf("%s.%s.%s", pre, c.stream, c.consumer), msgs)
+		if err != nil {
+			return err
+		}
+		defer sub.Unsubscribe()
+	}
+
+	err = c.nc.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Listening for delivery advisories on Consumer %s > %s\n\n", c.stream, c.consumer)
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	seen := make(map[uint64]*consumerEventMessage)
+	received := 0
+
+	for {
+		select {
+		case msg := <-msgs:
+			err = c.handleConsumerEvent(stream, msg, seen)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not process advisory on %s: %v\n", msg.Subject, err)
+				continue
+			}
+
+			received++
+			if c.eventsCount > 0 && received >= c.eventsCount {
+				c.renderConsumerEventsSummary(seen)
+				return nil
+			}
+
+		case <-ctx.Done():
+			c.renderConsumerEventsSummary(seen)
+			return nil
+		}
+	}
+}
+
+func (c *consumerCmd) handleConsumerEvent(stream *jsm.Stream, msg *nats.Msg, seen map[uint64]*consumerEventMessage) error {
+	var kind, reason string
+	var seq, deliveries uint64
+	var ts time.Time
+
+	switch {
+	case strings.HasPrefix(msg.Subject, consumerNakAdvisoryPre):
+		var nak advisory.JSConsumerDeliveryNakAdvisoryV1
+		err := json.Unmarshal(msg.Data, &nak)
+		if err != nil {
+			return err
+		}
+		kind, seq, deliveries, ts = "NAK", nak.StreamSeq, nak.Deliveries, nak.Time
+
+	case strings.HasPrefix(msg.Subject, consumerTerminatedAdvisoryPre):
+		var term advisory.JSConsumerDeliveryTerminatedAdvisoryV1
+		err := json.Unmarshal(msg.Data, &term)
+		if err != nil {
+			return err
+		}
+		kind, seq, deliveries, ts, reason = "TERMINATED", term.StreamSeq, term.Deliveries, term.Time, term.Reason
+
+	default:
+		var exceeded advisory.ConsumerDeliveryExceededAdvisoryV1
+		err := json.Unmarshal(msg.Data, &exceeded)
+		if err != nil {
+			return err
+		}
+		kind, seq, deliveries, ts = "MAX DELIVERIES", exceeded.StreamSeq, exceeded.Deliveries, exceeded.Time
+	}
+
+	m, ok := seen[seq]
+	if !ok {
+		m = &consumerEventMessage{seq: seq, subject: "unknown"}
+		stored, err := stream.ReadMessage(seq)
+		if err == nil {
+			m.subject = stored.Subject
+
+			// only the shortened data is kept as every message seen is held for the summary
+			if c.eventsPeek {
+				m.peek = iu.Base64IfNotPrintable(stored.Data)
+				if len(m.peek) > 80 {
+					m.peek = m.peek[:80] + "..."
+				}
+			}
+		}
+		seen[seq] = m
+	}
+
+	switch kind {
+	case "NAK":
+		m.naks++
+	case "TERMINATED":
+		m.terms++
+	default:
+		m.maxDeliveries++
+	}
+	m.deliveries = max(m.deliveries, deliveries)
+
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	line := fmt.Sprintf("[%s] %-14s stream seq: %d deliveries: %d subject: %s", ts.Local().Format("15:04:05"), kind, seq, deliveries, m.subject)
+	if reason != "" {
+		line += fmt.Sprintf(" reason: %s", reason)
+	}
+	if m.total() > 1 {
+		line += fmt.Sprintf(" (advisory %d for this message)", m.total())
+	}
+	fmt.Println(line)
+
+	if m.peek != "" {
+		fmt.Printf("           data: %s\n", m.peek)
+	}
+
+	return nil
+}
+
+func (c *consumerCmd) renderConsumerEventsSummary(seen map[uint64]*consumerEventMessage) {
+	fmt.Println()
+
+	if len(seen) == 0 {
+		fmt.Println("No delivery advisories received")
+		return
+	}
+
+	var msgs []*consumerEventMessage
+	for _, m := range seen {
+		msgs = append(msgs, m)
+	}
+	slices.SortFunc(msgs, func(a, b *consumerEventMessage) int {
+		return cmp.Or(cmp.Compare(b.total(), a.total()), cmp.Compare(a.seq, b.seq))
+	})
+	if len(msgs) > 10 {
+		msgs = msgs[:10]
+	}
+
+	table := iu.NewTableWriterf(opts(), "Messages with the most advisories on Consumer %s > %s", c.stream, c.consumer)
+	table.AddHeaders("Stream Sequence", "Subject", "Naks", "Terminated", "Max Deliveries", "Deliveries")
+	for _, m := range msgs {
+		table.AddRow(m.seq, m.subject, f(m.naks), f(m.terms), f(m.maxDeliveries), f(m.deliveries))
+	}
+	fmt.Println(table.Render())
+}
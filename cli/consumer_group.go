@@ -0,0 +1,164 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+type consumerGroupState struct {
+	Group          string    `json:"group"`
+	Policy         string    `json:"policy"`
+	PinnedClientID string    `json:"pinned_client_id,omitempty"`
+	PinnedTS       time.Time `json:"pinned_ts,omitempty"`
+}
+
+type consumerOverflowStatus struct {
+	Groups        []string `json:"groups"`
+	NumPending    uint64   `json:"num_pending"`
+	NumAckPending int      `json:"num_ack_pending"`
+	NumWaiting    int      `json:"num_waiting"`
+	MinPending    int64    `json:"min_pending,omitempty"`
+	MinAckPending int64    `json:"min_ack_pending,omitempty"`
+	Overflowing   bool     `json:"overflowing"`
+}
+
+// consumerGroupStates combines the configured groups with the pin state reported by the server,
+// groups that have never been pinned are not reported by the server
+func consumerGroupStates(nfo api.ConsumerInfo) []consumerGroupState {
+	pins := map[string]api.PriorityGroupState{}
+	for _, g := range nfo.PriorityGroups {
+		pins[g.Group] = g
+	}
+
+	var res []consumerGroupState
+	for _, g := range nfo.Config.PriorityGroups {
+		state := consumerGroupState{Group: g, Policy: nfo.Config.PriorityPolicy.String()}
+		if pin, ok := pins[g]; ok {
+			state.PinnedClientID = pin.PinnedClientID
+			state.PinnedTS = pin.PinnedTS
+		}
+		res = append(res, state)
+	}
+
+	return res
+}
+
+// overflowing determines if a pull request with the given thresholds would be served, either threshold being met is enough
+func overflowing(nfo api.ConsumerInfo, minPending int64, minAckPending int64) bool {
+	if minPending <= 0 && minAckPending <= 0 {
+		return true
+	}
+
+	if minPending > 0 && nfo.NumPending >= uint64(minPending) {
+		return true
+	}
+
+	return minAckPending > 0 && int64(nfo.NumAckPending) >= minAckPending
+}
+
+func (c *consumerCmd) groupListAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, true)
+
+	nfo, err := c.selectedConsumer.LatestState()
+	if err != nil {
+		return err
+	}
+
+	if nfo.Config.PriorityPolicy == api.PriorityNone || len(nfo.Config.PriorityGroups) == 0 {
+		return fmt.Errorf("consumer %s > %s does not have Priority Groups", c.stream, c.consumer)
+	}
+
+	groups := consumerGroupStates(nfo)
+
+	if c.json {
+		return iu.PrintJSON(groups)
+	}
+
+	table := iu.NewTableWriterf(opts(), "Priority Groups on Consumer %s > %s", c.stream, c.consumer)
+	table.AddHeaders("Group", "Policy", "Pinned Client", "Pinned")
+	for _, g := range groups {
+		switch {
+		case nfo.Config.PriorityPolicy != api.PriorityPinnedClient:
+			table.AddRow(g.Group, g.Policy, "", "")
+		case g.PinnedClientID == "":
+			table.AddRow(g.Group, g.Policy, "No client", "")
+		default:
+			table.AddRow(g.Group, g.Policy, g.PinnedClientID, fmt.Sprintf("%s ago", f(time.Since(g.PinnedTS))))
+		}
+	}
+	fmt.Println(table.Render())
+
+	if nfo.Config.PriorityPolicy == api.PriorityPinnedClient {
+		fmt.Printf("Pinned clients that do not pull for %s are unpinned\n", f(nfo.Config.PinnedTTL))
+	}
+
+	return nil
+}
+
+func (c *consumerCmd) groupOverflowAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, true)
+
+	nfo, err := c.selectedConsumer.LatestState()
+	if err != nil {
+		return err
+	}
+
+	if nfo.Config.PriorityPolicy != api.PriorityOverflow {
+		return fmt.Errorf("consumer %s > %s is not an Overflow consumer", c.stream, c.consumer)
+	}
+
+	status := consumerOverflowStatus{
+		Groups:        nfo.Config.PriorityGroups,
+		NumPending:    nfo.NumPending,
+		NumAckPending: nfo.NumAckPending,
+		NumWaiting:    nfo.NumWaiting,
+		MinPending:    c.groupMinPending,
+		MinAckPending: c.groupMinAckPending,
+		Overflowing:   overflowing(nfo, c.groupMinPending, c.groupMinAckPending),
+	}
+
+	if c.json {
+		return iu.PrintJSON(status)
+	}
+
+	cols := newColumnsf("Overflow status for Consumer %s > %s", c.stream, c.consumer)
+	defer cols.Frender(os.Stdout)
+
+	cols.AddRow("Groups", status.Groups)
+	cols.AddRow("Unprocessed Messages", status.NumPending)
+	if nfo.Config.MaxAckPending > 0 {
+		cols.AddRowf("Outstanding Acks", "%s out of maximum %s", f(status.NumAckPending), f(nfo.Config.MaxAckPending))
+	} else {
+		cols.AddRow("Outstanding Acks", status.NumAckPending)
+	}
+	cols.AddRow("Waiting Pulls", status.NumWaiting)
+	cols.AddRowIf("Minimum Pending", status.MinPending, status.MinPending > 0)
+	cols.AddRowIf("Minimum Ack Pending", status.MinAckPending, status.MinAckPending > 0)
+
+	switch {
+	case status.MinPending <= 0 && status.MinAckPending <= 0:
+		cols.AddRow("Overflowing", "clients without thresholds always receive messages")
+	default:
+		cols.AddRow("Overflowing", status.Overflowing)
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	iu "github.com/nats-io/natscli/internal/util"
+	"github.com/nats-io/nuid"
+)
+
+const (
+	latencyProbeHeader   = "Nats-Latency-Probe"
+	latencyProbeTSHeader = "Nats-Latency-Probe-Time"
+	latencyProbeGrace    = 5 * time.Second
+)
+
+type consumerLatencyReport struct {
+	Stream   string        `json:"stream"`
+	Consumer string        `json:"consumer"`
+	Subject  string        `json:"subject"`
+	Sent     int           `json:"sent"`
+	Received int           `json:"received"`
+	Others   int           `json:"others"`
+	Min      time.Duration `json:"min"`
+	P50      time.Duration `json:"p50"`
+	P95      time.Duration `json:"p95"`
+	P99      time.Duration `json:"p99"`
+	Max      time.Duration `json:"max"`
+}
+
+// latencyProbeSubject picks the subject to publish probes to, it has to be matched by the consumer filter
+func latencyProbeSubject(subject string, filters []string, streamSubjects []string) (string, error) {
+	if len(filters) == 0 {
+		filters = streamSubjects
+	}
+
+	if subject == "" {
+		if len(filters) != 1 || strings.ContainsAny(filters[0], "*>") {
+			return "", fmt.Errorf("a probe subject is required when the consumer filter is not a single literal subject, set one using --subject")
+		}
+
+		return filters[0], nil
+	}
+
+	for _, filter := range filters {
+		if jsm.SubjectIsSubsetMatch(subject, filter) {
+			return subject, nil
+		}
+	}
+
+	return "", fmt.Errorf("probe subject %s is not matched by the consumer filter %s", subject, strings.Join(filters, ", "))
+}
+
+// latencyProbeConsumerConfig creates the configuration of a temporary consumer that receives only new probes, it is
+// stored and replicated like cfg so latencies are comparable without taking messages from the measured consumer
+func latencyProbeConsumerConfig(cfg api.ConsumerConfig, subject string) api.ConsumerConfig {
+	return api.ConsumerConfig{
+		Description:       fmt.Sprintf("Latency probes for %s", cfg.Name),
+		AckPolicy:         api.AckExplicit,
+		AckWait:           cfg.AckWait,
+		DeliverPolicy:     api.DeliverNew,
+		FilterSubject:     subject,
+		MaxAckPending:     cfg.MaxAckPending,
+		MaxWaiting:        cfg.MaxWaiting,
+		ReplayPolicy:      api.ReplayInstant,
+		Replicas:          cfg.Replicas,
+		MemoryStorage:     cfg.MemoryStorage,
+		InactiveThreshold: time.Minute,
+	}
+}
+
+func buildConsumerLatencyReport(report *consumerLatencyReport, latencies []time.Duration) {
+	slices.Sort(latencies)
+
+	report.Received = len(latencies)
+	if len(latencies) == 0 {
+		return
+	}
+
+	report.Min = latencies[0]
+	report.P50 = durationPercentile(latencies, 50)
+	report.P95 = durationPercentile(latencies, 95)
+	report.P99 = durationPercentile(latencies, 99)
+	report.Max = durationPercentile(latencies, 100)
+}
+
+func (c *consumerCmd) latencyAction(_ *fisk.ParseContext) error {
+	if c.latencyRate < 1 {
+		return fmt.Errorf("rate must be at least 1")
+	}
+
+	c.connectAndSetup(true, true)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	filters := c.selectedConsumer.FilterSubjects()
+	if c.selectedConsumer.FilterSubject() != "" {
+		filters = []string{c.selectedConsumer.FilterSubject()}
+	}
+
+	subject, err := latencyProbeSubject(c.latencySubject, filters, stream.Subjects())
+	if err != nil {
+		return err
+	}
+
+	js, err := newJetStreamWithOptions(c.nc, opts())
+	if err != nil {
+		return err
+	}
+
+	probe, err := c.mgr.NewConsumerFromDefault(c.stream, latencyProbeConsumerConfig(c.selectedConsumer.Configuration(), subject))
+	if err != nil {
+		return fmt.Errorf("could not create probe consumer: %w", err)
+	}
+	defer probe.Delete()
+
+	cons, err := js.Consumer(ctx, c.stream, probe.Name())
+	if err != nil {
+		return err
+	}
+
+	iter, err := cons.Messages()
+	if err != nil {
+		return err
+	}
+	defer iter.Stop()
+
+	report := &consumerLatencyReport{Stream: c.stream, Consumer: c.consumer, Subject: subject}
+	probeId := nuid.Next()
+
+	var latencies []time.Duration
+	var mu sync.Mutex
+	received := make(chan struct{}, 1)
+
+	go func() {
+		for {
+			msg, err := iter.Next()
+			if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				return
+			}
+			if err != nil {
+				continue
+			}
+
+			// the probe consumer is ours alone so every message is acknowledged
+			msg.Ack()
+
+			if msg.Headers().Get(latencyProbeHeader) != probeId {
+				// regular messages or probes of other measurements published to the probe subject
+				mu.Lock()
+				report.Others++
+				mu.Unlock()
+				continue
+			}
+
+			ts, err := strconv.ParseInt(msg.Headers().Get(latencyProbeTSHeader), 10, 64)
+			if err != nil {
+				continue
+			}
+
+			mu.Lock()
+			latencies = append(latencies, time.Since(time.Unix(0, ts)))
+			mu.Unlock()
+
+			select {
+			case received <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	if !c.json {
+		fmt.Printf("Publishing %d probes per second to %s for %s\n", c.latencyRate, subject, f(c.latencyDuration))
+	}
+
+	pubCtx, cancel := context.WithTimeout(ctx, c.latencyDuration)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second / time.Duration(c.latencyRate))
+	defer ticker.Stop()
+
+	// send the first probe right away rather than after the first tick
+	for publish := true; publish; {
+		msg := nats.NewMsg(subject)
+		msg.Header.Set(latencyProbeHeader, probeId)
+		msg.Header.Set(latencyProbeTSHeader, strconv.FormatInt(time.Now().UnixNano(), 10))
+
+		_, err = js.PublishMsg(ctx, msg)
+		if err != nil {
+			return fmt.Errorf("publishing probe failed: %w", err)
+		}
+		report.Sent++
+
+		select {
+		case <-ticker.C:
+		case <-pubCtx.Done():
+			publish = false
+		}
+	}
+
+	// give probes still in flight a chance to arrive
+	grace := time.NewTimer(latencyProbeGrace)
+	defer grace.Stop()
+
+	for waiting := true; waiting; {
+		mu.Lock()
+		done := len(latencies) >= report.Sent
+		mu.Unlock()
+		if done {
+			break
+		}
+
+		select {
+		case <-received:
+		case <-grace.C:
+			waiting = false
+		case <-ctx.Done():
+			waiting = false
+		}
+	}
+
+	iter.Stop()
+
+	mu.Lock()
+	buildConsumerLatencyReport(report, slices.Clone(latencies))
+	mu.Unlock()
+
+	if c.json {
+		return iu.PrintJSON(report)
+	}
+
+	fmt.Println()
+	cols := newColumnsf("Latency for Consumer %s > %s using probes on %s", c.stream, c.consumer, subject)
+	defer cols.Frender(os.Stdout)
+
+	cols.AddRow("Probes Sent", report.Sent)
+	cols.AddRow("Probes Received", report.Received)
+	cols.AddRowIf("Probes Lost", report.Sent-report.Received, report.Sent > report.Received)
+	cols.AddRowIf("Other Messages", fmt.Sprintf("%s published to %s during the measurement", f(report.Others), subject), report.Others > 0)
+	if report.Received > 0 {
+		cols.AddSectionTitle("Latency")
+		cols.AddRow("Minimum", report.Min)
+		cols.AddRow("p50", report.P50)
+		cols.AddRow("p95", report.P95)
+		cols.AddRow("p99", report.P99)
+		cols.AddRow("Maximum", report.Max)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/nats.go"
+)
+
+// consumerNextMsgMetadata is the sidecar written next to each message saved by consumer next --output-dir
+type consumerNextMsgMetadata struct {
+	Subject        string              `json:"subject"`
+	Headers        map[string][]string `json:"headers,omitempty"`
+	Stream         string              `json:"stream"`
+	Consumer       string              `json:"consumer"`
+	StreamSeq      uint64              `json:"stream_seq"`
+	ConsumerSeq    uint64              `json:"consumer_seq"`
+	Delivered      int                 `json:"delivered"`
+	Pending        uint64              `json:"pending"`
+	Time           time.Time           `json:"time"`
+	Acknowledgment string              `json:"acknowledgment"`
+}
+
+// writeNextMsg saves the payload of a message as SEQ.data and its metadata as SEQ.json in the output directory
+func (c *consumerCmd) writeNextMsg(msg *nats.Msg) error {
+	info, err := jsm.ParseJSMsgMetadata(msg)
+	if err != nil {
+		return fmt.Errorf("could not parse message metadata: %w", err)
+	}
+
+	meta := consumerNextMsgMetadata{
+		Subject:        msg.Subject,
+		Headers:        msg.Header,
+		Stream:         info.Stream(),
+		Consumer:       info.Consumer(),
+		StreamSeq:      info.StreamSequence(),
+		ConsumerSeq:    info.ConsumerSequence(),
+		Delivered:      info.Delivered(),
+		Pending:        info.Pending(),
+		Time:           info.TimeStamp(),
+		Acknowledgment: "none",
+	}
+
+	switch {
+	case c.term:
+		meta.Acknowledgment = "term"
+	case c.nak:
+		meta.Acknowledgment = "nak"
+	case c.ack:
+		meta.Acknowledgment = "ack"
+	}
+
+	base := filepath.Join(c.nextOutputDir, fmt.Sprintf("%d", meta.StreamSeq))
+
+	err = os.WriteFile(base+".data", msg.Data, 0600)
+	if err != nil {
+		return err
+	}
+
+	mj, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(base+".json", mj, 0600)
+	if err != nil {
+		return err
+	}
+
+	if !c.raw {
+		fmt.Printf("[%s] subj: %s / tries: %d / str seq: %d written to %s.data\n", time.Now().Format("15:04:05"), msg.Subject, meta.Delivered, meta.StreamSeq, base)
+	}
+
+	return nil
+}
+
+// getNextMsgBatches fetches up to c.pullCount messages using pull requests of c.pullBatch messages each
+func (c *consumerCmd) getNextMsgBatches(stream string, consumer string) error {
+	c.checkNextAckFlags()
+
+	received := 0
+
+	for received < c.pullCount {
+		batch := min(c.pullBatch, c.pullCount-received)
+
+		sub, err := c.nc.SubscribeSync(c.nc.NewRespInbox())
+		if err != nil {
+			return err
+		}
+
+		req := &api.JSApiConsumerGetNextRequest{Batch: batch, Expires: opts().Timeout, NoWait: true}
+		err = c.mgr.NextMsgRequest(stream, consumer, sub.Subject, req)
+		if err != nil {
+			sub.Unsubscribe()
+			return fmt.Errorf("could not request next messages: %w", err)
+		}
+
+		got := 0
+		for got < batch {
+			msg, err := sub.NextMsg(opts().Timeout)
+			if err != nil {
+				sub.Unsubscribe()
+				return fmt.Errorf("no message received: %w", err)
+			}
+
+			if len(msg.Data) == 0 && msg.Header.Get("Status") != "" {
+				break
+			}
+
+			got++
+			err = c.handleNextMsg(msg)
+			if err != nil {
+				sub.Unsubscribe()
+				return err
+			}
+		}
+		sub.Unsubscribe()
+
+		received += got
+
+		// the consumer had fewer messages available than requested
+		if got < batch {
+			break
+		}
+	}
+
+	if received == 0 {
+		return fmt.Errorf("no messages available on Consumer %s > %s", stream, consumer)
+	}
+
+	if !c.raw {
+		fmt.Printf("Received %s messages from Consumer %s > %s\n", f(received), stream, consumer)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// consumerProfilePath is the location of a named consumer profile in the configuration directory
+func consumerProfilePath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid profile name %q", name)
+	}
+
+	parent, err := iu.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine configuration directory: %w", err)
+	}
+
+	return filepath.Join(parent, "profiles", "consumer", name+".json"), nil
+}
+
+// applyConsumerProfile uses the settings present in a profile as defaults for any options not set on the command line,
+// settings that are not in the profile are left to flags and prompts as usual
+func (c *consumerCmd) applyConsumerProfile(name string) error {
+	path, err := consumerProfilePath(name)
+	if err != nil {
+		return err
+	}
+
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unknown consumer profile %q, expected it in %s", name, path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// zero values are valid settings so we track which keys the profile actually holds
+	var keys map[string]json.RawMessage
+	err = json.Unmarshal(body, &keys)
+	if err != nil {
+		return fmt.Errorf("invalid consumer profile %s: %w", path, err)
+	}
+
+	var p api.ConsumerConfig
+	err = json.Unmarshal(body, &p)
+	if err != nil {
+		return fmt.Errorf("invalid consumer profile %s: %w", path, err)
+	}
+
+	has := func(key string) bool {
+		_, ok := keys[key]
+		return ok
+	}
+
+	if has("description") && c.description == "" {
+		c.description = p.Description
+	}
+
+	if has("ack_policy") && c.ackPolicy == "" {
+		switch p.AckPolicy {
+		case api.AckNone:
+			c.ackPolicy = "none"
+		case api.AckAll:
+			c.ackPolicy = "all"
+		case api.AckFlowControl:
+			c.ackPolicy = "flow_control"
+		default:
+			c.ackPolicy = "explicit"
+		}
+	}
+
+	if has("ack_wait") && c.ackWait == -1*time.Second {
+		c.ackWait = p.AckWait
+	}
+
+	if has("max_deliver") && c.maxDeliver == 0 {
+		c.maxDeliver = p.MaxDeliver
+	}
+
+	// an explicit --backoff mode takes precedence over the periods in the profile
+	if has("backoff") && c.backoffMode == "" {
+		c.profileBackoff = p.BackOff
+	}
+
+	if has("max_ack_pending") && c.maxAckPending == -1 {
+		c.maxAckPending = p.MaxAckPending
+	}
+
+	if has("max_waiting") && c.maxWaiting == 0 {
+		c.maxWaiting = p.MaxWaiting
+	}
+
+	if has("max_batch") && c.maxPullBatch == 0 {
+		c.maxPullBatch = p.MaxRequestBatch
+	}
+
+	if has("max_expires") && c.maxPullExpire == 0 {
+		c.maxPullExpire = p.MaxRequestExpires
+	}
+
+	if has("inactive_threshold") && c.inactiveThreshold == 0 {
+		c.inactiveThreshold = p.InactiveThreshold
+	}
+
+	if has("headers_only") && !c.hdrsOnlySet {
+		c.hdrsOnly, c.hdrsOnlySet = p.HeadersOnly, true
+	}
+
+	if has("num_replicas") && c.replicas == 0 {
+		c.replicas = p.Replicas
+	}
+
+	metadata := iu.RemoveReservedMetadata(p.Metadata)
+	if len(metadata) > 0 {
+		for k, v := range metadata {
+			_, ok := c.metadata[k]
+			if !ok {
+				c.metadata[k] = v
+			}
+		}
+		c.metadataIsSet = true
+	}
+
+	return nil
+}
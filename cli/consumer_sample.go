@@ -0,0 +1,270 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/jsm.go/api/jetstream/metric"
+	"github.com/nats-io/nats.go"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+type consumerSampleLatency struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+type consumerSampleSubject struct {
+	Subject         string                `json:"subject"`
+	Samples         int                   `json:"samples"`
+	Redelivered     int                   `json:"redelivered"`
+	RedeliveryRatio float64               `json:"redelivery_ratio"`
+	Latency         consumerSampleLatency `json:"latency"`
+}
+
+type consumerSampleReport struct {
+	Stream          string                   `json:"stream"`
+	Consumer        string                   `json:"consumer"`
+	Duration        time.Duration            `json:"duration"`
+	Samples         int                      `json:"samples"`
+	Redelivered     int                      `json:"redelivered"`
+	RedeliveryRatio float64                  `json:"redelivery_ratio"`
+	Latency         consumerSampleLatency    `json:"latency"`
+	Subjects        []*consumerSampleSubject `json:"subjects"`
+}
+
+// consumerSampleRemoved is the subject reported for samples of messages no longer in the stream, for example
+// those removed on acknowledgement by work queue and interest streams
+const consumerSampleRemoved = "(removed from stream)"
+
+type consumerSampleAck struct {
+	seq        uint64
+	subject    string
+	delay      time.Duration
+	deliveries uint64
+}
+
+// durationPercentile finds the nearest-rank percentile in a sorted list of durations
+func durationPercentile(sorted []time.Duration, pct float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(pct / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func consumerSampleLatencies(delays []time.Duration) consumerSampleLatency {
+	slices.Sort(delays)
+
+	return consumerSampleLatency{
+		P50: durationPercentile(delays, 50),
+		P90: durationPercentile(delays, 90),
+		P99: durationPercentile(delays, 99),
+		Max: durationPercentile(delays, 100),
+	}
+}
+
+func buildConsumerSampleReport(samples []consumerSampleAck) (consumerSampleLatency, int, []*consumerSampleSubject) {
+	var all []time.Duration
+	var redelivered int
+	subjects := make(map[string]*consumerSampleSubject)
+	delays := make(map[string][]time.Duration)
+
+	for _, sample := range samples {
+		subj, ok := subjects[sample.subject]
+		if !ok {
+			subj = &consumerSampleSubject{Subject: sample.subject}
+			subjects[sample.subject] = subj
+		}
+
+		subj.Samples++
+		if sample.deliveries > 1 {
+			subj.Redelivered++
+			redelivered++
+		}
+
+		all = append(all, sample.delay)
+		delays[sample.subject] = append(delays[sample.subject], sample.delay)
+	}
+
+	res := []*consumerSampleSubject{}
+	for _, subj := range subjects {
+		subj.RedeliveryRatio = float64(subj.Redelivered) / float64(subj.Samples)
+		subj.Latency = consumerSampleLatencies(delays[subj.Subject])
+		res = append(res, subj)
+	}
+	slices.SortFunc(res, func(a, b *consumerSampleSubject) int {
+		return cmp.Or(cmp.Compare(b.Samples, a.Samples), strings.Compare(a.Subject, b.Subject))
+	})
+
+	return consumerSampleLatencies(all), redelivered, res
+}
+
+// resolveConsumerSampleSubjects looks up the subject of every sampled message once sampling is complete
+func resolveConsumerSampleSubjects(stream *jsm.Stream, samples []consumerSampleAck) {
+	subjects := make(map[uint64]string)
+
+	for i := range samples {
+		subj, ok := subjects[samples[i].seq]
+		if !ok {
+			stored, err := stream.ReadMessage(samples[i].seq)
+			switch {
+			case err == nil:
+				subj = stored.Subject
+			case jsm.IsNatsError(err, 10037):
+				subj = consumerSampleRemoved
+			default:
+				subj = "unknown"
+			}
+			subjects[samples[i].seq] = subj
+		}
+
+		samples[i].subject = subj
+	}
+}
+
+func (c *consumerCmd) sampleAction(_ *fisk.ParseContext) error {
+	if c.sampleDuration <= 0 {
+		return fmt.Errorf("--duration must be greater than 0")
+	}
+	if c.samplePercent < 1 || c.samplePercent > 100 {
+		return fmt.Errorf("--percent must be between 1 and 100")
+	}
+
+	c.connectAndSetup(true, true)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	consumer, err := c.mgr.LoadConsumer(c.stream, c.consumer)
+	if err != nil {
+		return err
+	}
+
+	if !consumer.IsSampled() {
+		if !c.sampleEnable {
+			return fmt.Errorf("consumer %s > %s does not have sampling enabled", c.stream, c.consumer)
+		}
+
+		err = consumer.UpdateConfiguration(jsm.SamplePercent(c.samplePercent))
+		if err != nil {
+			return fmt.Errorf("could not enable sampling: %w", err)
+		}
+
+		defer func() {
+			err := consumer.UpdateConfiguration(jsm.SamplePercent(0))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARNING: could not disable sampling on Consumer %s > %s: %v\n", c.stream, c.consumer, err)
+			}
+		}()
+
+		if !c.json {
+			fmt.Printf("Temporarily enabled %d%% sampling on Consumer %s > %s\n", c.samplePercent, c.stream, c.consumer)
+		}
+	}
+
+	msgs := make(chan *nats.Msg, 1000)
+	sub, err := c.nc.ChanSubscribe(api.JSMetricConsumerAckPre+"."+c.stream+"."+c.consumer, msgs)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	if !c.json {
+		fmt.Printf("Gathering acknowledgement samples for Consumer %s > %s for %s\n\n", c.stream, c.consumer, f(c.sampleDuration))
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	timer := time.NewTimer(c.sampleDuration)
+	defer timer.Stop()
+
+	started := time.Now()
+	var samples []consumerSampleAck
+
+gather:
+	for {
+		select {
+		case msg := <-msgs:
+			var ack metric.ConsumerAckMetricV1
+			err = json.Unmarshal(msg.Data, &ack)
+			if err != nil {
+				continue
+			}
+
+			samples = append(samples, consumerSampleAck{seq: ack.StreamSeq, delay: time.Duration(ack.Delay), deliveries: ack.Deliveries})
+
+		case <-timer.C:
+			break gather
+		case <-ctx.Done():
+			break gather
+		}
+	}
+
+	sub.Unsubscribe()
+	resolveConsumerSampleSubjects(stream, samples)
+
+	report := consumerSampleReport{
+		Stream:   c.stream,
+		Consumer: c.consumer,
+		Duration: time.Since(started).Round(time.Millisecond),
+		Samples:  len(samples),
+	}
+	report.Latency, report.Redelivered, report.Subjects = buildConsumerSampleReport(samples)
+	if report.Samples > 0 {
+		report.RedeliveryRatio = float64(report.Redelivered) / float64(report.Samples)
+	}
+
+	if c.json {
+		return iu.PrintJSON(report)
+	}
+
+	if report.Samples == 0 {
+		fmt.Printf("No acknowledgement samples received for Consumer %s > %s in %s\n", c.stream, c.consumer, f(report.Duration))
+		return nil
+	}
+
+	table := iu.NewTableWriterf(opts(), "Acknowledgement samples for Consumer %s > %s over %s", c.stream, c.consumer, f(report.Duration))
+	table.AddHeaders("Subject", "Samples", "Redelivered", "Redelivery Ratio", "P50", "P90", "P99", "Max")
+	for _, subj := range report.Subjects {
+		table.AddRow(subj.Subject, f(subj.Samples), f(subj.Redelivered), fmt.Sprintf("%.1f%%", subj.RedeliveryRatio*100), f(subj.Latency.P50), f(subj.Latency.P90), f(subj.Latency.P99), f(subj.Latency.Max))
+	}
+	table.AddFooter("Total", f(report.Samples), f(report.Redelivered), fmt.Sprintf("%.1f%%", report.RedeliveryRatio*100), f(report.Latency.P50), f(report.Latency.P90), f(report.Latency.P99), f(report.Latency.Max))
+	fmt.Println(table.Render())
+
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// consumerStateSnapshot is a point in time view of consumer progress used to audit migrations and upgrades,
+// the server does not expose per message redelivery details so only the redelivery count is recorded
+type consumerStateSnapshot struct {
+	Stream         string                   `json:"stream"`
+	Consumer       string                   `json:"consumer"`
+	Time           time.Time                `json:"time"`
+	StreamLastSeq  uint64                   `json:"stream_last_seq"`
+	Delivered      api.SequenceInfo         `json:"delivered"`
+	AckFloor       api.SequenceInfo         `json:"ack_floor"`
+	NumPending     uint64                   `json:"num_pending"`
+	NumAckPending  int                      `json:"num_ack_pending"`
+	NumRedelivered int                      `json:"num_redelivered"`
+	NumWaiting     int                      `json:"num_waiting"`
+	Paused         bool                     `json:"paused,omitempty"`
+	PriorityGroups []api.PriorityGroupState `json:"priority_groups,omitempty"`
+	Leader         string                   `json:"leader,omitempty"`
+}
+
+func newConsumerStateSnapshot(state api.ConsumerInfo, streamState api.StreamState) consumerStateSnapshot {
+	snap := consumerStateSnapshot{
+		Stream:         state.Stream,
+		Consumer:       state.Name,
+		Time:           state.TimeStamp,
+		StreamLastSeq:  streamState.LastSeq,
+		Delivered:      state.Delivered,
+		AckFloor:       state.AckFloor,
+		NumPending:     state.NumPending,
+		NumAckPending:  state.NumAckPending,
+		NumRedelivered: state.NumRedelivered,
+		NumWaiting:     state.NumWaiting,
+		Paused:         state.Paused,
+		PriorityGroups: state.PriorityGroups,
+	}
+
+	if snap.Time.IsZero() {
+		snap.Time = time.Now().UTC()
+	}
+
+	if state.Cluster != nil {
+		snap.Leader = state.Cluster.Leader
+	}
+
+	return snap
+}
+
+func (c *consumerCmd) stateSnapshotAction(_ *fisk.ParseContext) error {
+	c.connectAndSetup(true, true)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	streamState, err := stream.State()
+	if err != nil {
+		return err
+	}
+
+	state, err := c.selectedConsumer.State()
+	if err != nil {
+		return err
+	}
+
+	snap := newConsumerStateSnapshot(state, streamState)
+
+	if c.stateCompare == "" {
+		return iu.PrintJSON(snap)
+	}
+
+	data, err := os.ReadFile(c.stateCompare)
+	if err != nil {
+		return err
+	}
+
+	var before consumerStateSnapshot
+	err = json.Unmarshal(data, &before)
+	if err != nil {
+		return fmt.Errorf("invalid state snapshot %s: %w", c.stateCompare, err)
+	}
+
+	if before.Stream != snap.Stream || before.Consumer != snap.Consumer {
+		return fmt.Errorf("snapshot %s is for Consumer %s > %s", c.stateCompare, before.Stream, before.Consumer)
+	}
+
+	change := func(b, a int64) string {
+		if a == b {
+			return ""
+		}
+		return fmt.Sprintf("%+d", a-b)
+	}
+
+	table := iu.NewTableWriterf(opts(), "State changes for Consumer %s > %s since %s", c.stream, c.consumer, f(before.Time))
+	table.AddHeaders("Field", "Before", "After", "Change")
+	table.AddRow("Stream Last Sequence", f(before.StreamLastSeq), f(snap.StreamLastSeq), change(int64(before.StreamLastSeq), int64(snap.StreamLastSeq)))
+	table.AddRow("Delivered Consumer Sequence", f(before.Delivered.Consumer), f(snap.Delivered.Consumer), change(int64(before.Delivered.Consumer), int64(snap.Delivered.Consumer)))
+	table.AddRow("Delivered Stream Sequence", f(before.Delivered.Stream), f(snap.Delivered.Stream), change(int64(before.Delivered.Stream), int64(snap.Delivered.Stream)))
+	table.AddRow("Ack Floor Consumer Sequence", f(before.AckFloor.Consumer), f(snap.AckFloor.Consumer), change(int64(before.AckFloor.Consumer), int64(snap.AckFloor.Consumer)))
+	table.AddRow("Ack Floor Stream Sequence", f(before.AckFloor.Stream), f(snap.AckFloor.Stream), change(int64(before.AckFloor.Stream), int64(snap.AckFloor.Stream)))
+	table.AddRow("Unprocessed Messages", f(before.NumPending), f(snap.NumPending), change(int64(before.NumPending), int64(snap.NumPending)))
+	table.AddRow("Outstanding Acks", f(before.NumAckPending), f(snap.NumAckPending), change(int64(before.NumAckPending), int64(snap.NumAckPending)))
+	table.AddRow("Redelivered Messages", f(before.NumRedelivered), f(snap.NumRedelivered), change(int64(before.NumRedelivered), int64(snap.NumRedelivered)))
+	table.AddRow("Waiting Pulls", f(before.NumWaiting), f(snap.NumWaiting), change(int64(before.NumWaiting), int64(snap.NumWaiting)))
+	if before.Leader != "" || snap.Leader != "" {
+		table.AddRow("Leader", before.Leader, snap.Leader, "")
+	}
+	fmt.Println(table.Render())
+
+	switch {
+	case snap.Delivered.Stream < before.Delivered.Stream || snap.AckFloor.Stream < before.AckFloor.Stream:
+		return fmt.Errorf("consumer %s > %s moved backwards since the snapshot was taken", c.stream, c.consumer)
+	case snap.StreamLastSeq < before.StreamLastSeq:
+		return fmt.Errorf("stream %s has a lower last sequence than when the snapshot was taken", c.stream)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// orderedSubscribe reads the messages the consumer would receive using an ordered consumer, the consumer itself is not
+// touched, and gaps detected by the ordered consumer are handled by recreating it from the last seen stream sequence
+func (c *consumerCmd) orderedSubscribe(consumer *jsm.Consumer) error {
+	nfo, err := consumer.LatestState()
+	if err != nil {
+		return err
+	}
+
+	ocfg, err := orderedConsumerConfig(nfo)
+	if err != nil {
+		return err
+	}
+
+	js, err := newJetStreamWithOptions(c.nc, opts())
+	if err != nil {
+		return err
+	}
+
+	cons, err := js.OrderedConsumer(ctx, c.stream, ocfg)
+	if err != nil {
+		return err
+	}
+
+	iter, err := cons.Messages()
+	if err != nil {
+		return err
+	}
+	defer iter.Stop()
+
+	go func() {
+		<-ctx.Done()
+		iter.Stop()
+	}()
+
+	if !c.raw {
+		fmt.Printf("Reading Consumer %s > %s using an ordered consumer, its state will not be modified\n\n", c.stream, c.consumer)
+	}
+
+	var seen int
+	var lastSeq uint64
+	for {
+		msg, err := iter.Next()
+		if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return err
+		}
+
+		// the ordered consumer might replay messages when it recreates itself
+		if meta.Sequence.Stream <= lastSeq {
+			continue
+		}
+		lastSeq = meta.Sequence.Stream
+		seen++
+
+		c.renderOrderedSubMsg(msg, meta)
+
+		if c.subCount > 0 && seen >= c.subCount {
+			return nil
+		}
+	}
+}
+
+func (c *consumerCmd) renderOrderedSubMsg(msg jetstream.Msg, meta *jetstream.MsgMetadata) {
+	if c.raw {
+		fmt.Println(string(msg.Data()))
+		return
+	}
+
+	fmt.Printf("[%s] subj: %s / str seq: %d / pending: %s\n", time.Now().Format("15:04:05"), msg.Subject(), meta.Sequence.Stream, f(meta.NumPending))
+
+	if len(msg.Headers()) > 0 {
+		fmt.Println()
+		fmt.Println("Headers:")
+		fmt.Println()
+
+		for h, vals := range msg.Headers() {
+			for _, val := range vals {
+				fmt.Printf("   %s: %s\n", h, val)
+			}
+		}
+
+		fmt.Println()
+		fmt.Println("Data:")
+	}
+
+	fmt.Printf("%s\n", string(msg.Data()))
+	if !strings.HasSuffix(string(msg.Data()), "\n") {
+		fmt.Println()
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// consumerSubWorker tracks the messages handled by one parallel pull worker
+type consumerSubWorker struct {
+	received    atomic.Uint64
+	acked       atomic.Uint64
+	redelivered atomic.Uint64
+	errors      atomic.Uint64
+}
+
+// parallelSubscribe runs c.subParallel workers that each pull and handle one message at a time
+func (c *consumerCmd) parallelSubscribe(consumer *jsm.Consumer) error {
+	js, err := newJetStreamWithOptions(c.nc, opts())
+	if err != nil {
+		return err
+	}
+
+	cons, err := js.Consumer(ctx, consumer.StreamName(), consumer.Name())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var total atomic.Uint64
+	var failed error
+	var failOnce sync.Once
+	workers := make([]*consumerSubWorker, c.subParallel)
+	wg := sync.WaitGroup{}
+	started := time.Now()
+
+	for i := range workers {
+		workers[i] = &consumerSubWorker{}
+
+		iter, err := cons.Messages(jetstream.PullMaxMessages(1))
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func(w *consumerSubWorker) {
+			defer wg.Done()
+
+			err := c.parallelSubscribeWorker(ctx, cancel, iter, w, &total)
+			if err != nil {
+				failOnce.Do(func() { failed = err })
+				cancel()
+			}
+		}(workers[i])
+
+		go func() {
+			<-ctx.Done()
+			iter.Stop()
+		}()
+	}
+
+	if !c.raw {
+		fmt.Printf("Subscribing to %s > %s using %d workers, acknowledgment: %v\n\n", consumer.StreamName(), consumer.Name(), c.subParallel, c.ack)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	interactive := iu.IsStdoutTerminal() && !c.raw
+
+	for {
+		select {
+		case <-ticker.C:
+			if interactive {
+				iu.ClearScreen()
+				c.renderParallelSubscribe(consumer, workers, time.Since(started))
+			}
+
+		case <-done:
+			if !c.raw {
+				if interactive {
+					iu.ClearScreen()
+				}
+				c.renderParallelSubscribe(consumer, workers, time.Since(started))
+			}
+
+			return failed
+		}
+	}
+}
+
+// parallelSubscribeWorker handles messages until the iterator is stopped, it backs off after transient errors and
+// returns errors that the consumer can not recover from
+func (c *consumerCmd) parallelSubscribeWorker(ctx context.Context, cancel func(), iter jetstream.MessagesContext, w *consumerSubWorker, total *atomic.Uint64) error {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	backoff := time.Duration(0)
+
+	for {
+		msg, err := iter.Next()
+		switch {
+		case errors.Is(err, jetstream.ErrMsgIteratorClosed):
+			return nil
+		case errors.Is(err, jetstream.ErrConsumerDeleted), errors.Is(err, jetstream.ErrConsumerNotFound), errors.Is(err, nats.ErrConnectionClosed):
+			w.errors.Add(1)
+			return err
+		case err != nil:
+			w.errors.Add(1)
+			backoff = min(max(2*backoff, 100*time.Millisecond), 5*time.Second)
+
+			select {
+			case <-time.After(backoff):
+				continue
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		backoff = 0
+
+		w.received.Add(1)
+
+		meta, err := msg.Metadata()
+		if err == nil && meta.NumDelivered > 1 {
+			w.redelivered.Add(1)
+		}
+
+		if c.raw {
+			fmt.Println(string(msg.Data()))
+		}
+
+		if c.ackWait > 0 {
+			select {
+			case <-time.After(time.Duration(r.Int63n(int64(c.ackWait)))):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		if c.ack {
+			err = msg.Ack()
+			if err != nil {
+				w.errors.Add(1)
+			} else {
+				w.acked.Add(1)
+			}
+		}
+
+		if c.subCount > 0 && total.Add(1) >= uint64(c.subCount) {
+			cancel()
+			return nil
+		}
+	}
+}
+
+func (c *consumerCmd) renderParallelSubscribe(consumer *jsm.Consumer, workers []*consumerSubWorker, elapsed time.Duration) {
+	var received, acked, redelivered, errs uint64
+
+	table := iu.NewTableWriterf(opts(), "%d workers on Consumer %s > %s after %s", len(workers), consumer.StreamName(), consumer.Name(), f(elapsed.Round(time.Second)))
+	table.AddHeaders("Worker", "Received", "Acknowledged", "Redelivered", "Errors", "Messages / sec")
+	for i, w := range workers {
+		wr, wa, wrd, we := w.received.Load(), w.acked.Load(), w.redelivered.Load(), w.errors.Load()
+		received += wr
+		acked += wa
+		redelivered += wrd
+		errs += we
+
+		table.AddRow(i+1, f(wr), f(wa), f(wrd), f(we), fFloat2Int(calculateRate(float64(wr), 0, elapsed)))
+	}
+	table.AddFooter("Total", f(received), f(acked), f(redelivered), f(errs), fFloat2Int(calculateRate(float64(received), 0, elapsed)))
+	fmt.Println(table.Render())
+
+	state, err := consumer.State()
+	if err == nil {
+		fmt.Printf("Consumer Ack Pending: %s / %s Redelivered: %s Unprocessed: %s\n", f(state.NumAckPending), f(state.Config.MaxAckPending), f(state.NumRedelivered), f(state.NumPending))
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/fatih/color"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// consumerWatchState tracks the recent history of a watched consumer
+type consumerWatchState struct {
+	lag           []uint64
+	lastDelivered uint64
+	lastSeen      time.Time
+	rate          float64
+}
+
+// lagGrowing determines if the last samples lag values grew every time they were taken
+func lagGrowing(lag []uint64, samples int) bool {
+	if samples < 2 || len(lag) < samples {
+		return false
+	}
+
+	recent := lag[len(lag)-samples:]
+	for i := 1; i < len(recent); i++ {
+		if recent[i] <= recent[i-1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *consumerWatchState) update(state api.ConsumerInfo, samples int) {
+	now := time.Now()
+	if !s.lastSeen.IsZero() {
+		s.rate = calculateRate(float64(state.Delivered.Consumer), float64(s.lastDelivered), now.Sub(s.lastSeen))
+	}
+
+	s.lastDelivered = state.Delivered.Consumer
+	s.lastSeen = now
+	s.lag = append(s.lag, state.NumPending)
+	if len(s.lag) > samples {
+		s.lag = s.lag[len(s.lag)-samples:]
+	}
+}
+
+func (c *consumerCmd) watchAction(_ *fisk.ParseContext) error {
+	if c.watchInterval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+	if c.watchSamples < 2 {
+		return fmt.Errorf("samples must be at least 2")
+	}
+
+	c.connectAndSetup(true, false)
+
+	stream, err := c.mgr.LoadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	if c.consumer != "" {
+		known, err := c.mgr.IsKnownConsumer(c.stream, c.consumer)
+		if err != nil {
+			return err
+		}
+		if !known {
+			return fmt.Errorf("consumer %s > %s does not exist", c.stream, c.consumer)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	watched := make(map[string]*consumerWatchState)
+	ticker := time.NewTicker(c.watchInterval)
+	defer ticker.Stop()
+
+	for i := 1; ; i++ {
+		err = c.renderConsumerWatch(stream, watched)
+		if err != nil {
+			return err
+		}
+
+		if c.watchCount > 0 && i >= c.watchCount {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// consumerStates loads the state of the selected consumer or, when none is selected, of all consumers on the stream
+func (c *consumerCmd) consumerStates(stream *jsm.Stream) (map[string]api.ConsumerInfo, error) {
+	states := make(map[string]api.ConsumerInfo)
+
+	if c.consumer != "" {
+		consumer, err := c.mgr.LoadConsumer(stream.Name(), c.consumer)
+		if err != nil {
+			return nil, err
+		}
+		states[consumer.Name()], err = consumer.LatestState()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, _, err := stream.EachConsumer(func(consumer *jsm.Consumer) {
+			state, err := consumer.LatestState()
+			if err == nil {
+				states[consumer.Name()] = state
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return states, nil
+}
+
+func (c *consumerCmd) renderConsumerWatch(stream *jsm.Stream, watched map[string]*consumerWatchState) error {
+	states, err := c.consumerStates(stream)
+	if err != nil {
+		return err
+	}
+
+	names := iu.MapKeys(states)
+	slices.Sort(names)
+
+	var growing []string
+	table := iu.NewTableWriterf(opts(), "Consumers on Stream %s at %s", stream.Name(), time.Now().Format(time.TimeOnly))
+	table.AddHeaders("Consumer", "Unprocessed", "Ack Pending", "Redelivered", "Delivered / sec", "Lag Trend")
+	for _, name := range names {
+		state := states[name]
+
+		ws, ok := watched[name]
+		if !ok {
+			ws = &consumerWatchState{}
+			watched[name] = ws
+		}
+		ws.update(state, c.watchSamples)
+
+		trend := ""
+		if lagGrowing(ws.lag, c.watchSamples) {
+			trend = "growing"
+			growing = append(growing, name)
+			if iu.IsStdoutTerminal() {
+				trend = color.RedString(trend)
+			}
+		}
+
+		table.AddRow(name, f(state.NumPending), f(state.NumAckPending), f(state.NumRedelivered), fFloat2Int(ws.rate), trend)
+	}
+
+	// forget consumers that were removed
+	for name := range watched {
+		if _, ok := states[name]; !ok {
+			delete(watched, name)
+		}
+	}
+
+	if iu.IsStdoutTerminal() {
+		iu.ClearScreen()
+	}
+
+	fmt.Println(table.Render())
+	if len(growing) > 0 {
+		fmt.Printf("Lag grew over the last %d samples for: %s\n\n", c.watchSamples, strings.Join(growing, ", "))
+	}
+
+	return nil
+}
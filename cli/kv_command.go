@@ -19,8 +19,10 @@ import (
 	"io"
 	"math"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -86,6 +88,11 @@ type kvCommand struct {
 	metadataIsSet           bool
 	metadata                map[string]string
 	noMirror                bool
+	file                    string
+	watchKeyRegex           string
+	watchIgnoreDeletes      bool
+	watchOnlyDeletes        bool
+	watchTemplate           string
 }
 
 func configureKVCommand(app commandHost) {
@@ -143,6 +150,7 @@ for an indefinite period or a per-bucket configured TTL.
 	put.Arg("bucket", "The bucket to act on").Required().StringVar(&c.bucket)
 	put.Arg("key", "The key to act on").Required().StringVar(&c.key)
 	put.Arg("value", "The value to store, when empty reads STDIN").StringVar(&c.val)
+	put.Flag("ttl", "Sets a TTL for the key, requires Per-Key TTLs to be enabled on the bucket").PlaceHolder("DURATION").DurationVar(&c.keyTTL)
 
 	get := kv.Command("get", "Gets a value for a key").Action(c.getAction)
 	get.Tag("scope:user", "impact:ro")
@@ -202,6 +210,10 @@ for an indefinite period or a per-bucket configured TTL.
 	watch.Flag("deletes", "Includes deletes in watched values").Default("true").BoolVar(&c.includeDeletes)
 	watch.Flag("updates", "Only show new values written").UnNegatableBoolVar(&c.updatesOnly)
 	watch.Flag("revision", "Starts from a certain revision").Uint64Var(&c.revision)
+	watch.Flag("key-regex", "Only show keys matching a regular expression").PlaceHolder("REGEX").StringVar(&c.watchKeyRegex)
+	watch.Flag("ignore-deletes", "Do not show deletes and purges").UnNegatableBoolVar(&c.watchIgnoreDeletes)
+	watch.Flag("only-deletes", "Only show deletes and purges").UnNegatableBoolVar(&c.watchOnlyDeletes)
+	watch.Flag("template", "Formats each entry using a Go template with access to .Bucket, .Key, .Revision, .Operation, .Created, .Value and .Headers").PlaceHolder("TEMPLATE").StringVar(&c.watchTemplate)
 
 	ls := kv.Command("ls", "List available buckets or the keys in a bucket").Alias("list").Action(c.lsAction)
 	ls.Tag("scope:user", "impact:ro")
@@ -211,6 +223,28 @@ for an indefinite period or a per-bucket configured TTL.
 	ls.Flag("verbose", "Show detailed info about the key").Short('v').UnNegatableBoolVar(&c.lsVerbose)
 	ls.Flag("display-value", "Display value in verbose output (has no effect without 'verbose')").UnNegatableBoolVar(&c.lsVerboseDisplayValue)
 
+	export := kv.Command("export", "Exports all keys including their history to a JSON Lines file").Action(c.exportAction)
+	export.Tag("scope:user", "impact:ro")
+	export.HelpLong(`Exports every revision held in the bucket, including delete and purge markers
+and message headers, to a file with one JSON document per line.
+
+Use kv import to load the file into another bucket, this allows buckets to be
+moved between clusters or renamed where a stream backup is not suitable.`)
+	export.Arg("bucket", "The bucket to act on").Required().StringVar(&c.bucket)
+	export.Arg("file", "The file to write the entries to").Required().StringVar(&c.file)
+
+	imp := kv.Command("import", "Imports keys and their history from a file written by kv export").Action(c.importAction)
+	imp.Tag("scope:user", "impact:rw")
+	imp.HelpLong(`Replays all entries from a file written using kv export into an existing bucket,
+create the bucket with the desired configuration first.
+
+The target bucket assigns new revisions and creation times to the entries, the
+history kept is limited by the history setting of the target bucket. Per key
+TTLs are not imported.`)
+	imp.Arg("bucket", "The bucket to act on").Required().StringVar(&c.bucket)
+	imp.Arg("file", "The file to read the entries from").Required().ExistingFileVar(&c.file)
+	imp.Flag("force", "Import into a bucket that already holds values without prompting").Short('f').UnNegatableBoolVar(&c.force)
+
 	rmHistory := kv.Command("compact", "Reclaim space used by deleted keys").Action(c.compactAction)
 	rmHistory.Tag("scope:user", "impact:rw")
 	rmHistory.Arg("bucket", "The bucket to act on").Required().StringVar(&c.bucket)
@@ -724,7 +758,7 @@ func (c *kvCommand) getAction(_ *fisk.ParseContext) error {
 }
 
 func (c *kvCommand) putAction(_ *fisk.ParseContext) error {
-	_, _, store, err := c.loadBucket()
+	_, js, store, err := c.loadBucket()
 	if err != nil {
 		return err
 	}
@@ -734,6 +768,10 @@ func (c *kvCommand) putAction(_ *fisk.ParseContext) error {
 		return err
 	}
 
+	if c.keyTTL > 0 {
+		return c.putWithTTL(js, store, val)
+	}
+
 	_, err = store.Put(ctx, c.key, val)
 	if err != nil {
 		return err
@@ -744,6 +782,50 @@ func (c *kvCommand) putAction(_ *fisk.ParseContext) error {
 	return err
 }
 
+// putWithTTL stores val with a per-key TTL, the KV API only supports TTLs on create so this publishes to the bucket directly
+func (c *kvCommand) putWithTTL(js jetstream.JetStream, store jetstream.KeyValue, val []byte) error {
+	status, err := store.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.BackingStore() != "JetStream" {
+		return errors.New(c.bucket + " is not a JetStream bucket")
+	}
+	nfo := status.(*jetstream.KeyValueBucketStatus).StreamInfo()
+
+	if status.LimitMarkerTTL() == 0 {
+		return fmt.Errorf("bucket %s does not support Per-Key TTLs, enable it using 'nats kv edit %s --marker-ttl'", c.bucket, c.bucket)
+	}
+
+	if c.keyTTL < time.Second {
+		return fmt.Errorf("key TTL must be at least 1s")
+	}
+
+	if status.TTL() > 0 && c.keyTTL > status.TTL() {
+		return fmt.Errorf("key TTL %v exceeds the bucket TTL of %v", c.keyTTL, status.TTL())
+	}
+
+	// buckets that mirror another bucket accept writes for the origin bucket
+	bucket := c.bucket
+	if nfo.Config.Mirror != nil {
+		bucket = strings.TrimPrefix(nfo.Config.Mirror.Name, "KV_")
+	}
+
+	msg := nats.NewMsg(fmt.Sprintf("$KV.%s.%s", bucket, c.key))
+	msg.Data = val
+
+	_, err = js.PublishMsg(ctx, msg, jetstream.WithMsgTTL(c.keyTTL))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(c.val)
+	fmt.Printf("Expires at %s\n", f(time.Now().Add(c.keyTTL)))
+
+	return nil
+}
+
 func (c *kvCommand) createAction(_ *fisk.ParseContext) error {
 	_, _, store, err := c.loadBucket()
 	if err != nil {
@@ -862,14 +944,88 @@ func (c *kvCommand) infoAction(_ *fisk.ParseContext) error {
 	return c.showStatus(store)
 }
 
+// kvWatchEntry is the data available to kv watch output templates
+type kvWatchEntry struct {
+	Bucket    string
+	Key       string
+	Revision  uint64
+	Operation string
+	Created   time.Time
+	Value     string
+	Headers   nats.Header
+}
+
+// watchMatches determines if a watched entry passes the key and operation filters
+func (c *kvCommand) watchMatches(keyRe *regexp.Regexp, entry jetstream.KeyValueEntry) bool {
+	if keyRe != nil && !keyRe.MatchString(entry.Key()) {
+		return false
+	}
+
+	if c.watchIgnoreDeletes && entry.Operation() != jetstream.KeyValuePut {
+		return false
+	}
+
+	return !c.watchOnlyDeletes || entry.Operation() != jetstream.KeyValuePut
+}
+
+// renderKVWatchTemplate renders a watched entry using tmpl ensuring the output ends in a new line
+func renderKVWatchTemplate(tmpl *template.Template, entry kvWatchEntry) (string, error) {
+	var out strings.Builder
+	err := tmpl.Execute(&out, entry)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(out.String(), "\n") {
+		out.WriteString("\n")
+	}
+
+	return out.String(), nil
+}
+
 func (c *kvCommand) watchAction(_ *fisk.ParseContext) error {
-	_, _, store, err := c.loadBucket()
+	if c.watchIgnoreDeletes && c.watchOnlyDeletes {
+		return fmt.Errorf("--ignore-deletes and --only-deletes can not be used together")
+	}
+
+	var keyRe *regexp.Regexp
+	var err error
+	if c.watchKeyRegex != "" {
+		keyRe, err = regexp.Compile(c.watchKeyRegex)
+		if err != nil {
+			return fmt.Errorf("invalid key regular expression: %w", err)
+		}
+	}
+
+	var tmpl *template.Template
+	if c.watchTemplate != "" {
+		tmpl, err = template.New("watch").Parse(c.watchTemplate)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+
+	_, js, store, err := c.loadBucket()
 	if err != nil {
 		return err
 	}
 
+	// headers are not part of watched entries so they are only fetched from the stream when the template needs them
+	var stream jetstream.Stream
+	if tmpl != nil && strings.Contains(c.watchTemplate, ".Headers") {
+		status, err := store.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		stream, err = js.Stream(ctx, status.(*jetstream.KeyValueBucketStatus).StreamInfo().Config.Name)
+		if err != nil {
+			return err
+		}
+	}
+
 	var opts []jetstream.WatchOpt
-	if !c.includeDeletes {
+	if !c.includeDeletes || c.watchIgnoreDeletes {
 		opts = append(opts, jetstream.IgnoreDeletes())
 	}
 	if c.includeHistory {
@@ -893,6 +1049,37 @@ func (c *kvCommand) watchAction(_ *fisk.ParseContext) error {
 			continue
 		}
 
+		if !c.watchMatches(keyRe, res) {
+			continue
+		}
+
+		if tmpl != nil {
+			entry := kvWatchEntry{
+				Bucket:    res.Bucket(),
+				Key:       res.Key(),
+				Revision:  res.Revision(),
+				Operation: c.strForOp(res.Operation()),
+				Created:   res.Created(),
+				Value:     string(res.Value()),
+			}
+
+			if stream != nil {
+				msg, err := stream.GetMsg(ctx, res.Revision())
+				if err == nil {
+					entry.Headers = msg.Header
+				}
+			}
+
+			out, err := renderKVWatchTemplate(tmpl, entry)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(out)
+
+			continue
+		}
+
 		switch res.Operation() {
 		case jetstream.KeyValueDelete, jetstream.KeyValuePurge:
 			fmt.Printf("[%s] %s %s > %s\n", f(res.Created()), color.RedString(c.strForOp(res.Operation())), res.Bucket(), res.Key())
@@ -0,0 +1,87 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"regexp"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type testKVEntry struct {
+	key string
+	op  jetstream.KeyValueOp
+}
+
+func (e testKVEntry) Bucket() string                  { return "T" }
+func (e testKVEntry) Key() string                     { return e.key }
+func (e testKVEntry) Value() []byte                   { return nil }
+func (e testKVEntry) Revision() uint64                { return 1 }
+func (e testKVEntry) Created() time.Time              { return time.Time{} }
+func (e testKVEntry) Delta() uint64                   { return 0 }
+func (e testKVEntry) Operation() jetstream.KeyValueOp { return e.op }
+
+func TestKVWatchMatches(t *testing.T) {
+	put := testKVEntry{key: "users.1", op: jetstream.KeyValuePut}
+	del := testKVEntry{key: "users.2", op: jetstream.KeyValueDelete}
+	purge := testKVEntry{key: "orders.1", op: jetstream.KeyValuePurge}
+
+	c := &kvCommand{}
+	for _, e := range []testKVEntry{put, del, purge} {
+		if !c.watchMatches(nil, e) {
+			t.Fatalf("expected %s to match without filters", e.key)
+		}
+	}
+
+	re := regexp.MustCompile(`^users\.`)
+	if !c.watchMatches(re, put) || c.watchMatches(re, purge) {
+		t.Fatalf("key regex did not filter keys")
+	}
+
+	c.watchIgnoreDeletes = true
+	if !c.watchMatches(nil, put) || c.watchMatches(nil, del) || c.watchMatches(nil, purge) {
+		t.Fatalf("ignore deletes did not filter deletes")
+	}
+
+	c = &kvCommand{watchOnlyDeletes: true}
+	if c.watchMatches(nil, put) || !c.watchMatches(nil, del) || !c.watchMatches(nil, purge) {
+		t.Fatalf("only deletes did not filter puts")
+	}
+	if c.watchMatches(re, purge) {
+		t.Fatalf("key regex did not apply to deletes")
+	}
+}
+
+func TestRenderKVWatchTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("watch").Parse(`{{ .Operation }} {{ .Key }}@{{ .Revision }}={{ .Value }} {{ .Headers.Get "X-Meta" }}`))
+
+	out, err := renderKVWatchTemplate(tmpl, kvWatchEntry{
+		Key:       "users.1",
+		Revision:  10,
+		Operation: "PUT",
+		Value:     "bob",
+		Headers:   nats.Header{"X-Meta": []string{"meta"}},
+	})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	if out != "PUT users.1@10=bob meta\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	kvOperationHeader = "KV-Operation"
+	kvRollupHeader    = "Nats-Rollup"
+)
+
+// kvImportSkipHeaders are set by the server and can not be replayed into another bucket
+var kvImportSkipHeaders = []string{"Nats-Stream-Source", jetstream.MarkerReasonHeader}
+
+// kvExportEntry is a single revision of a key as stored in the export file
+type kvExportEntry struct {
+	Key       string      `json:"key"`
+	Revision  uint64      `json:"revision"`
+	Operation string      `json:"operation"`
+	Created   time.Time   `json:"created"`
+	Value     []byte      `json:"value,omitempty"`
+	Headers   nats.Header `json:"headers,omitempty"`
+}
+
+// kvKeyFromSubject removes the $KV.<bucket>. prefix, the bucket name is not used as mirrors retain the origin subjects
+func kvKeyFromSubject(subject string) (string, error) {
+	parts := strings.SplitN(subject, ".", 3)
+	if len(parts) != 3 || parts[0] != "$KV" {
+		return "", fmt.Errorf("invalid key subject %q", subject)
+	}
+
+	return parts[2], nil
+}
+
+// kvOperation determines the operation of a stored entry like nats.go does, markers placed by the server only
+// carry the reason they were placed
+func kvOperation(hdr nats.Header) jetstream.KeyValueOp {
+	switch hdr.Get(kvOperationHeader) {
+	case "DEL":
+		return jetstream.KeyValueDelete
+	case "PURGE":
+		return jetstream.KeyValuePurge
+	case "":
+		switch hdr.Get(jetstream.MarkerReasonHeader) {
+		case "MaxAge", "Purge":
+			return jetstream.KeyValuePurge
+		case "Remove":
+			return jetstream.KeyValueDelete
+		}
+	}
+
+	return jetstream.KeyValuePut
+}
+
+func (c *kvCommand) exportAction(_ *fisk.ParseContext) error {
+	_, js, store, err := c.loadBucket()
+	if err != nil {
+		return err
+	}
+
+	status, err := store.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(c.file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w := bufio.NewWriter(out)
+
+	var entries int
+	keys := make(map[string]struct{})
+
+	if status.Values() > 0 {
+		cons, err := js.OrderedConsumer(ctx, status.(*jetstream.KeyValueBucketStatus).StreamInfo().Config.Name, jetstream.OrderedConsumerConfig{})
+		if err != nil {
+			return err
+		}
+
+		iter, err := cons.Messages()
+		if err != nil {
+			return err
+		}
+		defer iter.Stop()
+
+		for {
+			msg, err := iter.Next()
+			if err != nil {
+				return err
+			}
+
+			meta, err := msg.Metadata()
+			if err != nil {
+				return err
+			}
+
+			key, err := kvKeyFromSubject(msg.Subject())
+			if err != nil {
+				return err
+			}
+
+			entry := kvExportEntry{
+				Key:       key,
+				Revision:  meta.Sequence.Stream,
+				Operation: c.strForOp(kvOperation(msg.Headers())),
+				Created:   meta.Timestamp,
+				Value:     msg.Data(),
+				Headers:   msg.Headers(),
+			}
+
+			ej, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(w, string(ej))
+			if err != nil {
+				return err
+			}
+
+			entries++
+			keys[key] = struct{}{}
+
+			if meta.NumPending == 0 {
+				break
+			}
+		}
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %s entries for %s keys from bucket %s to %s\n", f(entries), f(len(keys)), c.bucket, c.file)
+
+	return nil
+}
+
+func (c *kvCommand) importAction(_ *fisk.ParseContext) error {
+	_, js, store, err := c.loadBucket()
+	if err != nil {
+		return err
+	}
+
+	status, err := store.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Values() > 0 && !c.force {
+		ok, err := askConfirmation(fmt.Sprintf("Bucket %s holds %s values, really import %s into it", c.bucket, f(status.Values()), c.file), false)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			fmt.Println("Skipping import")
+			return nil
+		}
+	}
+
+	prefix := fmt.Sprintf("$KV.%s.", c.bucket)
+	if opts().JsDomain != "" {
+		prefix = fmt.Sprintf("$JS.%s.API.$KV.%s.", opts().JsDomain, c.bucket)
+	}
+
+	in, err := os.Open(c.file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var entries int
+	keys := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 128*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var entry kvExportEntry
+		err = json.Unmarshal(line, &entry)
+		if err != nil {
+			return fmt.Errorf("invalid entry on line %d: %w", entries+1, err)
+		}
+
+		if entry.Key == "" {
+			return fmt.Errorf("invalid entry on line %d: no key", entries+1)
+		}
+
+		msg := nats.NewMsg(prefix + entry.Key)
+		msg.Data = entry.Value
+		for k, vals := range entry.Headers {
+			// revision expectations of Create and Update and the TTLs would be acted on again
+			if !republishHeader(k, false, false) {
+				continue
+			}
+			for _, v := range vals {
+				msg.Header.Add(k, v)
+			}
+		}
+		for _, h := range kvImportSkipHeaders {
+			msg.Header.Del(h)
+		}
+
+		switch entry.Operation {
+		case c.strForOp(jetstream.KeyValueDelete):
+			msg.Header.Set(kvOperationHeader, "DEL")
+		case c.strForOp(jetstream.KeyValuePurge):
+			msg.Header.Set(kvOperationHeader, "PURGE")
+			msg.Header.Set(kvRollupHeader, "sub")
+		}
+
+		_, err = js.PublishMsg(ctx, msg)
+		if err != nil {
+			var apiErr *jetstream.APIError
+			if errors.As(err, &apiErr) {
+				return fmt.Errorf("importing revision %d of key %s failed: %s", entry.Revision, entry.Key, apiErr.Description)
+			}
+			return fmt.Errorf("importing revision %d of key %s failed: %w", entry.Revision, entry.Key, err)
+		}
+
+		entries++
+		keys[entry.Key] = struct{}{}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %s entries for %s keys into bucket %s\n", f(entries), f(len(keys)), c.bucket)
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/nats.go"
+)
+
+func (c *SrvCheckCmd) checkAccess(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.accessSubject, Check: "access", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	switch {
+	case !c.accessPub && !c.accessSub:
+		check.Critical("--pub or --sub is required")
+		return nil
+	case c.accessPub && strings.ContainsAny(c.accessSubject, "*>"):
+		check.Critical("cannot publish to a wildcard subject")
+		return nil
+	}
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		var sub *nats.Subscription
+		var subDenied, pubDenied bool
+		var err error
+
+		if c.accessSub {
+			subDenied, err = c.accessDenied(nc, "subscription", func() error {
+				sub, err = nc.SubscribeSync(c.accessSubject)
+				return err
+			})
+			if sub != nil {
+				defer sub.Unsubscribe()
+			}
+			if err != nil {
+				return err
+			}
+
+			c.accessResult(check, "subscribe", subDenied)
+		}
+
+		if c.accessPub {
+			pubDenied, err = c.accessDenied(nc, "publish", func() error {
+				return nc.Publish(c.accessSubject, []byte("nats server check access"))
+			})
+			if err != nil {
+				return err
+			}
+
+			c.accessResult(check, "publish", pubDenied)
+		}
+
+		// when both are allowed we also confirm the message made it through
+		if sub != nil && !c.accessExpectDeny && !subDenied && c.accessPub && !pubDenied {
+			_, err = sub.NextMsg(c.requestTimeout())
+			if err != nil {
+				check.Criticalf("published message was not received: %v", err)
+			}
+		}
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// accessResult records the outcome of a probe honoring --expect-deny
+func (c *SrvCheckCmd) accessResult(check *monitor.Result, action string, denied bool) {
+	switch {
+	case denied && c.accessExpectDeny:
+		check.Okf("%s denied", action)
+	case denied:
+		check.Criticalf("%s denied", action)
+	case c.accessExpectDeny:
+		check.Criticalf("%s allowed", action)
+	default:
+		check.Okf("%s allowed", action)
+	}
+}
+
+// accessDenied calls cb and reports if the server rejected it with a permissions violation, action is the operation in the server error like publish or subscription
+func (c *SrvCheckCmd) accessDenied(nc *nats.Conn, action string, cb func() error) (bool, error) {
+	before := nc.LastError()
+
+	err := cb()
+	if err != nil {
+		return false, err
+	}
+
+	// the server reports violations before responding to the flush ping
+	err = nc.FlushTimeout(c.requestTimeout())
+	if err != nil {
+		return false, err
+	}
+
+	last := nc.LastError()
+	if last == nil || last == before || !errors.Is(last, nats.ErrPermissionViolation) {
+		return false, nil
+	}
+
+	return strings.Contains(strings.ToLower(last.Error()), "for "+action), nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+)
+
+func (c *SrvCheckCmd) checkCertificate(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: "Certificate", Check: "certificate", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	addresses := c.certAddresses
+	if len(addresses) == 0 {
+		if opts().Config == nil {
+			err := loadContext(false)
+			if check.CriticalIfErrf(err, "loading context failed: %v", err) {
+				return nil
+			}
+		}
+
+		addresses = strings.Split(opts().Config.ServerURL(), ",")
+	}
+
+	err := c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		var soonest time.Duration
+		var checked int
+
+		for _, addr := range addresses {
+			addr = strings.TrimSpace(addr)
+
+			certs, err := c.peerCertificates(addr)
+			if err != nil {
+				check.Criticalf("%s: %v", addr, err)
+				continue
+			}
+
+			for _, cert := range certs {
+				remaining := time.Until(cert.NotAfter)
+				if checked == 0 || remaining < soonest {
+					soonest = remaining
+				}
+				checked++
+
+				switch {
+				case remaining <= 0:
+					check.Criticalf("%s: certificate %q expired %v ago", addr, cert.Subject.CommonName, f(-remaining))
+				case c.certValidityCrit > 0 && remaining <= c.certValidityCrit:
+					check.Criticalf("%s: certificate %q expires in %v", addr, cert.Subject.CommonName, f(remaining))
+				case c.certValidityWarn > 0 && remaining <= c.certValidityWarn:
+					check.Warnf("%s: certificate %q expires in %v", addr, cert.Subject.CommonName, f(remaining))
+				}
+			}
+		}
+
+		if checked > 0 {
+			check.Pd(&monitor.PerfDataItem{Name: "expiry", Value: soonest.Seconds(), Warn: c.certValidityWarn.Seconds(), Crit: c.certValidityCrit.Seconds(), Unit: "s", Help: "Seconds until the first certificate expires"})
+		}
+
+		check.OkIfNoWarningsOrCriticalsf("%d certificates valid for at least %v", checked, f(soonest))
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// peerCertificates retrieves the certificates presented by addr, nats:// and tls:// addresses are upgraded to TLS after the NATS INFO while https:// and wss:// addresses use TLS immediately
+func (c *SrvCheckCmd) peerCertificates(addr string) ([]*x509.Certificate, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "nats://" + addr
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "https", "wss":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		default:
+			host = net.JoinHostPort(u.Hostname(), "4222")
+		}
+	}
+
+	timeout := c.requestTimeout()
+
+	conn, err := net.DialTimeout("tcp", host, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "https", "wss":
+	case "nats", "tls":
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("could not read server INFO: %w", err)
+		}
+
+		jinfo, ok := strings.CutPrefix(strings.TrimSpace(line), "INFO ")
+		if !ok {
+			return nil, fmt.Errorf("invalid server INFO received")
+		}
+
+		var info struct {
+			TLSRequired  bool `json:"tls_required"`
+			TLSAvailable bool `json:"tls_available"`
+		}
+		err = json.Unmarshal([]byte(jinfo), &info)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server INFO received: %w", err)
+		}
+
+		if !info.TLSRequired && !info.TLSAvailable {
+			return nil, fmt.Errorf("TLS is not enabled")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	// we only inspect the presented certificates, validity of the chain is checked by connection checks
+	tconn := tls.Client(conn, &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: true})
+	err = tconn.Handshake()
+	if err != nil {
+		return nil, err
+	}
+
+	return tconn.ConnectionState().PeerCertificates, nil
+}
@@ -14,17 +14,40 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"regexp"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/nats-io/jsm.go"
 	"github.com/nats-io/jsm.go/api"
 	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
 
 	"github.com/choria-io/fisk"
 )
 
 type SrvCheckCmd struct {
+	checkTimeout  time.Duration
+	perfSuppress  []*regexp.Regexp
+	watchInterval time.Duration
+	watchSubject  string
+	statusSubject string
+	nc            *nats.Conn
+
+	connectTimeout       time.Duration
+	connectRetries       int
+	connectRetryInterval time.Duration
+	connectAttempts      int
+	connectSpent         time.Duration
+	connectErr           error
+
 	connectWarning  time.Duration
 	connectCritical time.Duration
 	rttWarning      time.Duration
@@ -49,6 +72,9 @@ type SrvCheckCmd struct {
 	subjectsWarnIsSet        bool
 	subjectsCrit             int
 	subjectsCritIsSet        bool
+	streamRateWarn           float64
+	streamRateCrit           float64
+	streamRateStateFile      string
 
 	consumerName                        string
 	consumerAckOutstandingCritical      int
@@ -63,6 +89,12 @@ type SrvCheckCmd struct {
 	consumerLastAckCriticalIsSet        bool
 	consumerRedeliveryCritical          int
 	consumerRedeliveryCriticalIsSet     bool
+	consumerAckOutstandingWarn          int
+	consumerWaitingWarn                 int
+	consumerUnprocessedWarn             int
+	consumerLastDeliveryWarn            time.Duration
+	consumerLastAckWarn                 time.Duration
+	consumerRedeliveryWarn              int
 	consumerPinned                      bool
 
 	raftExpect            int
@@ -76,6 +108,25 @@ type SrvCheckCmd struct {
 	jsMemCritical         int
 	jsStoreWarn           int
 	jsStoreCritical       int
+	jsSrvMemWarn          int
+	jsSrvMemCrit          int
+	jsSrvStoreWarn        int
+	jsSrvStoreCrit        int
+	jsAccount             bool
+	jsStreamCountWarn     int
+	streamConsumersWarn   int
+	streamConsumersCrit   int
+	raftLeaderFile        string
+	raftLeaderChanged     string
+	raftLeaderWindow      time.Duration
+	raftLeaderChangesWarn int
+	raftLeaderChangesCrit int
+	raftAPIStats          bool
+	raftAPIInflightWarn   int
+	raftAPIInflightCrit   int
+	raftAPIPendingWarn    int
+	raftAPIPendingCrit    int
+	jsStreamCountCrit     int
 	jsStreamsWarn         int
 	jsStreamsCritical     int
 	jsConsumersWarn       int
@@ -100,31 +151,93 @@ type SrvCheckCmd struct {
 	srvJSRequired     bool
 	srvtlsExpiredWarn time.Duration
 	srvtlsExpiredCrit time.Duration
-
-	msgSubject      string
-	msgAgeWarn      time.Duration
-	msgAgeCrit      time.Duration
-	msgRegexp       *regexp.Regexp
-	msgBodyAsTs     bool
-	msgHeaders      map[string]string
-	msgHeadersMatch map[string]string
-	msgPayload      string
-	msgCrit         time.Duration
-	msgWarn         time.Duration
+	srvExpectAccounts int
+	srvMaxAccounts    int
+	srvConnPoolWarn   int
+	srvConnPoolCrit   int
+	srvRequireWS      bool
+	srvJSAPIQueueWarn int
+	srvJSAPIQueueCrit int
+	checkAllStreams   bool
+	checkAllConsumers bool
+	leafExpect        int
+	leafStateFile     string
+	gwExpect          []string
+	gwMinUptime       time.Duration
+	certAddresses     []string
+	certValidityWarn  time.Duration
+	certValidityCrit  time.Duration
+
+	msgSubject         string
+	msgAgeWarn         time.Duration
+	msgAgeCrit         time.Duration
+	msgRegexp          *regexp.Regexp
+	msgBodyAsTs        bool
+	msgTimestampPath   string
+	msgTimestampHeader string
+	msgValuePath       string
+	msgValueWarn       float64
+	msgValueCrit       float64
+	msgHeaders         map[string]string
+	msgHeadersMatch    map[string]string
+	msgPayload         string
+	msgCrit            time.Duration
+	msgWarn            time.Duration
 
 	kvBucket                 string
 	kvValuesCrit             int64
 	kvValuesWarn             int64
 	kvKey                    string
+	kvKeyAgeWarn             time.Duration
+	kvKeyAgeCrit             time.Duration
+	objBucket                string
+	objSizeWarn              string
+	objSizeCrit              string
+	objCountWarn             int
+	objCountCrit             int
+	objAgeWarn               time.Duration
+	objAgeCrit               time.Duration
 	credentialValidityCrit   time.Duration
 	credentialValidityWarn   time.Duration
 	credentialRequiresExpire bool
 	credential               string
+	credentialDir            string
 
 	exporterConfigFile  string
 	exporterPort        int
 	exporterCertificate string
 	exporterKey         string
+
+	allServers bool
+
+	srvRoutesExpect int
+	slowWarn        int
+	slowCrit        int
+	slowStateFile   string
+
+	suiteConfigFile string
+	suiteIndividual bool
+
+	accessSubject    string
+	accessPub        bool
+	accessSub        bool
+	accessExpectDeny bool
+
+	svcName          string
+	svcInstances     int
+	svcErrorRateWarn float64
+	svcErrorRateCrit float64
+	svcAvgTimeWarn   time.Duration
+	svcAvgTimeCrit   time.Duration
+
+	acctName     string
+	acctConnWarn int
+	acctConnCrit int
+	acctConnMin  int
+
+	listenerAddress       string
+	listenerHandshakeWarn time.Duration
+	listenerHandshakeCrit time.Duration
 }
 
 func configureServerCheckCommand(srv *fisk.CmdClause) {
@@ -138,12 +251,37 @@ func configureServerCheckCommand(srv *fisk.CmdClause) {
 	const inversion = "For most flags setting critical to a smaller value than warn will invert the check from >= to <=\n\n"
 
 	check := srv.Command("check", "Health check for NATS servers")
-	check.Flag("format", "Render the check in a specific format (nagios, json, prometheus, text)").Default("nagios").EnumVar(&checkRenderFormatText, "nagios", "json", "prometheus", "text")
+	check.HelpLong(`Checks are rendered in the Nagios format by default. The json format renders the full
+result including the status, messages and perf data while the prometheus format renders
+gauges suitable for the node_exporter textfile collector.
+
+The zabbix format renders a low level discovery item followed by key=value items like
+nats_server_check.stream[ORDERS,messages]=10 while the checkmk format renders a local
+check line.
+
+The exit code is 0 for OK, 1 for WARNING, 2 for CRITICAL and 3 for UNKNOWN in all formats
+other than prometheus, zabbix and checkmk which always exit 0. Systems that only understand
+success or failure can use --critical-only to exit 1 for critical results and 0 otherwise,
+or --warning-as-ok to exit 0 for warnings.
+
+With --status-subject the check, name, status and exit code of every check is published
+as JSON to the subject.`)
+	check.Flag("format", "Render the check in a specific format (nagios, json, prometheus, text, zabbix, checkmk)").Default("nagios").EnumVar(&checkRenderFormatText, "nagios", "json", "prometheus", "text", "zabbix", "checkmk")
 	check.Flag("namespace", "The prometheus namespace to use in output").Default(opts().PrometheusNamespace).StringVar(&opts().PrometheusNamespace)
 	check.Flag("outfile", "Save output to a file rather than STDOUT").StringVar(&checkRenderOutFile)
+	check.Flag("check-timeout", "Maximum time allowed for the check to complete, excluding connection setup").PlaceHolder("DURATION").DurationVar(&c.checkTimeout)
+	check.Flag("suppress-perf-item", "Regular expression matching perf data items to omit from the output, thresholds are still checked").PlaceHolder("PATTERN").RegexpListVar(&c.perfSuppress)
+	check.Flag("watch", "Runs the check repeatedly at this interval publishing results as JSON").PlaceHolder("INTERVAL").DurationVar(&c.watchInterval)
+	check.Flag("watch-subject", "Subject to publish results to when watching, defaults to $NATS.CHECK.<check>").PlaceHolder("SUBJECT").StringVar(&c.watchSubject)
+	check.Flag("connect-timeout", "Maximum time to wait for a connection to be established").PlaceHolder("DURATION").DurationVar(&c.connectTimeout)
+	check.Flag("retries", "Number of times to retry failed connections before reporting a failure").Default("0").IntVar(&c.connectRetries)
+	check.Flag("retry-interval", "Time to wait between connection retries").Default("1s").PlaceHolder("DURATION").DurationVar(&c.connectRetryInterval)
+	check.Flag("critical-only", "Exits 1 only for critical or unknown results and 0 otherwise").UnNegatableBoolVar(&checkCriticalOnly)
+	check.Flag("warning-as-ok", "Exits 0 for warning results").UnNegatableBoolVar(&checkWarningAsOK)
+	check.Flag("status-subject", "Subject to publish the status of every check to").PlaceHolder("SUBJECT").StringVar(&c.statusSubject)
 	check.PreAction(c.parseRenderFormat)
 
-	conn := check.Command("connection", "Checks basic server connection").Alias("conn").Action(c.checkConnection)
+	conn := check.Command("connection", "Checks basic server connection").Alias("conn").Action(c.watchable(c.checkConnection))
 	conn.Tag("scope:user", "impact:ro")
 	conn.HelpLong(multipleChecks + warnAndCritical)
 	conn.Flag("connect-warn", "Warning threshold to allow for establishing connections").Default("500ms").DurationVar(&c.connectWarning)
@@ -152,15 +290,17 @@ func configureServerCheckCommand(srv *fisk.CmdClause) {
 	conn.Flag("rtt-critical", "Critical threshold to allow for server RTT").Default("1s").DurationVar(&c.rttCritical)
 	conn.Flag("req-warn", "Warning threshold to allow for full round trip test").Default("500ms").DurationVar(&c.reqWarning)
 	conn.Flag("req-critical", "Critical threshold to allow for full round trip test").Default("1s").DurationVar(&c.reqCritical)
+	conn.Flag("all-servers", "Checks connections to every server discovered using the system account").UnNegatableBoolVar(&c.allServers)
 
-	stream := check.Command("stream", "Checks the health of mirrored streams, streams with sources or clustered streams").Action(c.checkStream)
+	stream := check.Command("stream", "Checks the health of mirrored streams, streams with sources or clustered streams").Action(c.watchable(c.checkStream))
 	stream.Tag("scope:user", "impact:ro")
 	stream.HelpLong(multipleChecks + warnAndCritical + inversion + `These settings can be set using Stream Metadata in the following form:
 
 	io.nats.monitor.lag-critical: 200
 
 When set these settings will be used, but can be overridden using --lag-critical.`)
-	stream.Flag("stream", "The streams to check").Required().StringVar(&c.sourcesStream)
+	stream.Flag("stream", "The streams to check").StringVar(&c.sourcesStream)
+	stream.Flag("check-all-streams", "Checks all streams in the account and reports the worst status").UnNegatableBoolVar(&c.checkAllStreams)
 	stream.Flag("lag-critical", "Critical threshold to allow for lag on any source or mirror").PlaceHolder("MSGS").IsSetByUser(&c.sourcesLagCriticalIsSet).Uint64Var(&c.sourcesLagCritical)
 	stream.Flag("seen-critical", "Critical threshold for how long ago the source or mirror should have been seen").PlaceHolder("DURATION").IsSetByUser(&c.sourcesSeenCriticalIsSet).DurationVar(&c.sourcesSeenCritical)
 	stream.Flag("min-sources", "Minimum number of sources to expect").PlaceHolder("SOURCES").IsSetByUser(&c.sourcesMinSourcesIsSet).IntVar(&c.sourcesMinSources)
@@ -172,8 +312,18 @@ When set these settings will be used, but can be overridden using --lag-critical
 	stream.Flag("msgs-critical", "Critical if there are fewer than this many messages in the stream").PlaceHolder("MSGS").IsSetByUser(&c.streamMessagesCritIsSet).Uint64Var(&c.streamMessagesCrit)
 	stream.Flag("subjects-warn", "Critical threshold for subjects in the stream").PlaceHolder("SUBJECTS").IsSetByUser(&c.subjectsWarnIsSet).IntVar(&c.subjectsWarn)
 	stream.Flag("subjects-critical", "Warning threshold for subjects in the stream").PlaceHolder("SUBJECTS").IsSetByUser(&c.subjectsCritIsSet).IntVar(&c.subjectsCrit)
-
-	consumer := check.Command("consumer", "Checks the health of a consumer").Action(c.checkConsumer)
+	stream.Flag("msgs-rate-warn", "Warning threshold for messages received per second since the previous check, supports inversion").PlaceHolder("RATE").Float64Var(&c.streamRateWarn)
+	stream.Flag("msgs-rate-crit", "Critical threshold for messages received per second since the previous check, supports inversion").PlaceHolder("RATE").Float64Var(&c.streamRateCrit)
+	stream.Flag("state-file", "Stores stream sequences between runs to calculate message rates").PlaceHolder("FILE").StringVar(&c.streamRateStateFile)
+
+	streamConsumers := check.Command("stream-consumers", "Checks the number of consumers on a stream").Action(c.watchable(c.checkStreamConsumers))
+	streamConsumers.Tag("scope:user", "impact:ro")
+	streamConsumers.HelpLong(warnAndCritical + inversion)
+	streamConsumers.Flag("stream", "The stream to check").Required().StringVar(&c.sourcesStream)
+	streamConsumers.Flag("consumers-warn", "Warning threshold for the number of consumers").PlaceHolder("CONSUMERS").IntVar(&c.streamConsumersWarn)
+	streamConsumers.Flag("consumers-crit", "Critical threshold for the number of consumers").PlaceHolder("CONSUMERS").IntVar(&c.streamConsumersCrit)
+
+	consumer := check.Command("consumer", "Checks the health of a consumer").Action(c.watchable(c.checkConsumer))
 	consumer.Tag("scope:user", "impact:ro")
 	consumer.HelpLong(multipleChecks + `These settings can be set using Consumer Metadata in the following form:
 
@@ -181,62 +331,112 @@ When set these settings will be used, but can be overridden using --lag-critical
 
 When set these settings will be used, but can be overridden using --waiting-critical.`)
 	consumer.Flag("stream", "The streams to check").Required().StringVar(&c.sourcesStream)
-	consumer.Flag("consumer", "The consumer to check").Required().StringVar(&c.consumerName)
+	consumer.Flag("consumer", "The consumer to check").StringVar(&c.consumerName)
+	consumer.Flag("all-consumers", "Checks all consumers on the stream and reports the worst status").UnNegatableBoolVar(&c.checkAllConsumers)
 	consumer.Flag("outstanding-ack-critical", "Maximum number of outstanding acks to allow").Default("-1").IsSetByUser(&c.consumerAckOutstandingCriticalIsSet).IntVar(&c.consumerAckOutstandingCritical)
 	consumer.Flag("waiting-critical", "Maximum number of waiting pulls to allow").Default("-1").IsSetByUser(&c.consumerWaitingCriticalIsSet).IntVar(&c.consumerWaitingCritical)
 	consumer.Flag("unprocessed-critical", "Maximum number of unprocessed messages to allow").Default("-1").IsSetByUser(&c.consumerUnprocessedCriticalIsSet).IntVar(&c.consumerUnprocessedCritical)
 	consumer.Flag("last-delivery-critical", "Time to allow since the last delivery").Default("0s").IsSetByUser(&c.consumerLastDeliveryCriticalIsSet).DurationVar(&c.consumerLastDeliveryCritical)
 	consumer.Flag("last-ack-critical", "Time to allow since the last ack").Default("0s").IsSetByUser(&c.consumerLastAckCriticalIsSet).DurationVar(&c.consumerLastAckCritical)
 	consumer.Flag("redelivery-critical", "Maximum number of redeliveries to allow").Default("-1").IsSetByUser(&c.consumerRedeliveryCriticalIsSet).IntVar(&c.consumerRedeliveryCritical)
+	consumer.Flag("outstanding-ack-warn", "Number of outstanding acks that will raise a warning").Default("0").IntVar(&c.consumerAckOutstandingWarn)
+	consumer.Flag("waiting-warn", "Number of waiting pulls that will raise a warning").Default("0").IntVar(&c.consumerWaitingWarn)
+	consumer.Flag("unprocessed-warn", "Number of unprocessed messages that will raise a warning").Default("0").IntVar(&c.consumerUnprocessedWarn)
+	consumer.Flag("last-delivery-warn", "Time since the last delivery that will raise a warning").Default("0s").DurationVar(&c.consumerLastDeliveryWarn)
+	consumer.Flag("last-ack-warn", "Time since the last ack that will raise a warning").Default("0s").DurationVar(&c.consumerLastAckWarn)
+	consumer.Flag("redelivery-warn", "Number of redeliveries that will raise a warning").Default("0").IntVar(&c.consumerRedeliveryWarn)
 	consumer.Flag("pinned", "Requires Pinned Client priority with all groups having a pinned client").UnNegatableBoolVar(&c.consumerPinned)
 
-	msg := check.Command("message", "Checks properties of a message stored in a stream").Action(c.checkMsg)
+	msg := check.Command("message", "Checks properties of a message stored in a stream").Action(c.watchable(c.checkMsg))
 	msg.Tag("scope:user", "impact:ro")
-	msg.HelpLong(multipleChecks + warnAndCritical)
+	msg.HelpLong(multipleChecks + warnAndCritical + inversion + `JSON paths are dot separated keys and array indexes like data.items.0.ts`)
 	msg.Flag("stream", "The streams to check").Required().StringVar(&c.sourcesStream)
 	msg.Flag("subject", "The subject to fetch a message from").Default(">").StringVar(&c.msgSubject)
 	msg.Flag("age-warn", "Warning threshold for message age as a duration").PlaceHolder("DURATION").DurationVar(&c.msgAgeWarn)
 	msg.Flag("age-critical", "Critical threshold for message age as a duration").PlaceHolder("DURATION").DurationVar(&c.msgAgeCrit)
 	msg.Flag("content", "Regular expression to check the content against").Default(".").RegexpVar(&c.msgRegexp)
+	msg.Flag("content-regex", "Regular expression to check the content against").Default(".").Hidden().RegexpVar(&c.msgRegexp)
 	msg.Flag("body-timestamp", "Use message body as a unix timestamp instead of message metadata").UnNegatableBoolVar(&c.msgBodyAsTs)
+	msg.Flag("timestamp-header", "Use the unix or RFC3339 timestamp in this header instead of message metadata").PlaceHolder("HEADER").StringVar(&c.msgTimestampHeader)
+	msg.Flag("body-timestamp-json-path", "Use the unix or RFC3339 timestamp at this path in a JSON body instead of message metadata").PlaceHolder("PATH").StringVar(&c.msgTimestampPath)
+	msg.Flag("body-value-json-path", "Checks the number at this path in a JSON body against --value-warn and --value-critical").PlaceHolder("PATH").StringVar(&c.msgValuePath)
+	msg.Flag("value-warn", "Warning threshold for the value extracted from the body, supports inversion").PlaceHolder("VALUE").Float64Var(&c.msgValueWarn)
+	msg.Flag("value-critical", "Critical threshold for the value extracted from the body, supports inversion").PlaceHolder("VALUE").Float64Var(&c.msgValueCrit)
 
-	meta := check.Command("meta", "Check JetStream cluster state").Alias("raft").Action(c.checkRaft)
+	meta := check.Command("meta", "Check JetStream cluster state").Alias("raft").Action(c.watchable(c.checkRaft))
 	meta.Tag("scope:user", "impact:ro")
-	meta.HelpLong(multipleChecks)
+	meta.HelpLong(multipleChecks + `When a state file is given the meta leader is recorded between runs, changes
+since the previous run and within the window can then be alerted on.`)
 	meta.Flag("expect", "Number of servers to expect").Required().PlaceHolder("SERVERS").IntVar(&c.raftExpect)
 	meta.Flag("lag-critical", "Critical threshold to allow for lag").PlaceHolder("OPS").Required().Uint64Var(&c.raftLagCritical)
 	meta.Flag("seen-critical", "Critical threshold for how long ago a peer should have been seen").Required().PlaceHolder("DURATION").DurationVar(&c.raftSeenCritical)
-
-	req := check.Command("request", "Checks a request-reply service").Alias("req").Action(c.checkRequest)
+	meta.Flag("state-file", "File to record the meta leader in between runs to detect leader changes").PlaceHolder("FILE").StringVar(&c.raftLeaderFile)
+	meta.Flag("leader-change-file", "File to record the meta leader in between runs to detect leader changes").Hidden().StringVar(&c.raftLeaderFile)
+	meta.Flag("leader-changed", "Status to raise when the leader changed since the previous run (warning, critical)").PlaceHolder("STATUS").EnumVar(&c.raftLeaderChanged, "warning", "critical")
+	meta.Flag("leader-change-window", "Time window to count leader changes in").Default("1h").PlaceHolder("DURATION").DurationVar(&c.raftLeaderWindow)
+	meta.Flag("leader-changes-warn", "Warning threshold for leader changes within the window").PlaceHolder("CHANGES").IntVar(&c.raftLeaderChangesWarn)
+	meta.Flag("leader-changes-crit", "Critical threshold for leader changes within the window").PlaceHolder("CHANGES").IntVar(&c.raftLeaderChangesCrit)
+	meta.Flag("api-stats", "Reports JetStream API statistics from the meta leader, requires system access").UnNegatableBoolVar(&c.raftAPIStats)
+	meta.Flag("api-inflight-warn", "Warning threshold for in-flight JetStream API requests on the meta leader").PlaceHolder("REQUESTS").IntVar(&c.raftAPIInflightWarn)
+	meta.Flag("api-inflight-crit", "Critical threshold for in-flight JetStream API requests on the meta leader").PlaceHolder("REQUESTS").IntVar(&c.raftAPIInflightCrit)
+	meta.Flag("api-pending-warn", "Warning threshold for JetStream API requests queued on the meta leader").PlaceHolder("REQUESTS").IntVar(&c.raftAPIPendingWarn)
+	meta.Flag("api-pending-crit", "Critical threshold for JetStream API requests queued on the meta leader").PlaceHolder("REQUESTS").IntVar(&c.raftAPIPendingCrit)
+
+	req := check.Command("request", "Checks a request-reply service").Alias("req").Action(c.watchable(c.checkRequest))
 	req.Tag("scope:user", "impact:rw")
 	req.HelpLong(multipleChecks + warnAndCritical)
 	req.Flag("subject", "The subject to send the request to").Required().StringVar(&c.msgSubject)
 	req.Flag("payload", "Payload to send in the request").StringVar(&c.msgPayload)
 	req.Flag("headers", "Headers to publish in the request").StringMapVar(&c.msgHeaders)
-	req.Flag("match-payload", "Regular expression the response should match").RegexpVar(&c.msgRegexp)
-	req.Flag("match-headers", "Headers to publish in the request").StringMapVar(&c.msgHeaders)
-	req.Flag("response-critical", "Critical threshold for response time").DurationVar(&c.msgCrit)
-	req.Flag("response-warn", "Warning threshold for response time").DurationVar(&c.msgWarn)
-
-	js := check.Command("jetstream", "Check JetStream account state").Alias("js").Action(c.checkJS)
+	req.Flag("response-regex", "Regular expression the response should match").PlaceHolder("PATTERN").RegexpVar(&c.msgRegexp)
+	req.Flag("match-payload", "Regular expression the response should match").Hidden().RegexpVar(&c.msgRegexp)
+	req.Flag("match-headers", "Headers the response should have").StringMapVar(&c.msgHeadersMatch)
+	req.Flag("rtt-warn", "Warning threshold for the request round trip time").PlaceHolder("DURATION").DurationVar(&c.msgWarn)
+	req.Flag("rtt-crit", "Critical threshold for the request round trip time").PlaceHolder("DURATION").DurationVar(&c.msgCrit)
+	req.Flag("response-critical", "Critical threshold for response time").Hidden().DurationVar(&c.msgCrit)
+	req.Flag("response-warn", "Warning threshold for response time").Hidden().DurationVar(&c.msgWarn)
+
+	svc := check.Command("service", "Checks the health of a NATS Micro service").Alias("svc").Action(c.watchable(c.checkService))
+	svc.Tag("scope:user", "impact:ro")
+	svc.HelpLong(multipleChecks + warnAndCritical + `Statistics are gathered from all instances of the service and combined per endpoint,
+error rates and average processing times are calculated since the instances started.`)
+	svc.Flag("name", "The service to check").Required().StringVar(&c.svcName)
+	svc.Flag("instances", "Critical when fewer instances respond").Default("1").PlaceHolder("INSTANCES").IntVar(&c.svcInstances)
+	svc.Flag("error-rate-warn", "Warning threshold for the error rate of any endpoint, in percent").PlaceHolder("PCT").Float64Var(&c.svcErrorRateWarn)
+	svc.Flag("error-rate-crit", "Critical threshold for the error rate of any endpoint, in percent").PlaceHolder("PCT").Float64Var(&c.svcErrorRateCrit)
+	svc.Flag("avg-time-warn", "Warning threshold for the average processing time of any endpoint").PlaceHolder("DURATION").DurationVar(&c.svcAvgTimeWarn)
+	svc.Flag("avg-time-crit", "Critical threshold for the average processing time of any endpoint").PlaceHolder("DURATION").DurationVar(&c.svcAvgTimeCrit)
+
+	js := check.Command("jetstream", "Check JetStream account state").Alias("js").Action(c.watchable(c.checkJS))
 	js.Tag("scope:user", "impact:ro")
 	js.HelpLong(multipleChecks + warnAndCritical + inversion)
 	js.Flag("mem-warn", "Warning threshold for memory storage, in percent of limit").Default("75").IntVar(&c.jsMemWarn)
 	js.Flag("mem-critical", "Critical threshold for memory storage, in percent of limit").Default("90").IntVar(&c.jsMemCritical)
+	js.Flag("js-mem-storage-warn", "Warning threshold for memory storage, in percent of limit, same as --mem-warn").PlaceHolder("PCT").IntVar(&c.jsMemWarn)
+	js.Flag("js-mem-storage-crit", "Critical threshold for memory storage, in percent of limit, same as --mem-critical").PlaceHolder("PCT").IntVar(&c.jsMemCritical)
 	js.Flag("store-warn", "Warning threshold for disk storage, in percent of limit").Default("75").IntVar(&c.jsStoreWarn)
 	js.Flag("store-critical", "Critical threshold for disk storage, in percent of limit").Default("90").IntVar(&c.jsStoreCritical)
+	js.Flag("account", "Checks the JetStream account, disable when using system account credentials to only check servers").Default("true").BoolVar(&c.jsAccount)
+	js.Flag("server-mem-warn", "Warning threshold for memory storage on any server, in percent of the server limit, requires --no-account and system account credentials").PlaceHolder("PCT").IntVar(&c.jsSrvMemWarn)
+	js.Flag("server-mem-critical", "Critical threshold for memory storage on any server, in percent of the server limit, requires --no-account and system account credentials").PlaceHolder("PCT").IntVar(&c.jsSrvMemCrit)
+	js.Flag("server-store-warn", "Warning threshold for disk storage on any server, in percent of the server limit, requires --no-account and system account credentials").PlaceHolder("PCT").IntVar(&c.jsSrvStoreWarn)
+	js.Flag("server-store-critical", "Critical threshold for disk storage on any server, in percent of the server limit, requires --no-account and system account credentials").PlaceHolder("PCT").IntVar(&c.jsSrvStoreCrit)
 	js.Flag("streams-warn", "Warning threshold for number of streams used, in percent of limit").Default("-1").IntVar(&c.jsStreamsWarn)
 	js.Flag("streams-critical", "Critical threshold for number of streams used, in percent of limit").Default("-1").IntVar(&c.jsStreamsCritical)
+	js.Flag("stream-count-warn", "Warning threshold for the number of streams, regardless of account limits").PlaceHolder("STREAMS").IntVar(&c.jsStreamCountWarn)
+	js.Flag("stream-count-crit", "Critical threshold for the number of streams, regardless of account limits").PlaceHolder("STREAMS").IntVar(&c.jsStreamCountCrit)
 	js.Flag("consumers-warn", "Warning threshold for number of consumers used, in percent of limit").Default("-1").IntVar(&c.jsConsumersWarn)
 	js.Flag("consumers-critical", "Critical threshold for number of consumers used, in percent of limit").Default("-1").IntVar(&c.jsConsumersCritical)
 	js.Flag("replicas", "Checks if all streams have healthy replicas").Default("true").BoolVar(&c.jsReplicas)
 	js.Flag("replica-seen-critical", "Critical threshold for when a stream replica should have been seen, as a duration").Default("5s").DurationVar(&c.jsReplicaSeenCritical)
 	js.Flag("replica-lag-critical", "Critical threshold for how many operations behind a peer can be").Default("200").Uint64Var(&c.jsReplicaLagCritical)
+	js.Flag("all-servers", "Checks JetStream storage on every server discovered using the system account, the account is not checked").UnNegatableBoolVar(&c.allServers)
 
-	serv := check.Command("server", "Checks a NATS Server health").Action(c.checkSrv)
+	serv := check.Command("server", "Checks a NATS Server health").Action(c.watchable(c.checkSrv))
 	serv.Tag("scope:system", "impact:ro")
 	serv.HelpLong(multipleChecks + warnAndCritical + inversion)
-	serv.Flag("name", "Server name to require in the result").Required().StringVar(&c.srvName)
+	serv.Flag("name", "Server name to require in the result").StringVar(&c.srvName)
+	serv.Flag("all-servers", "Checks every server discovered using the system account").UnNegatableBoolVar(&c.allServers)
 	serv.Flag("cpu-warn", "Warning threshold for CPU usage, in percent").IntVar(&c.srvCPUWarn)
 	serv.Flag("cpu-critical", "Critical threshold for CPU usage, in percent").IntVar(&c.srvCPUCrit)
 	serv.Flag("mem-warn", "Warning threshold for Memory usage, in bytes").IntVar(&c.srvMemWarn)
@@ -245,6 +445,7 @@ When set these settings will be used, but can be overridden using --waiting-crit
 	serv.Flag("conn-critical", "Critical threshold for connections, supports inversion").IntVar(&c.srvConnCrit)
 	serv.Flag("subs-warn", "Warning threshold for number of active subscriptions, supports inversion").IntVar(&c.srvSubsWarn)
 	serv.Flag("subs-critical", "Critical threshold for number of active subscriptions, supports inversion").IntVar(&c.srvSubCrit)
+	serv.Flag("subs-crit", "Critical threshold for number of active subscriptions, supports inversion").Hidden().IntVar(&c.srvSubCrit)
 	serv.Flag("uptime-warn", "Warning threshold for server uptime as duration").DurationVar(&c.srvUptimeWarn)
 	serv.Flag("uptime-critical", "Critical threshold for server uptime as duration").DurationVar(&c.srvUptimeCrit)
 	serv.Flag("auth-required", "Checks that authentication is enabled").UnNegatableBoolVar(&c.srvAuthRequired)
@@ -252,25 +453,166 @@ When set these settings will be used, but can be overridden using --waiting-crit
 	serv.Flag("js-required", "Checks that JetStream is enabled").UnNegatableBoolVar(&c.srvJSRequired)
 	serv.Flag("tls-cert-warn", "Warning threshold for TLS certificate expiry like 1d3h5m").DurationVar(&c.srvtlsExpiredWarn)
 	serv.Flag("tls-cert-crit", "Critical threshold for TLS certificate expiry like 1d3h5m").DurationVar(&c.srvtlsExpiredCrit)
-
-	kv := check.Command("kv", "Checks a NATS KV Bucket").Action(c.checkKV)
+	serv.Flag("conn-pool-warn", "Warning threshold for connections, in percent of the maximum connections").PlaceHolder("PCT").IntVar(&c.srvConnPoolWarn)
+	serv.Flag("conn-pool-crit", "Critical threshold for connections, in percent of the maximum connections").PlaceHolder("PCT").IntVar(&c.srvConnPoolCrit)
+	serv.Flag("js-api-queue-warn", "Warning threshold for queued JetStream API requests").PlaceHolder("REQUESTS").IntVar(&c.srvJSAPIQueueWarn)
+	serv.Flag("js-api-queue-crit", "Critical threshold for queued JetStream API requests").PlaceHolder("REQUESTS").IntVar(&c.srvJSAPIQueueCrit)
+	serv.Flag("routes-expect", "Critical when the server has routes to fewer other servers, pooled connections to a server count once").PlaceHolder("ROUTES").IntVar(&c.srvRoutesExpect)
+	serv.Flag("require-websocket", "Critical if the WebSocket listener is not enabled").UnNegatableBoolVar(&c.srvRequireWS)
+	serv.Flag("expected-account-count", "Critical when the server does not have exactly this many accounts").PlaceHolder("ACCOUNTS").IntVar(&c.srvExpectAccounts)
+	serv.Flag("max-accounts", "Warning when the server has more than this many accounts").PlaceHolder("ACCOUNTS").IntVar(&c.srvMaxAccounts)
+
+	slow := check.Command("slow-consumers", "Checks a NATS Server for slow consumers").Alias("slow").Action(c.watchable(c.checkSlowConsumers))
+	slow.Tag("scope:system", "impact:ro")
+	slow.HelpLong(warnAndCritical + `The server counts slow consumers since it started, when a state file is
+given the thresholds apply to the slow consumers detected since the previous
+check instead.
+
+When thresholds are exceeded recently closed slow consumer connections are
+listed.`)
+	slow.Flag("name", "Server name to check").Required().StringVar(&c.srvName)
+	slow.Flag("warn", "Warning threshold for the number of slow consumers").PlaceHolder("COUNT").IntVar(&c.slowWarn)
+	slow.Flag("critical", "Critical threshold for the number of slow consumers").PlaceHolder("COUNT").IntVar(&c.slowCrit)
+	slow.Flag("state-file", "Stores the slow consumer count between runs to alert on new slow consumers").PlaceHolder("FILE").StringVar(&c.slowStateFile)
+
+	acctConns := check.Command("account-connections", "Checks the number of client connections in an account").Alias("acct-conns").Action(c.watchable(c.checkAccountConnections))
+	acctConns.Tag("scope:system", "impact:ro")
+	acctConns.HelpLong(warnAndCritical + inversion + `Connections are counted across all servers unless --name is given.`)
+	acctConns.Flag("account", "The account to check").Required().StringVar(&c.acctName)
+	acctConns.Flag("name", "Only counts connections on this server").StringVar(&c.srvName)
+	acctConns.Flag("warn", "Warning threshold for the number of connections, supports inversion").PlaceHolder("CONNECTIONS").IntVar(&c.acctConnWarn)
+	acctConns.Flag("crit", "Critical threshold for the number of connections, supports inversion").PlaceHolder("CONNECTIONS").IntVar(&c.acctConnCrit)
+	acctConns.Flag("min", "Critical when fewer connections are found").PlaceHolder("CONNECTIONS").IntVar(&c.acctConnMin)
+
+	leafs := check.Command("leafnodes", "Checks the leafnodes connected to a server").Alias("leafz").Alias("leafs").Action(c.watchable(c.checkLeafnodes))
+	leafs.Tag("scope:system", "impact:ro")
+	leafs.Flag("name", "Server name to check").Required().StringVar(&c.srvName)
+	leafs.Flag("expect", "Critical when fewer leafnodes are connected").Required().PlaceHolder("LEAFNODES").IntVar(&c.leafExpect)
+	leafs.Flag("state-file", "Stores leafnode message counts between runs to calculate message rates").PlaceHolder("FILE").StringVar(&c.leafStateFile)
+
+	gw := check.Command("gateway", "Checks the gateways connected to a server").Alias("gateways").Alias("gw").Action(c.watchable(c.checkGateways))
+	gw.Tag("scope:system", "impact:ro")
+	gw.HelpLong(multipleChecks + "When no gateways are expected all configured gateways must be connected")
+	gw.Flag("name", "Server name to check").Required().StringVar(&c.srvName)
+	gw.Flag("expect", "Gateway that must be connected (pass multiple times)").PlaceHolder("GATEWAY").StringsVar(&c.gwExpect)
+	gw.Flag("min-uptime", "Warn when a gateway connection is younger than this, indicating reconnects").PlaceHolder("DURATION").DurationVar(&c.gwMinUptime)
+
+	kv := check.Command("kv", "Checks a NATS KV Bucket").Action(c.watchable(c.checkKV))
 	kv.Tag("scope:user", "impact:ro")
 	kv.HelpLong(multipleChecks + warnAndCritical + inversion)
 	kv.Flag("bucket", "Checks a specific bucket").Required().StringVar(&c.kvBucket)
 	kv.Flag("values-critical", "Critical threshold for number of values in the bucket").Default("-1").Int64Var(&c.kvValuesCrit)
 	kv.Flag("values-warn", "Warning threshold for number of values in the bucket").Default("-1").Int64Var(&c.kvValuesWarn)
 	kv.Flag("key", "Requires a key to have any non-delete value set").StringVar(&c.kvKey)
-
-	cred := check.Command("credential", "Checks the validity of a NATS credential file").Action(c.checkCredentialAction)
+	kv.Flag("key-age-warn", "Warning threshold for the age of the newest value of --key").PlaceHolder("DURATION").DurationVar(&c.kvKeyAgeWarn)
+	kv.Flag("key-age-critical", "Critical threshold for the age of the newest value of --key").PlaceHolder("DURATION").DurationVar(&c.kvKeyAgeCrit)
+	kv.Flag("peer-expect", "Number of cluster replicas to expect, defaults to the bucket replicas").PlaceHolder("SERVERS").IntVar(&c.raftExpect)
+	kv.Flag("peer-lag-critical", "Critical threshold to allow for cluster peer lag").PlaceHolder("OPS").Uint64Var(&c.raftLagCritical)
+	kv.Flag("peer-seen-critical", "Critical threshold for how long ago a cluster peer should have been seen").PlaceHolder("DURATION").DurationVar(&c.raftSeenCritical)
+
+	obj := check.Command("object", "Checks a NATS Object Store Bucket").Alias("obj").Action(c.watchable(c.checkObject))
+	obj.Tag("scope:user", "impact:ro")
+	obj.HelpLong(multipleChecks + warnAndCritical + inversion)
+	obj.Flag("bucket", "Checks a specific bucket").Required().StringVar(&c.objBucket)
+	obj.Flag("size-warn", "Warning threshold for the size of the bucket").PlaceHolder("SIZE").StringVar(&c.objSizeWarn)
+	obj.Flag("size-critical", "Critical threshold for the size of the bucket").PlaceHolder("SIZE").StringVar(&c.objSizeCrit)
+	obj.Flag("objects-warn", "Warning threshold for the number of objects, supports inversion").PlaceHolder("OBJECTS").IntVar(&c.objCountWarn)
+	obj.Flag("objects-critical", "Critical threshold for the number of objects, supports inversion").PlaceHolder("OBJECTS").IntVar(&c.objCountCrit)
+	obj.Flag("age-warn", "Warning threshold for the age of the most recently modified object").PlaceHolder("DURATION").DurationVar(&c.objAgeWarn)
+	obj.Flag("age-critical", "Critical threshold for the age of the most recently modified object").PlaceHolder("DURATION").DurationVar(&c.objAgeCrit)
+	obj.Flag("peer-expect", "Number of cluster replicas to expect, defaults to the bucket replicas").PlaceHolder("SERVERS").IntVar(&c.raftExpect)
+	obj.Flag("peer-lag-critical", "Critical threshold to allow for cluster peer lag").PlaceHolder("OPS").Uint64Var(&c.raftLagCritical)
+	obj.Flag("peer-seen-critical", "Critical threshold for how long ago a cluster peer should have been seen").PlaceHolder("DURATION").DurationVar(&c.raftSeenCritical)
+
+	cred := check.Command("credential", "Checks the validity of a NATS credential file").Action(c.watchable(c.checkCredentialAction))
 	cred.Tag("scope:system", "impact:ro")
 	cred.HelpLong(multipleChecks + warnAndCritical + inversion)
-	cred.Flag("credential", "The file holding the NATS credential").Required().StringVar(&c.credential)
+	cred.Flag("credential", "The file holding the NATS credential").StringVar(&c.credential)
+	cred.Flag("credential-dir", "Checks every .creds file in this directory and its subdirectories").PlaceHolder("DIR").ExistingDirVar(&c.credentialDir)
 	cred.Flag("validity-warn", "Warning threshold for time before expiry").DurationVar(&c.credentialValidityWarn)
 	cred.Flag("validity-critical", "Critical threshold for time before expiry").DurationVar(&c.credentialValidityCrit)
 	cred.Flag("require-expiry", "Requires the credential to have expiry set").Default("true").BoolVar(&c.credentialRequiresExpire)
 
-	exporter := check.Command("exporter", "Prometheus exporter for server checks").Hidden().Action(c.exporterAction)
+	access := check.Command("access", "Checks the permissions of the connection by publishing and subscribing").Action(c.watchable(c.checkAccess))
+	access.Tag("scope:user", "impact:rw")
+	access.HelpLong(`Subscribes to and publishes a message on the subject using the connection
+credentials, when both are allowed the published message must be received.
+
+Use --expect-deny to confirm the credentials are not allowed to access the subject.`)
+	access.Flag("subject", "The subject to probe").Required().StringVar(&c.accessSubject)
+	access.Flag("pub", "Checks publishing to the subject").UnNegatableBoolVar(&c.accessPub)
+	access.Flag("sub", "Checks subscribing to the subject").UnNegatableBoolVar(&c.accessSub)
+	access.Flag("expect-deny", "Requires the server to deny access to the subject").UnNegatableBoolVar(&c.accessExpectDeny)
+
+	ws := check.Command("websocket", "Checks a WebSocket listener by performing the upgrade handshake").Alias("ws").Action(c.watchable(c.checkWebsocket))
+	ws.Tag("scope:system", "impact:ro")
+	ws.HelpLong(`Connects to the address and performs the WebSocket upgrade, wss:// addresses are
+verified against the CA from the context or the system roots.`)
+	ws.Flag("address", "The WebSocket listener address like ws://host:8080 or wss://host:443").Required().PlaceHolder("URL").StringVar(&c.listenerAddress)
+	ws.Flag("handshake-warn", "Warning threshold for the time taken to complete the handshake").PlaceHolder("DURATION").DurationVar(&c.listenerHandshakeWarn)
+	ws.Flag("handshake-critical", "Critical threshold for the time taken to complete the handshake").PlaceHolder("DURATION").DurationVar(&c.listenerHandshakeCrit)
+
+	mqtt := check.Command("mqtt", "Checks a MQTT listener by performing a MQTT CONNECT").Action(c.watchable(c.checkMQTT))
+	mqtt.Tag("scope:system", "impact:ro")
+	mqtt.HelpLong(`Connects to the address and sends a MQTT 3.1.1 CONNECT using the user and
+password from the context, mqtts://, tls:// and ssl:// addresses are verified
+against the CA from the context or the system roots.
+
+A CONNACK refusing the connection is a warning as the listener is up.`)
+	mqtt.Flag("address", "The MQTT listener address like mqtt://host:1883 or mqtts://host:8883").Required().PlaceHolder("URL").StringVar(&c.listenerAddress)
+	mqtt.Flag("handshake-warn", "Warning threshold for the time taken to complete the handshake").PlaceHolder("DURATION").DurationVar(&c.listenerHandshakeWarn)
+	mqtt.Flag("handshake-critical", "Critical threshold for the time taken to complete the handshake").PlaceHolder("DURATION").DurationVar(&c.listenerHandshakeCrit)
+
+	cert := check.Command("certificate", "Checks the expiry of TLS certificates presented by servers").Alias("cert").Action(c.watchable(c.checkCertificate))
+	cert.Tag("scope:system", "impact:ro")
+	cert.HelpLong(multipleChecks + `Addresses using nats:// or tls:// are treated as NATS client, leafnode or
+gateway ports while https:// and wss:// are used for monitoring and WebSocket
+ports. When no addresses are given the servers from the context are checked.`)
+	cert.Flag("address", "Address to check (pass multiple times)").PlaceHolder("URL").StringsVar(&c.certAddresses)
+	cert.Flag("validity-warn", "Warning threshold for time before expiry").Default("720h").DurationVar(&c.certValidityWarn)
+	cert.Flag("validity-critical", "Critical threshold for time before expiry").Default("168h").DurationVar(&c.certValidityCrit)
+
+	suite := check.Command("suite", "Performs multiple checks defined in a configuration file").Action(c.watchable(c.checkSuite))
+	suite.Tag("scope:system", "impact:ro")
+	suite.HelpLong(`The configuration file uses the same format as the Prometheus exporter:
+
+    context: production
+    checks:
+      - name: ORDERS
+        kind: stream
+        properties:
+          stream_name: ORDERS
+          min_sources: 1
+
+Supported kinds are connection, stream, consumer, message, meta, jetstream,
+server, kv, credential and request. Properties match the JSON options of the
+checks in the jsm.go monitor package.
+
+All checks are run concurrently and combined into a single result unless
+--individual is set.`)
+	suite.Flag("config", "Check suite configuration").Required().PlaceHolder("FILE").ExistingFileVar(&c.suiteConfigFile)
+	suite.Flag("individual", "Renders a result for every check rather than a combined result").UnNegatableBoolVar(&c.suiteIndividual)
+
+	exporter := check.Command("exporter", "Prometheus exporter for server checks").Action(c.exporterAction)
 	exporter.Tag("scope:system", "impact:rw")
+	exporter.HelpLong(`Serves the results of checks on /metrics, every scrape performs all checks.
+
+The configuration file is shared with the suite check:
+
+    context: production
+    checks:
+      - name: ORDERS
+        kind: stream
+        reuse_connection: true
+        properties:
+          stream_name: ORDERS
+
+Supported kinds are connection, stream, consumer, message, meta, jetstream,
+server, kv, credential and request. Properties match the JSON options of the
+checks in the jsm.go monitor package.
+
+Each check may set its own context, when reuse_connection is set the
+connection is kept open between scrapes.`)
 	exporter.Flag("config", "Exporter configuration").Required().ExistingFileVar(&c.exporterConfigFile)
 	exporter.Flag("port", "Port to listen on").Default("8080").IntVar(&c.exporterPort)
 	exporter.Flag("https-key", "Key for HTTPS").ExistingFileVar(&c.exporterKey)
@@ -281,24 +623,251 @@ var (
 	checkRenderFormatText = "nagios"
 	checkRenderFormat     = monitor.NagiosFormat
 	checkRenderOutFile    = ""
+	checkCriticalOnly     = false
+	checkWarningAsOK      = false
 )
 
 func (c *SrvCheckCmd) parseRenderFormat(_ *fisk.ParseContext) error {
-	switch checkRenderFormatText {
+	setCheckRenderFormat(checkRenderFormatText)
+
+	return nil
+}
+
+// setCheckRenderFormat selects the format used to render check results
+func setCheckRenderFormat(format string) {
+	checkRenderFormatText = format
+
+	switch format {
 	case "prometheus":
 		checkRenderFormat = monitor.PrometheusFormat
 	case "text":
 		checkRenderFormat = monitor.TextFormat
 	case "json":
 		checkRenderFormat = monitor.JSONFormat
+	default:
+		checkRenderFormat = monitor.NagiosFormat
 	}
+}
 
-	return nil
+// watchable runs the action once or, when watching, repeatedly until interrupted
+func (c *SrvCheckCmd) watchable(action fisk.Action) fisk.Action {
+	return func(pc *fisk.ParseContext) error {
+		if c.watchInterval <= 0 {
+			return action(pc)
+		}
+
+		// establishes the connection shared by checks and used to publish results
+		_, err := c.checkConn()
+		if err != nil {
+			return err
+		}
+
+		ticker := time.NewTicker(c.watchInterval)
+		defer ticker.Stop()
+
+		for {
+			err = action(pc)
+			if err != nil {
+				return err
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// finish renders the result and exits, when watching the result is rendered and published instead
+func (c *SrvCheckCmd) finish(check *monitor.Result) {
+	err := recover()
+	if err != nil {
+		check.Criticalf("check caused a panic: %v", err)
+		if check.Trace {
+			debug.PrintStack()
+		}
+	}
+
+	c.recordConnectFailure(check)
+
+	// rendering also prepares the status
+	out := renderCheck(check)
+	c.publishStatus(check)
+
+	if c.watchInterval <= 0 {
+		exitCheck(check, out)
+		return
+	}
+
+	fmt.Println(out)
+
+	subj := c.watchSubject
+	if subj == "" {
+		subj = fmt.Sprintf("$NATS.CHECK.%s", check.Check)
+	}
+
+	body, err := json.Marshal(check)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not encode result: %v\n", err)
+		return
+	}
+
+	nc, err := c.checkConn()
+	if err == nil {
+		err = nc.Publish(subj, body)
+	}
+	if err == nil {
+		err = nc.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not publish result to %s: %v\n", subj, err)
+	}
+}
+
+// publishStatus publishes the status of a prepared result to --status-subject
+func (c *SrvCheckCmd) publishStatus(check *monitor.Result) {
+	if c.statusSubject == "" {
+		return
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"check":     check.Check,
+		"name":      check.Name,
+		"status":    check.Status,
+		"exit_code": checkPolicyCode(check),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not encode status: %v\n", err)
+		return
+	}
+
+	// do not retry connections that already failed during the check
+	if c.nc == nil && c.connectErr != nil {
+		fmt.Fprintf(os.Stderr, "could not publish status to %s: %v\n", c.statusSubject, c.connectErr)
+		return
+	}
+
+	nc, err := c.checkConn()
+	if err == nil {
+		err = nc.Publish(c.statusSubject, body)
+	}
+	if err == nil {
+		err = nc.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not publish status to %s: %v\n", c.statusSubject, err)
+	}
+}
+
+// checkConn returns the supplied connection or connects to NATS, this is done outside of any check timeout
+func (c *SrvCheckCmd) checkConn() (*nats.Conn, error) {
+	if c.nc != nil {
+		return c.nc, nil
+	}
+
+	if opts().Conn != nil {
+		return opts().Conn, nil
+	}
+
+	err := c.connectWithRetries(func() error {
+		var err error
+		c.nc, err = newNatsConn("", c.checkNatsOpts()...)
+		return err
+	})
+
+	return c.nc, err
+}
+
+// checkMgr returns the supplied JetStream manager or creates one, this is done outside of any check timeout
+func (c *SrvCheckCmd) checkMgr() (*jsm.Manager, error) {
+	if opts().Mgr != nil {
+		return opts().Mgr, nil
+	}
+
+	// connects using the retry policy, the helper then reuses the connection
+	_, err := c.checkConn()
+	if err != nil {
+		return nil, err
+	}
+
+	_, mgr, err := prepareHelper("", c.checkNatsOpts()...)
+
+	return mgr, err
+}
+
+// requestTimeout is the timeout to pass to checks that perform their own requests
+func (c *SrvCheckCmd) requestTimeout() time.Duration {
+	if c.checkTimeout > 0 {
+		return c.checkTimeout
+	}
+
+	return opts().Timeout
+}
+
+// filterPerfData removes perf data items matching --suppress-perf-item from the check
+func (c *SrvCheckCmd) filterPerfData(check *monitor.Result) {
+	if len(c.perfSuppress) == 0 {
+		return
+	}
+
+	check.PerfData = slices.DeleteFunc(check.PerfData, func(pd *monitor.PerfDataItem) bool {
+		return slices.ContainsFunc(c.perfSuppress, func(re *regexp.Regexp) bool {
+			return re.MatchString(pd.Name)
+		})
+	})
+}
+
+// runCheck calls cb to perform the check logic and filters the resulting perf data
+func (c *SrvCheckCmd) runCheck(check *monitor.Result, cb func(ctx context.Context, check *monitor.Result) error) error {
+	err := c.runCheckWithTimeout(check, cb)
+	c.filterPerfData(check)
+
+	return err
+}
+
+// runCheckWithTimeout calls cb, when --check-timeout is set the check fails if cb does not complete in time and the context passed to cb is canceled
+func (c *SrvCheckCmd) runCheckWithTimeout(check *monitor.Result, cb func(ctx context.Context, check *monitor.Result) error) error {
+	if c.checkTimeout <= 0 {
+		return cb(ctx, check)
+	}
+
+	to, cancel := context.WithTimeout(ctx, c.checkTimeout)
+	defer cancel()
+
+	// the check might continue in the background after a timeout, so it works on a copy to avoid racing with rendering
+	res := *check
+	res.OKs = slices.Clone(check.OKs)
+	res.Warnings = slices.Clone(check.Warnings)
+	res.Criticals = slices.Clone(check.Criticals)
+	res.PerfData = slices.Clone(check.PerfData)
+
+	errs := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errs <- fmt.Errorf("check caused a panic: %v", r)
+			}
+		}()
+
+		errs <- cb(to, &res)
+	}()
+
+	select {
+	case err := <-errs:
+		*check = res
+		return err
+
+	case <-to.Done():
+		return fmt.Errorf("check did not complete within %v", c.checkTimeout)
+	}
 }
 
 func (c *SrvCheckCmd) checkRequest(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: c.msgSubject, Check: "request", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
 
 	checkOpts := monitor.CheckRequestOptions{
 		Subject:              c.msgSubject,
@@ -313,22 +882,40 @@ func (c *SrvCheckCmd) checkRequest(_ *fisk.ParseContext) error {
 		checkOpts.ResponseMatch = c.msgRegexp.String()
 	}
 
-	var err error
-	nc := opts().Conn
-
-	if nc == nil {
-		err = monitor.CheckRequest(opts().Config.ServerURL(), natsOpts(), check, opts().Timeout, checkOpts)
-	} else {
-		err = monitor.CheckRequestWithConnection(nc, check, opts().Timeout, checkOpts)
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
 	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		err := monitor.CheckRequestWithConnection(nc, check, c.requestTimeout(), checkOpts)
+		if err != nil {
+			return err
+		}
+
+		// the monitor package reports the round trip as time, rtt matches the connection check
+		for _, pd := range check.PerfData {
+			if pd.Name == "time" {
+				check.Pd(&monitor.PerfDataItem{Name: "rtt", Value: pd.Value, Warn: pd.Warn, Crit: pd.Crit, Unit: pd.Unit, Help: "The request round trip time"})
+				break
+			}
+		}
+
+		return nil
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
 }
 
 func (c *SrvCheckCmd) checkConsumer(_ *fisk.ParseContext) error {
-	check := &monitor.Result{Name: fmt.Sprintf("%s_%s", c.sourcesStream, c.consumerName), Check: "consumer", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	name := fmt.Sprintf("%s_%s", c.sourcesStream, c.consumerName)
+	if c.checkAllConsumers {
+		name = c.sourcesStream
+	}
+
+	check := &monitor.Result{Name: name, Check: "consumer", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
 
 	checkOpts := monitor.CheckConsumerHealthOptions{
 		StreamName:   c.sourcesStream,
@@ -354,20 +941,34 @@ func (c *SrvCheckCmd) checkConsumer(_ *fisk.ParseContext) error {
 	if c.consumerRedeliveryCriticalIsSet {
 		checkOpts.RedeliveryCritical = c.consumerRedeliveryCritical
 	}
+	checkOpts.HealthChecks = append(checkOpts.HealthChecks, c.checkConsumerWarnings)
 
 	logger := api.NewDiscardLogger()
 	if opts().Trace {
 		logger = api.NewDefaultLogger(api.TraceLevel)
 	}
 
-	var err error
-	mgr := opts().Mgr
+	mgr, err := c.checkMgr()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
 
-	if mgr == nil {
-		err = monitor.CheckConsumerHealth(opts().Config.ServerURL(), natsOpts(), jsmOpts(), check, checkOpts, logger)
-	} else {
-		err = monitor.CheckConsumerHealthWithConnection(mgr, check, checkOpts, logger)
+	switch {
+	case c.checkAllConsumers && c.consumerName != "":
+		check.Critical("--consumer and --all-consumers are mutually exclusive")
+		return nil
+	case !c.checkAllConsumers && c.consumerName == "":
+		check.Critical("consumer name is required")
+		return nil
 	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		if c.checkAllConsumers {
+			return c.checkEveryConsumer(mgr, check, checkOpts, logger)
+		}
+
+		return monitor.CheckConsumerHealthWithConnection(mgr, check, checkOpts, logger)
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
@@ -375,7 +976,7 @@ func (c *SrvCheckCmd) checkConsumer(_ *fisk.ParseContext) error {
 
 func (c *SrvCheckCmd) checkKV(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: c.kvBucket, Check: "kv", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
 
 	checkOpts := monitor.CheckKVBucketAndKeyOptions{
 		Bucket:         c.kvBucket,
@@ -384,14 +985,29 @@ func (c *SrvCheckCmd) checkKV(_ *fisk.ParseContext) error {
 		ValuesWarning:  c.kvValuesWarn,
 	}
 
-	var err error
-	nc := opts().Conn
+	if (c.kvKeyAgeWarn > 0 || c.kvKeyAgeCrit > 0) && c.kvKey == "" {
+		check.Critical("--key is required when checking the key age")
+		return nil
+	}
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
 
-	if nc == nil {
-		err = monitor.CheckKVBucketAndKey(opts().Config.ServerURL(), natsOpts(), check, checkOpts)
-	} else {
-		err = monitor.CheckKVBucketAndKeyWithConnection(nc, check, checkOpts)
+	mgr, err := c.checkMgr()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
 	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		err := monitor.CheckKVBucketAndKeyWithConnection(nc, check, checkOpts)
+		if err != nil {
+			return err
+		}
+
+		return c.checkKVHealth(mgr, check)
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
@@ -399,7 +1015,18 @@ func (c *SrvCheckCmd) checkKV(_ *fisk.ParseContext) error {
 
 func (c *SrvCheckCmd) checkSrv(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: c.srvName, Check: "server", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
+
+	if c.allServers {
+		check.Name = "All Servers"
+		if c.srvName != "" {
+			check.Critical("--name and --all-servers are mutually exclusive")
+			return nil
+		}
+	} else if c.srvName == "" {
+		check.Critical("--name or --all-servers is required")
+		return nil
+	}
 
 	checkOpts := monitor.CheckServerOptions{
 		Name:                   c.srvName,
@@ -420,22 +1047,58 @@ func (c *SrvCheckCmd) checkSrv(_ *fisk.ParseContext) error {
 		TLSExpireCritical:      c.srvtlsExpiredCrit.String(),
 	}
 
-	var err error
-	nc := opts().Conn
-
-	if nc == nil {
-		err = monitor.CheckServer(opts().Config.ServerURL(), natsOpts(), check, opts().Timeout, checkOpts)
-	} else {
-		err = monitor.CheckServerWithConnection(nc, check, opts().Timeout, checkOpts)
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
 	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		if !c.allServers {
+			return c.checkServerNamed(nc, check, checkOpts)
+		}
+
+		return c.checkEachServer(nc, check, func(vz *server.Varz, res *monitor.Result) error {
+			sopts := checkOpts
+			sopts.Name = vz.Name
+
+			return c.checkServerNamed(nc, res, sopts)
+		})
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
 }
 
+// checkServerNamed checks the server named in checkOpts
+func (c *SrvCheckCmd) checkServerNamed(nc *nats.Conn, check *monitor.Result, checkOpts monitor.CheckServerOptions) error {
+	// captures the variables used by the monitor package for additional checks
+	var vz *server.Varz
+	checkOpts.Resolver = func(nc *nats.Conn, name string, _ time.Duration) (*server.Varz, error) {
+		var err error
+		vz, err = c.fetchVarz(nc, name)
+		return vz, err
+	}
+
+	err := monitor.CheckServerWithConnection(nc, check, c.requestTimeout(), checkOpts)
+	if err != nil {
+		return err
+	}
+
+	if vz != nil {
+		c.checkVarz(check, vz)
+	}
+
+	err = c.checkRoutes(nc, check, checkOpts.Name)
+	if err != nil {
+		return err
+	}
+
+	return c.checkServerAccounts(nc, check, checkOpts.Name)
+}
+
 func (c *SrvCheckCmd) checkJS(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: "JetStream", Check: "jetstream", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
 
 	checkOpts := monitor.CheckJetStreamAccountOptions{
 		MemoryWarning:       c.jsMemWarn,
@@ -451,22 +1114,73 @@ func (c *SrvCheckCmd) checkJS(_ *fisk.ParseContext) error {
 		ReplicaLagCritical:  c.jsReplicaLagCritical,
 	}
 
-	var err error
-	mgr := opts().Mgr
+	if c.allServers {
+		check.Name = "All Servers"
+
+		nc, err := c.checkConn()
+		if check.CriticalIfErrf(err, "connection failed: %v", err) {
+			return nil
+		}
+
+		err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+			return c.checkJetStreamServers(nc, check)
+		})
+		check.CriticalIfErrf(err, "Check failed: %v", err)
 
-	if mgr == nil {
-		err = monitor.CheckJetStreamAccount(opts().Config.ServerURL(), natsOpts(), jsmOpts(), check, checkOpts)
-	} else {
-		err = monitor.CheckJetStreamAccountWithConnection(mgr, check, checkOpts)
+		return nil
 	}
+
+	if !c.jsAccount {
+		check.Name = "JetStream Servers"
+		if c.jsSrvMemWarn == 0 && c.jsSrvMemCrit == 0 && c.jsSrvStoreWarn == 0 && c.jsSrvStoreCrit == 0 {
+			check.Critical("server thresholds are required when not checking the account")
+			return nil
+		}
+
+		nc, err := c.checkConn()
+		if check.CriticalIfErrf(err, "connection failed: %v", err) {
+			return nil
+		}
+
+		err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+			return c.checkServerStorage(nc, check)
+		})
+		check.CriticalIfErrf(err, "Check failed: %v", err)
+
+		return nil
+	}
+
+	// server storage is only available to the system account which has no JetStream account to check
+	if c.jsSrvMemWarn != 0 || c.jsSrvMemCrit != 0 || c.jsSrvStoreWarn != 0 || c.jsSrvStoreCrit != 0 {
+		check.Critical("server thresholds require --no-account and system account credentials")
+		return nil
+	}
+
+	mgr, err := c.checkMgr()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		return c.checkJetStreamAccount(mgr, check, checkOpts)
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
 }
 
+func (c *SrvCheckCmd) checkJetStreamAccount(mgr *jsm.Manager, check *monitor.Result, checkOpts monitor.CheckJetStreamAccountOptions) error {
+	err := monitor.CheckJetStreamAccountWithConnection(mgr, check, checkOpts)
+	if err != nil {
+		return err
+	}
+
+	return c.checkStreamCount(mgr, check)
+}
+
 func (c *SrvCheckCmd) checkRaft(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: "JetStream Meta Cluster", Check: "meta", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
 
 	checkOpts := monitor.CheckJetstreamMetaOptions{
 		ExpectServers: c.raftExpect,
@@ -474,22 +1188,37 @@ func (c *SrvCheckCmd) checkRaft(_ *fisk.ParseContext) error {
 		SeenCritical:  c.raftSeenCritical.Seconds(),
 	}
 
-	var err error
-	nc := opts().Conn
-
-	if nc == nil {
-		err = monitor.CheckJetstreamMeta(opts().Config.ServerURL(), natsOpts(), check, checkOpts)
-	} else {
-		err = monitor.CheckJetstreamMetaWithConnection(nc, check, checkOpts)
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
 	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		err := monitor.CheckJetstreamMetaWithConnection(nc, check, checkOpts)
+		if err != nil {
+			return err
+		}
+
+		err = c.checkLeaderChanges(nc, check)
+		if err != nil {
+			return err
+		}
+
+		return c.checkMetaAPI(nc, check)
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
 }
 
 func (c *SrvCheckCmd) checkStream(_ *fisk.ParseContext) error {
-	check := &monitor.Result{Name: c.sourcesStream, Check: "stream", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	name := c.sourcesStream
+	if c.checkAllStreams {
+		name = "All Streams"
+	}
+
+	check := &monitor.Result{Name: name, Check: "stream", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
 
 	checkOpts := monitor.CheckStreamHealthOptions{
 		StreamName: c.sourcesStream,
@@ -529,27 +1258,196 @@ func (c *SrvCheckCmd) checkStream(_ *fisk.ParseContext) error {
 		checkOpts.SubjectsCrit = c.subjectsCrit
 	}
 
+	checkOpts.HealthChecks = append(checkOpts.HealthChecks, checkStreamPeerExpect)
+
 	logger := api.NewDiscardLogger()
 	if opts().Trace {
 		logger = api.NewDefaultLogger(api.TraceLevel)
 	}
 
-	var err error
-	mgr := opts().Mgr
+	mgr, err := c.checkMgr()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	switch {
+	case c.checkAllStreams && c.sourcesStream != "":
+		check.Critical("--stream and --check-all-streams are mutually exclusive")
+		return nil
+	case !c.checkAllStreams && c.sourcesStream == "":
+		check.Critical("stream name is required")
+		return nil
+	case (c.streamRateWarn != 0 || c.streamRateCrit != 0) && c.streamRateStateFile == "":
+		check.Critical("--state-file is required when checking message rates")
+		return nil
+	}
 
-	if mgr == nil {
-		err = monitor.CheckStreamHealth(opts().Config.ServerURL(), natsOpts(), jsmOpts(), check, checkOpts, logger)
-	} else {
-		err = monitor.CheckStreamHealthWithConnection(mgr, check, checkOpts, logger)
+	var rates map[string]streamRateState
+	if c.streamRateStateFile != "" {
+		rates, err = c.loadStreamRates()
+		if check.CriticalIfErrf(err, "loading state failed: %v", err) {
+			return nil
+		}
+
+		checkOpts.HealthChecks = append(checkOpts.HealthChecks, c.streamRateCheck(rates))
 	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		var err error
+		if c.checkAllStreams {
+			err = c.checkEveryStream(mgr, check, checkOpts, logger)
+		} else {
+			err = monitor.CheckStreamHealthWithConnection(mgr, check, checkOpts, logger)
+		}
+		if err != nil || rates == nil {
+			return err
+		}
+
+		// the rates are updated by the check so they are saved here, a check that timed out saves once it completes
+		err = c.saveStreamRates(rates)
+		check.CriticalIfErrf(err, "saving state failed: %v", err)
+
+		return nil
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
 }
 
+// checkStreamPeerExpect warns when peer lag or activity thresholds are set without an expected peer count, the options include those set using stream metadata
+func checkStreamPeerExpect(_ *jsm.Stream, check *monitor.Result, opts monitor.CheckStreamHealthOptions, _ api.Logger) {
+	if (opts.ClusterLagCritical > 0 || opts.ClusterSeenCritical > 0) && opts.ClusterExpectedPeers == 0 {
+		check.Warn("peer thresholds set without an expected peer count, the number of peers is not checked")
+	}
+}
+
+// checkEveryStream checks all streams in the account, reporting the failing streams and totals
+func (c *SrvCheckCmd) checkEveryStream(mgr *jsm.Manager, check *monitor.Result, checkOpts monitor.CheckStreamHealthOptions, logger api.Logger) error {
+	names, err := mgr.StreamNames(nil)
+	if err != nil {
+		return err
+	}
+
+	checkEach(check, "streams", names, func(name string, res *monitor.Result) error {
+		opts := checkOpts
+		opts.StreamName = name
+
+		return monitor.CheckStreamHealthWithConnection(mgr, res, opts, logger)
+	})
+
+	return nil
+}
+
+// checkEveryConsumer checks all consumers on a stream, reporting the failing consumers and totals
+func (c *SrvCheckCmd) checkEveryConsumer(mgr *jsm.Manager, check *monitor.Result, checkOpts monitor.CheckConsumerHealthOptions, logger api.Logger) error {
+	names, err := mgr.ConsumerNames(checkOpts.StreamName)
+	if err != nil {
+		return err
+	}
+
+	checkEach(check, "consumers", names, func(name string, res *monitor.Result) error {
+		opts := checkOpts
+		opts.ConsumerName = name
+
+		err := monitor.CheckConsumerHealthWithConnection(mgr, res, opts, logger)
+
+		// keep the per consumer perf data, prefixed by the consumer name so they do not clash
+		prefix := perfDataNameRe.ReplaceAllString(name, "_")
+		for _, pd := range res.PerfData {
+			item := *pd
+			item.Name = prefix + "_" + pd.Name
+			item.Help = fmt.Sprintf("%s for consumer %s", pd.Help, name)
+			check.Pd(&item)
+		}
+
+		return err
+	})
+
+	return nil
+}
+
+// checkConsumerWarnings raises warnings for consumer values that passed the warning thresholds but not the critical ones
+func (c *SrvCheckCmd) checkConsumerWarnings(consumer *jsm.Consumer, check *monitor.Result, opts monitor.CheckConsumerHealthOptions, _ api.Logger) {
+	nfo, err := consumer.LatestState()
+	if err != nil {
+		return
+	}
+
+	setWarn := func(name string, warn float64) {
+		for _, pd := range check.PerfData {
+			if pd.Name == name {
+				pd.Warn = warn
+			}
+		}
+	}
+
+	countWarning := func(pdName string, label string, value int, warn int, crit int) {
+		if warn <= 0 {
+			return
+		}
+		setWarn(pdName, float64(warn))
+
+		if value >= warn && (crit <= 0 || value < crit) {
+			check.Warnf("%s: %d", label, value)
+		}
+	}
+
+	ageWarning := func(pdName string, label string, last *time.Time, warn time.Duration, crit float64) {
+		if warn <= 0 || last == nil {
+			return
+		}
+		setWarn(pdName, warn.Seconds())
+
+		since := time.Since(*last)
+		if since >= warn && (crit <= 0 || since.Seconds() < crit) {
+			check.Warnf("%s %v ago", label, since.Round(time.Millisecond))
+		}
+	}
+
+	countWarning("ack_pending", "Ack Pending", nfo.NumAckPending, c.consumerAckOutstandingWarn, opts.AckOutstandingCritical)
+	countWarning("pull_waiting", "Waiting Pulls", nfo.NumWaiting, c.consumerWaitingWarn, opts.WaitingCritical)
+	countWarning("pending", "Unprocessed Messages", int(nfo.NumPending), c.consumerUnprocessedWarn, opts.UnprocessedCritical)
+	countWarning("redelivered", "Redelivered", nfo.NumRedelivered, c.consumerRedeliveryWarn, opts.RedeliveryCritical)
+	ageWarning("last_delivery", "Last delivery", nfo.Delivered.Last, c.consumerLastDeliveryWarn, opts.LastDeliveryCritical)
+	ageWarning("last_ack", "Last ack", nfo.AckFloor.Last, c.consumerLastAckWarn, opts.LastAckCritical)
+}
+
+// checkEach runs cb for every name and reports the worst status along with totals as perf data
+func checkEach(check *monitor.Result, kind string, names []string, cb func(name string, res *monitor.Result) error) {
+	var healthy, warning, critical int
+
+	for _, name := range names {
+		res := &monitor.Result{Name: name, Check: check.Check}
+		err := cb(name, res)
+		if err != nil {
+			res.Criticalf("check failed: %v", err)
+		}
+
+		switch {
+		case len(res.Criticals) > 0:
+			critical++
+			check.Criticalf("%s: %s", name, strings.Join(res.Criticals, ", "))
+		case len(res.Warnings) > 0:
+			warning++
+			check.Warnf("%s: %s", name, strings.Join(res.Warnings, ", "))
+		default:
+			healthy++
+		}
+	}
+
+	check.Pd(
+		&monitor.PerfDataItem{Name: kind + "_total", Value: float64(len(names)), Help: fmt.Sprintf("The number of %s that were checked", kind)},
+		&monitor.PerfDataItem{Name: kind + "_healthy", Value: float64(healthy), Help: fmt.Sprintf("The number of %s without warnings or critical problems", kind)},
+		&monitor.PerfDataItem{Name: kind + "_warning", Value: float64(warning), Help: fmt.Sprintf("The number of %s with warnings", kind)},
+		&monitor.PerfDataItem{Name: kind + "_critical", Value: float64(critical), Help: fmt.Sprintf("The number of %s with critical problems", kind)},
+	)
+
+	check.OkIfNoWarningsOrCriticalsf("%d %s healthy", healthy, kind)
+}
+
 func (c *SrvCheckCmd) checkMsg(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: "Stream Message", Check: "message", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
 
 	checkOpts := monitor.CheckStreamMessageOptions{
 		StreamName:      c.sourcesStream,
@@ -560,14 +1458,29 @@ func (c *SrvCheckCmd) checkMsg(_ *fisk.ParseContext) error {
 		BodyAsTimestamp: c.msgBodyAsTs,
 	}
 
-	var err error
-	mgr := opts().Mgr
+	mgr, err := c.checkMgr()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
 
-	if mgr == nil {
-		err = monitor.CheckStreamMessage(opts().Config.ServerURL(), natsOpts(), jsmOpts(), check, checkOpts)
-	} else {
-		err = monitor.CheckStreamMessageWithConnection(mgr, check, checkOpts)
+	timestampSources := 0
+	for _, set := range []bool{c.msgBodyAsTs, c.msgTimestampPath != "", c.msgTimestampHeader != ""} {
+		if set {
+			timestampSources++
+		}
+	}
+	if timestampSources > 1 {
+		check.Critical("--body-timestamp, --body-timestamp-json-path and --timestamp-header are mutually exclusive")
+		return nil
 	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		if c.msgTimestampPath != "" || c.msgValuePath != "" || c.msgTimestampHeader != "" {
+			return c.checkMsgExtended(mgr, check)
+		}
+
+		return monitor.CheckStreamMessageWithConnection(mgr, check, checkOpts)
+	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
 	return nil
@@ -575,7 +1488,7 @@ func (c *SrvCheckCmd) checkMsg(_ *fisk.ParseContext) error {
 
 func (c *SrvCheckCmd) checkConnection(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: "Connection", Check: "connections", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
 
 	if opts().Config == nil {
 		err := loadContext(false)
@@ -596,25 +1509,81 @@ func (c *SrvCheckCmd) checkConnection(_ *fisk.ParseContext) error {
 	var err error
 	nc := opts().Conn
 
-	if nc == nil {
-		err = monitor.CheckConnection(opts().Config.ServerURL(), natsOpts(), opts().Timeout, check, checkOpts)
-	} else {
+	// connections made by the check itself, like when watching, are not supplied connections
+	switch {
+	case nc != nil && nc != c.nc:
 		err = fmt.Errorf("connection checks are not supported when a connection is supplied")
+	case c.allServers:
+		check.Name = "All Servers"
+
+		nc, err = c.checkConn()
+		if err == nil {
+			err = c.checkEachServer(nc, check, func(vz *server.Varz, res *monitor.Result) error {
+				return monitor.CheckConnection(serverClientURL(vz), c.checkNatsOpts(), opts().Timeout, res, checkOpts)
+			})
+		}
+	default:
+		err = c.checkConnectionWithRetries(check, checkOpts)
 	}
 	check.CriticalIfErrf(err, "Check failed: %v", err)
+	c.filterPerfData(check)
 
 	return nil
 }
 
+// checkConnectionWithRetries performs the connection check, retrying failed checks using the retry policy
+func (c *SrvCheckCmd) checkConnectionWithRetries(check *monitor.Result, checkOpts monitor.CheckConnectionOptions) error {
+	var res *monitor.Result
+	err := c.connectWithRetries(func() error {
+		res = &monitor.Result{Name: check.Name, Check: check.Check}
+
+		err := monitor.CheckConnection(opts().Config.ServerURL(), c.checkNatsOpts(), opts().Timeout, res, checkOpts)
+		if err != nil {
+			return err
+		}
+		if len(res.Criticals) > 0 {
+			return fmt.Errorf("%s", strings.Join(res.Criticals, ", "))
+		}
+
+		return nil
+	})
+
+	check.OKs = append(check.OKs, res.OKs...)
+	check.Warnings = append(check.Warnings, res.Warnings...)
+	check.Criticals = append(check.Criticals, res.Criticals...)
+	check.PerfData = append(check.PerfData, res.PerfData...)
+
+	// failures are already reported by the last attempt
+	if len(res.Criticals) > 0 {
+		return nil
+	}
+
+	return err
+}
+
 func (c *SrvCheckCmd) checkCredentialAction(_ *fisk.ParseContext) error {
 	check := &monitor.Result{Name: "Credential", Check: "credential", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
-	defer check.GenericExit()
+	defer c.finish(check)
+
+	if (c.credential == "") == (c.credentialDir == "") {
+		check.Critical("either --credential or --credential-dir is required")
+		return nil
+	}
 
-	err := monitor.CheckCredential(check, monitor.CheckCredentialOptions{
+	checkOpts := monitor.CheckCredentialOptions{
 		File:             c.credential,
 		ValidityWarning:  c.credentialValidityWarn.Seconds(),
 		ValidityCritical: c.credentialValidityCrit.Seconds(),
 		RequiresExpiry:   c.credentialRequiresExpire,
+	}
+
+	err := c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		if c.credentialDir != "" {
+			check.Name = c.credentialDir
+			return c.checkCredentialDir(check, checkOpts)
+		}
+
+		return monitor.CheckCredential(check, checkOpts)
 	})
 	check.CriticalIfErrf(err, "Check failed: %v", err)
 
@@ -0,0 +1,85 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jsm.go/monitor"
+)
+
+// checkCredentialDir checks every credential in the credential directory and reports the soonest expiry
+func (c *SrvCheckCmd) checkCredentialDir(check *monitor.Result, checkOpts monitor.CheckCredentialOptions) error {
+	var files []string
+	err := filepath.WalkDir(c.credentialDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".creds") {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		check.Criticalf("no credentials found in %s", c.credentialDir)
+		return nil
+	}
+
+	var soonest *monitor.PerfDataItem
+	var soonestFile string
+
+	checkEach(check, "credentials", files, func(file string, res *monitor.Result) error {
+		opts := checkOpts
+		opts.File = file
+
+		err := monitor.CheckCredential(res, opts)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(res.OKs, "never expires") || slices.Contains(res.Criticals, "never expires") {
+			return nil
+		}
+
+		for _, pd := range res.PerfData {
+			if pd.Name != "expiry" {
+				continue
+			}
+
+			if soonest == nil || pd.Value < soonest.Value {
+				soonest = pd
+				soonestFile = file
+			}
+		}
+
+		return nil
+	})
+
+	if soonest != nil {
+		check.Pd(&monitor.PerfDataItem{Name: "expiry", Value: soonest.Value, Warn: checkOpts.ValidityWarning, Crit: checkOpts.ValidityCritical, Unit: "s", Help: "Expiry time in seconds of the soonest expiring credential"})
+		check.Okf("%s expires soonest in %s", soonestFile, f(time.Duration(soonest.Value)*time.Second))
+	}
+
+	return nil
+}
@@ -0,0 +1,437 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/jsm.go/serverdata"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// leaderChangeState is the state persisted between runs of the meta check to detect leader changes
+type leaderChangeState struct {
+	Leader  string      `json:"leader"`
+	Changes []time.Time `json:"changes,omitempty"`
+}
+
+// checkStreamCount checks the number of streams in the account against absolute thresholds, useful for accounts without limits
+func (c *SrvCheckCmd) checkStreamCount(mgr *jsm.Manager, check *monitor.Result) error {
+	if c.jsStreamCountWarn <= 0 && c.jsStreamCountCrit <= 0 {
+		return nil
+	}
+
+	info, err := mgr.JetStreamAccountInfo()
+	if err != nil {
+		return err
+	}
+
+	check.Pd(&monitor.PerfDataItem{Name: "stream_count", Value: float64(info.Streams), Warn: float64(c.jsStreamCountWarn), Crit: float64(c.jsStreamCountCrit), Help: "Number of streams in the account"})
+	checkThreshold(check, "Stream Count", float64(c.jsStreamCountCrit), float64(c.jsStreamCountWarn), float64(info.Streams), false)
+
+	return nil
+}
+
+// metaLeader finds the current JetStream meta leader
+func (c *SrvCheckCmd) metaLeader(nc *nats.Conn) (string, error) {
+	jsz, err := c.metaLeaderJsz(nc)
+	if err != nil {
+		return "", err
+	}
+
+	return jsz.Meta.Leader, nil
+}
+
+// metaLeaderJsz retrieves JetStream information from the meta leader
+func (c *SrvCheckCmd) metaLeaderJsz(nc *nats.Conn) (*server.JSInfo, error) {
+	ds, err := c.checkDataSource(nc)
+	if err != nil {
+		return nil, err
+	}
+	defer ds.Close()
+
+	res, err := ds.Jsz(server.JszEventOptions{JSzOptions: server.JSzOptions{LeaderOnly: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no JetStream information received")
+	}
+	if res[0].Error != nil {
+		return nil, fmt.Errorf("invalid response received: %v", res[0].Error.Description)
+	}
+	if res[0].Data == nil || res[0].Data.Meta == nil {
+		return nil, fmt.Errorf("no JetStream cluster information received")
+	}
+	if res[0].Data.Meta.Leader == "" {
+		return nil, fmt.Errorf("no meta leader elected")
+	}
+
+	return res[0].Data, nil
+}
+
+// checkLeaderChanges records the meta leader in a state file and alerts when it changed too often within the window
+func (c *SrvCheckCmd) checkLeaderChanges(nc *nats.Conn, check *monitor.Result) error {
+	if c.raftLeaderFile == "" {
+		return nil
+	}
+
+	leader, err := c.metaLeader(nc)
+	if err != nil {
+		return err
+	}
+
+	var state leaderChangeState
+	sb, err := os.ReadFile(c.raftLeaderFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		err = json.Unmarshal(sb, &state)
+		if err != nil {
+			return fmt.Errorf("invalid leader state file %s: %v", c.raftLeaderFile, err)
+		}
+	}
+
+	now := time.Now()
+	changed := state.Leader != "" && state.Leader != leader
+	if changed {
+		state.Changes = append(state.Changes, now)
+
+		switch c.raftLeaderChanged {
+		case "critical":
+			check.Criticalf("leader changed from %s to %s", state.Leader, leader)
+		case "warning":
+			check.Warnf("leader changed from %s to %s", state.Leader, leader)
+		}
+	}
+	state.Leader = leader
+	state.Changes = slices.DeleteFunc(state.Changes, func(t time.Time) bool {
+		return now.Sub(t) > c.raftLeaderWindow
+	})
+
+	sb, err = json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomic(c.raftLeaderFile, sb)
+	if err != nil {
+		return err
+	}
+
+	changes := len(state.Changes)
+	check.Pd(&monitor.PerfDataItem{Name: "leader_changes", Value: float64(changes), Warn: float64(c.raftLeaderChangesWarn), Crit: float64(c.raftLeaderChangesCrit), Help: "Meta leader changes within the window"})
+	checkThreshold(check, "Leader Changes", float64(c.raftLeaderChangesCrit), float64(c.raftLeaderChangesWarn), float64(changes), false)
+
+	return nil
+}
+
+// checkServerStorage checks the storage used on every JetStream server against the server limits, unlike the account limits this detects servers running out of resources when accounts are unlimited
+func (c *SrvCheckCmd) checkServerStorage(nc *nats.Conn, check *monitor.Result) error {
+	if c.jsSrvMemWarn == 0 && c.jsSrvMemCrit == 0 && c.jsSrvStoreWarn == 0 && c.jsSrvStoreCrit == 0 {
+		return nil
+	}
+
+	reqFn := func(req any, subj string, waitFor int, nc *nats.Conn) ([][]byte, error) {
+		return serverdata.DoReq(ctx, req, subj, waitFor, nc, c.requestTimeout(), traceLogger())
+	}
+
+	ds, err := serverdata.NewLive(nc, reqFn, 0)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	res, err := ds.Jsz(server.JszEventOptions{})
+	if err != nil {
+		return err
+	}
+
+	var memPct, storePct float64
+	var servers int
+
+	for _, r := range res {
+		if r.Error != nil || r.Data == nil || r.Server == nil || r.Data.Disabled {
+			continue
+		}
+		servers++
+
+		if r.Data.Config.MaxMemory > 0 {
+			pct := float64(r.Data.Memory) / float64(r.Data.Config.MaxMemory) * 100
+			memPct = max(memPct, pct)
+			checkThreshold(check, fmt.Sprintf("%s Memory %%", r.Server.Name), float64(c.jsSrvMemCrit), float64(c.jsSrvMemWarn), pct, false)
+		}
+
+		if r.Data.Config.MaxStore > 0 {
+			pct := float64(r.Data.Store) / float64(r.Data.Config.MaxStore) * 100
+			storePct = max(storePct, pct)
+			checkThreshold(check, fmt.Sprintf("%s Storage %%", r.Server.Name), float64(c.jsSrvStoreCrit), float64(c.jsSrvStoreWarn), pct, false)
+		}
+	}
+
+	if servers == 0 {
+		return fmt.Errorf("no JetStream servers discovered, ensure the system account is used")
+	}
+
+	check.Pd(
+		&monitor.PerfDataItem{Name: "server_memory_pct", Value: memPct, Warn: float64(c.jsSrvMemWarn), Crit: float64(c.jsSrvMemCrit), Unit: "%", Help: "Memory storage used on the fullest server, in percent of the server limit"},
+		&monitor.PerfDataItem{Name: "server_storage_pct", Value: storePct, Warn: float64(c.jsSrvStoreWarn), Crit: float64(c.jsSrvStoreCrit), Unit: "%", Help: "File storage used on the fullest server, in percent of the server limit"},
+	)
+
+	return nil
+}
+
+// checkJetStreamServers checks JetStream on every server using the system account, the combined result names failing servers
+func (c *SrvCheckCmd) checkJetStreamServers(nc *nats.Conn, check *monitor.Result) error {
+	reqFn := func(req any, subj string, waitFor int, nc *nats.Conn) ([][]byte, error) {
+		return serverdata.DoReq(ctx, req, subj, waitFor, nc, c.requestTimeout(), traceLogger())
+	}
+
+	ds, err := serverdata.NewLive(nc, reqFn, 0)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	res, err := ds.Jsz(server.JszEventOptions{})
+	if err != nil {
+		return err
+	}
+
+	responses := make(map[string]*server.ServerAPIJszResponse)
+	for _, r := range res {
+		if r.Server != nil {
+			responses[r.Server.Name] = r
+		}
+	}
+
+	if len(responses) == 0 {
+		return fmt.Errorf("no servers discovered, ensure the system account is used")
+	}
+
+	names := slices.Sorted(maps.Keys(responses))
+
+	memWarn, memCrit := c.jsSrvMemWarn, c.jsSrvMemCrit
+	if memWarn == 0 && memCrit == 0 {
+		memWarn, memCrit = c.jsMemWarn, c.jsMemCritical
+	}
+	storeWarn, storeCrit := c.jsSrvStoreWarn, c.jsSrvStoreCrit
+	if storeWarn == 0 && storeCrit == 0 {
+		storeWarn, storeCrit = c.jsStoreWarn, c.jsStoreCritical
+	}
+
+	checkEach(check, "servers", names, func(name string, res *monitor.Result) error {
+		r := responses[name]
+
+		switch {
+		case r.Error != nil:
+			return r.Error
+		case r.Data == nil || r.Data.Disabled:
+			res.Critical("JetStream not enabled")
+			return nil
+		}
+
+		if r.Data.Config.MaxMemory > 0 {
+			pct := float64(r.Data.Memory) / float64(r.Data.Config.MaxMemory) * 100
+			checkThreshold(res, "Memory %", float64(memCrit), float64(memWarn), pct, false)
+		}
+
+		if r.Data.Config.MaxStore > 0 {
+			pct := float64(r.Data.Store) / float64(r.Data.Config.MaxStore) * 100
+			checkThreshold(res, "Storage %", float64(storeCrit), float64(storeWarn), pct, false)
+		}
+
+		return nil
+	})
+
+	return nil
+}
+
+// checkMetaAPI checks the JetStream API usage on the meta leader, a backlog indicates the meta leader is overloaded
+func (c *SrvCheckCmd) checkMetaAPI(nc *nats.Conn, check *monitor.Result) error {
+	if !c.raftAPIStats && c.raftAPIInflightWarn == 0 && c.raftAPIInflightCrit == 0 && c.raftAPIPendingWarn == 0 && c.raftAPIPendingCrit == 0 {
+		return nil
+	}
+
+	jsz, err := c.metaLeaderJsz(nc)
+	if err != nil {
+		return err
+	}
+
+	stats := jsz.JetStreamStats.API
+	pending := jsz.Meta.PendingRequests
+
+	check.Pd(
+		&monitor.PerfDataItem{Name: "js_api_total", Value: float64(stats.Total), Help: "JetStream API requests received by the meta leader since start"},
+		&monitor.PerfDataItem{Name: "js_api_errors", Value: float64(stats.Errors), Help: "JetStream API requests that resulted in errors on the meta leader since start"},
+		&monitor.PerfDataItem{Name: "js_api_inflight", Value: float64(stats.Inflight), Warn: float64(c.raftAPIInflightWarn), Crit: float64(c.raftAPIInflightCrit), Help: "JetStream API requests being served by the meta leader"},
+		&monitor.PerfDataItem{Name: "js_api_pending", Value: float64(pending), Warn: float64(c.raftAPIPendingWarn), Crit: float64(c.raftAPIPendingCrit), Help: "JetStream API requests queued on the meta leader"},
+	)
+
+	checkThreshold(check, "API In-flight", float64(c.raftAPIInflightCrit), float64(c.raftAPIInflightWarn), float64(stats.Inflight), false)
+	checkThreshold(check, "API Pending", float64(c.raftAPIPendingCrit), float64(c.raftAPIPendingWarn), float64(pending), false)
+
+	return nil
+}
+
+func (c *SrvCheckCmd) checkStreamConsumers(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.sourcesStream, Check: "stream_consumers", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	mgr, err := c.checkMgr()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		stream, err := mgr.LoadStream(c.sourcesStream)
+		if err != nil {
+			return err
+		}
+
+		state, err := stream.LatestState()
+		if err != nil {
+			return err
+		}
+
+		check.Pd(&monitor.PerfDataItem{Name: "stream_consumers", Value: float64(state.Consumers), Warn: float64(c.streamConsumersWarn), Crit: float64(c.streamConsumersCrit), Help: "Number of consumers on the stream"})
+
+		if c.streamConsumersWarn == 0 && c.streamConsumersCrit == 0 {
+			check.Okf("%d consumers", state.Consumers)
+			return nil
+		}
+
+		checkThreshold(check, "Consumers", float64(c.streamConsumersCrit), float64(c.streamConsumersWarn), float64(state.Consumers), true)
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// checkKVHealth checks the cluster health of the stream backing a bucket and the age of the newest value of the checked key
+func (c *SrvCheckCmd) checkKVHealth(mgr *jsm.Manager, check *monitor.Result) error {
+	// missing buckets are reported by the monitor package
+	known, err := mgr.IsKnownStream("KV_" + c.kvBucket)
+	if err != nil || !known {
+		return err
+	}
+
+	stream, err := mgr.LoadStream("KV_" + c.kvBucket)
+	if err != nil {
+		return err
+	}
+
+	nfo, err := stream.LatestInformation()
+	if err != nil {
+		return err
+	}
+
+	c.checkStreamReplicas(check, nfo)
+
+	if c.kvKeyAgeWarn <= 0 && c.kvKeyAgeCrit <= 0 {
+		return nil
+	}
+
+	msg, err := mgr.ReadLastMessageForSubject(stream.Name(), fmt.Sprintf("$KV.%s.%s", c.kvBucket, c.kvKey))
+	if jsm.IsNatsError(err, 10037) {
+		// missing keys are reported by the monitor package
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	age := time.Since(msg.Time)
+	check.Pd(&monitor.PerfDataItem{Name: "key_age", Value: age.Seconds(), Warn: c.kvKeyAgeWarn.Seconds(), Crit: c.kvKeyAgeCrit.Seconds(), Unit: "s", Help: "The age of the newest value of the key"})
+
+	switch {
+	case c.kvKeyAgeCrit > 0 && age >= c.kvKeyAgeCrit:
+		check.Criticalf("key %s last updated %v ago", c.kvKey, f(age))
+	case c.kvKeyAgeWarn > 0 && age >= c.kvKeyAgeWarn:
+		check.Warnf("key %s last updated %v ago", c.kvKey, f(age))
+	default:
+		check.Okf("key %s last updated %v ago", c.kvKey, f(age))
+	}
+
+	return nil
+}
+
+// checkStreamReplicas checks the leader, replica count and replica health of a stream, the expected replicas default to those configured
+func (c *SrvCheckCmd) checkStreamReplicas(check *monitor.Result, nfo *api.StreamInfo) {
+	expect := c.raftExpect
+	if expect <= 0 {
+		expect = nfo.Config.Replicas
+	}
+
+	switch {
+	case nfo.Cluster == nil || nfo.Config.Replicas <= 1:
+		if expect > 1 {
+			check.Criticalf("Expected %d replicas but the stream is not clustered", expect)
+		}
+
+	case nfo.Cluster.Leader == "":
+		check.Critical("No leader")
+
+	default:
+		peers := len(nfo.Cluster.Replicas) + 1
+		if peers != expect {
+			check.Criticalf("Expected %d replicas got %d", expect, peers)
+		} else {
+			check.Okf("%d peers", peers)
+		}
+
+		var lag uint64
+		var lagged, inactive, offline int
+		for _, peer := range nfo.Cluster.Replicas {
+			lag = max(lag, peer.Lag)
+
+			switch {
+			case peer.Offline:
+				offline++
+			case c.raftLagCritical > 0 && peer.Lag > c.raftLagCritical:
+				lagged++
+			case c.raftSeenCritical > 0 && peer.Active > c.raftSeenCritical:
+				inactive++
+			}
+		}
+
+		check.Pd(&monitor.PerfDataItem{Name: "replica_lag", Value: float64(lag), Crit: float64(c.raftLagCritical), Help: "The highest number of operations a replica is behind the leader"})
+
+		if offline > 0 {
+			check.Criticalf("%d replicas offline", offline)
+		}
+		if lagged > 0 {
+			check.Criticalf("%d replicas lagged", lagged)
+		}
+		if inactive > 0 {
+			check.Criticalf("%d replicas inactive", inactive)
+		}
+	}
+}
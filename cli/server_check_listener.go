@@ -0,0 +1,292 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+)
+
+// websocketGUID is the RFC 6455 value used to calculate Sec-WebSocket-Accept
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// mqttConnackCodes are the MQTT 3.1.1 CONNACK return codes
+var mqttConnackCodes = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+func (c *SrvCheckCmd) checkWebsocket(_ *fisk.ParseContext) error {
+	return c.checkListener("websocket", "ws", "wss", "80", "443", c.websocketHandshake)
+}
+
+func (c *SrvCheckCmd) checkMQTT(_ *fisk.ParseContext) error {
+	return c.checkListener("mqtt", "mqtt", "mqtts", "1883", "8883", c.mqttHandshake)
+}
+
+// checkListener connects to --address, performs the TLS handshake for the secure scheme and then the protocol handshake
+func (c *SrvCheckCmd) checkListener(kind string, scheme string, secureScheme string, port string, securePort string, handshake func(conn net.Conn, u *url.URL, check *monitor.Result) error) error {
+	check := &monitor.Result{Name: c.listenerAddress, Check: kind, OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	if opts().Config == nil {
+		err := loadContext(false)
+		if check.CriticalIfErrf(err, "loading context failed: %v", err) {
+			return nil
+		}
+	}
+
+	addr := c.listenerAddress
+	if !strings.Contains(addr, "://") {
+		addr = scheme + "://" + addr
+	}
+
+	u, err := url.Parse(addr)
+	if check.CriticalIfErrf(err, "invalid address: %v", err) {
+		return nil
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case scheme:
+	case secureScheme, "tls", "ssl":
+		secure = true
+	default:
+		check.Criticalf("unsupported scheme %q", u.Scheme)
+		return nil
+	}
+
+	if u.Port() == "" {
+		if secure {
+			u.Host = net.JoinHostPort(u.Hostname(), securePort)
+		} else {
+			u.Host = net.JoinHostPort(u.Hostname(), port)
+		}
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		timeout := c.requestTimeout()
+		start := time.Now()
+
+		dialer := net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+		if err != nil {
+			check.Criticalf("connection failed: %v", err)
+			return nil
+		}
+		defer conn.Close()
+
+		err = conn.SetDeadline(start.Add(timeout))
+		if err != nil {
+			return err
+		}
+
+		if secure {
+			tlsc, err := c.listenerTLSConfig(u.Hostname())
+			if err != nil {
+				return err
+			}
+
+			tconn := tls.Client(conn, tlsc)
+			err = tconn.Handshake()
+			if err != nil {
+				check.Criticalf("TLS handshake failed: %v", err)
+				return nil
+			}
+			conn = tconn
+		}
+
+		err = handshake(conn, u, check)
+		if err != nil {
+			check.Criticalf("%s handshake failed: %v", kind, err)
+			return nil
+		}
+
+		took := time.Since(start)
+		check.Pd(&monitor.PerfDataItem{Name: "handshake_time", Value: took.Seconds(), Warn: c.listenerHandshakeWarn.Seconds(), Crit: c.listenerHandshakeCrit.Seconds(), Unit: "s", Help: "Time taken to connect and complete the protocol handshake"})
+
+		switch {
+		case c.listenerHandshakeCrit > 0 && took >= c.listenerHandshakeCrit:
+			check.Criticalf("handshake took %v", f(took))
+		case c.listenerHandshakeWarn > 0 && took >= c.listenerHandshakeWarn:
+			check.Warnf("handshake took %v", f(took))
+		}
+
+		check.OkIfNoWarningsOrCriticalsf("handshake completed in %v", f(took))
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// listenerTLSConfig verifies the server against the CA from the context, or the system roots, and presents the context certificate when set
+func (c *SrvCheckCmd) listenerTLSConfig(host string) (*tls.Config, error) {
+	tlsc := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+
+	if ca := opts().Config.CA(); ca != "" {
+		pem, err := os.ReadFile(ca)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsc.RootCAs = x509.NewCertPool()
+		if !tlsc.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", ca)
+		}
+	}
+
+	if opts().Config.Certificate() != "" && opts().Config.Key() != "" {
+		cert, err := tls.LoadX509KeyPair(opts().Config.Certificate(), opts().Config.Key())
+		if err != nil {
+			return nil, err
+		}
+		tlsc.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsc, nil
+}
+
+// websocketHandshake upgrades the connection and verifies the Sec-WebSocket-Accept response
+func (c *SrvCheckCmd) websocketHandshake(conn net.Conn, u *url.URL, _ *monitor.Result) error {
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	path := u.RequestURI()
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", u.Host, path), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	err = req.Write(conn)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return fmt.Errorf("unexpected response %s", resp.Status)
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+		return fmt.Errorf("invalid Sec-WebSocket-Accept header")
+	}
+
+	return nil
+}
+
+// mqttHandshake sends a MQTT 3.1.1 CONNECT using the context user and password and waits for the CONNACK, a rejected CONNACK still shows the listener is up and is a warning
+func (c *SrvCheckCmd) mqttHandshake(conn net.Conn, _ *url.URL, check *monitor.Result) error {
+	user := opts().Config.User()
+	pass := opts().Config.Password()
+
+	mqttString := func(s string) []byte {
+		b := binary.BigEndian.AppendUint16(nil, uint16(len(s)))
+		return append(b, s...)
+	}
+
+	// clean session with a 30 second keep alive
+	flags := byte(0x02)
+	payload := mqttString(fmt.Sprintf("nats-check-%d", time.Now().UnixNano()))
+	if user != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(user)...)
+		if pass != "" {
+			flags |= 0x40
+			payload = append(payload, mqttString(pass)...)
+		}
+	}
+
+	body := append(mqttString("MQTT"), 0x04, flags, 0x00, 30)
+	body = append(body, payload...)
+
+	// remaining length is a variable length integer
+	packet := []byte{0x10}
+	for l := len(body); ; {
+		b := byte(l % 128)
+		l /= 128
+		if l > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if l == 0 {
+			break
+		}
+	}
+	packet = append(packet, body...)
+
+	_, err := conn.Write(packet)
+	if err != nil {
+		return err
+	}
+
+	connack := make([]byte, 4)
+	_, err = io.ReadFull(conn, connack)
+	if err != nil {
+		return err
+	}
+
+	if connack[0] != 0x20 || connack[1] != 0x02 {
+		return fmt.Errorf("invalid CONNACK received")
+	}
+
+	if connack[3] != 0 {
+		reason, ok := mqttConnackCodes[connack[3]]
+		if !ok {
+			reason = fmt.Sprintf("return code %d", connack[3])
+		}
+		check.Warnf("connection refused: %s", reason)
+		return nil
+	}
+
+	// DISCONNECT
+	_, err = conn.Write([]byte{0xe0, 0x00})
+
+	return err
+}
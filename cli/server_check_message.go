@@ -0,0 +1,187 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/monitor"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// checkMsgExtended checks the newest message like the monitor package but takes the timestamp from headers or JSON bodies and checks values in JSON bodies
+func (c *SrvCheckCmd) checkMsgExtended(mgr *jsm.Manager, check *monitor.Result) error {
+	msg, err := mgr.ReadLastMessageForSubject(c.sourcesStream, c.msgSubject)
+	if jsm.IsNatsError(err, 10037) {
+		check.Critical("no message found")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var body any
+	if c.msgTimestampPath != "" || c.msgValuePath != "" {
+		err = json.Unmarshal(msg.Data, &body)
+		if check.CriticalIfErrf(err, "invalid JSON body: %v", err) {
+			return nil
+		}
+	}
+
+	ts := msg.Time
+	switch {
+	case c.msgTimestampHeader != "":
+		hdr, err := iu.DecodeHeadersMsg(msg.Header)
+		if check.CriticalIfErrf(err, "invalid headers: %v", err) {
+			return nil
+		}
+
+		val := hdr.Get(c.msgTimestampHeader)
+		if val == "" {
+			check.Criticalf("header %s not found", c.msgTimestampHeader)
+			return nil
+		}
+
+		ts, err = parseCheckTimestamp(val)
+		if check.CriticalIfErrf(err, "invalid timestamp in header %s: %v", c.msgTimestampHeader, err) {
+			return nil
+		}
+
+	case c.msgTimestampPath != "":
+		ts, err = jsonPathTime(body, c.msgTimestampPath)
+		if check.CriticalIfErrf(err, "invalid timestamp at %s: %v", c.msgTimestampPath, err) {
+			return nil
+		}
+	}
+
+	since := time.Since(ts)
+	check.Pd(
+		&monitor.PerfDataItem{Name: "age", Value: since.Round(time.Millisecond).Seconds(), Warn: c.msgAgeWarn.Seconds(), Crit: c.msgAgeCrit.Seconds(), Unit: "s", Help: "The age of the message"},
+		&monitor.PerfDataItem{Name: "size", Value: float64(len(msg.Data)), Unit: "B", Help: "The size of the message"},
+	)
+
+	switch {
+	case c.msgAgeCrit > 0 && since > c.msgAgeCrit:
+		check.Criticalf("%v old", since.Round(time.Millisecond))
+	case c.msgAgeWarn > 0 && since > c.msgAgeWarn:
+		check.Warnf("%v old", since.Round(time.Millisecond))
+	}
+
+	if c.msgValuePath != "" {
+		val, err := jsonPathFloat(body, c.msgValuePath)
+		if check.CriticalIfErrf(err, "invalid value at %s: %v", c.msgValuePath, err) {
+			return nil
+		}
+
+		check.Pd(&monitor.PerfDataItem{Name: "value", Value: val, Warn: c.msgValueWarn, Crit: c.msgValueCrit, Help: "The value extracted from the message"})
+		checkThreshold(check, "Value", c.msgValueCrit, c.msgValueWarn, val, true)
+	}
+
+	if c.msgRegexp != nil && !c.msgRegexp.Match(msg.Data) {
+		check.Criticalf("does not match regex: %s", c.msgRegexp.String())
+	}
+
+	check.OkIfNoWarningsOrCriticalsf("Valid message on %s > %s", c.sourcesStream, c.msgSubject)
+
+	return nil
+}
+
+// jsonPathLookup finds the value at a dot separated path like data.items.0.ts
+func jsonPathLookup(body any, path string) (any, error) {
+	cur := body
+
+	for _, part := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[part]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", part)
+			}
+			cur = next
+
+		case []any:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, fmt.Errorf("invalid index %q", part)
+			}
+			cur = v[idx]
+
+		default:
+			return nil, fmt.Errorf("cannot traverse into %q", part)
+		}
+	}
+
+	return cur, nil
+}
+
+// jsonPathFloat finds a numeric value, strings holding numbers are accepted
+func jsonPathFloat(body any, path string) (float64, error) {
+	val, err := jsonPathLookup(body, path)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("%v is not a number", val)
+	}
+}
+
+// jsonPathTime finds a timestamp stored as unix seconds or a RFC3339 string
+func jsonPathTime(body any, path string) (time.Time, error) {
+	val, err := jsonPathLookup(body, path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch v := val.(type) {
+	case float64:
+		return unixFloatTime(v), nil
+	case string:
+		return parseCheckTimestamp(v)
+	default:
+		return time.Time{}, fmt.Errorf("%v is not a timestamp", val)
+	}
+}
+
+// parseCheckTimestamp parses a RFC3339 timestamp or unix seconds
+func parseCheckTimestamp(v string) (time.Time, error) {
+	ts, err := time.Parse(time.RFC3339Nano, v)
+	if err == nil {
+		return ts, nil
+	}
+
+	secs, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a RFC3339 or unix timestamp", v)
+	}
+
+	return unixFloatTime(secs), nil
+}
+
+func unixFloatTime(secs float64) time.Time {
+	whole, frac := math.Modf(secs)
+
+	return time.Unix(int64(whole), int64(frac*float64(time.Second)))
+}
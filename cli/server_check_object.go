@@ -0,0 +1,126 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/nats.go/jetstream"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+func (c *SrvCheckCmd) checkObject(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.objBucket, Check: "object", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	sizeWarn, err := iu.ParseStringAsBytes(c.objSizeWarn, 64)
+	if check.CriticalIfErrf(err, "invalid size warning threshold: %v", err) {
+		return nil
+	}
+	sizeCrit, err := iu.ParseStringAsBytes(c.objSizeCrit, 64)
+	if check.CriticalIfErrf(err, "invalid size critical threshold: %v", err) {
+		return nil
+	}
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	mgr, err := c.checkMgr()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	js, err := newJetStreamWithOptions(nc, opts())
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		store, err := js.ObjectStore(ctx, c.objBucket)
+		if errors.Is(err, jetstream.ErrBucketNotFound) {
+			check.Criticalf("bucket %s not found", c.objBucket)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		check.Okf("bucket %s", c.objBucket)
+
+		status, err := store.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		size := float64(status.Size())
+		check.Pd(&monitor.PerfDataItem{Name: "bytes", Value: size, Warn: float64(max(sizeWarn, 0)), Crit: float64(max(sizeCrit, 0)), Unit: "B", Help: "Bytes stored in the bucket"})
+		checkThreshold(check, "Size", float64(max(sizeCrit, 0)), float64(max(sizeWarn, 0)), size, false)
+
+		objects, err := store.List(ctx)
+		if err != nil && !errors.Is(err, jetstream.ErrNoObjectsFound) {
+			return err
+		}
+
+		var newest time.Time
+		for _, obj := range objects {
+			if obj.ModTime.After(newest) {
+				newest = obj.ModTime
+			}
+		}
+
+		check.Pd(&monitor.PerfDataItem{Name: "objects", Value: float64(len(objects)), Warn: float64(c.objCountWarn), Crit: float64(c.objCountCrit), Help: "Objects stored in the bucket"})
+		checkThreshold(check, "Objects", float64(c.objCountCrit), float64(c.objCountWarn), float64(len(objects)), true)
+
+		if c.objAgeWarn > 0 || c.objAgeCrit > 0 {
+			if newest.IsZero() {
+				check.Critical("no objects found")
+			} else {
+				age := time.Since(newest)
+				check.Pd(&monitor.PerfDataItem{Name: "newest_age", Value: age.Seconds(), Warn: c.objAgeWarn.Seconds(), Crit: c.objAgeCrit.Seconds(), Unit: "s", Help: "The age of the most recently modified object"})
+
+				switch {
+				case c.objAgeCrit > 0 && age >= c.objAgeCrit:
+					check.Criticalf("last modified %v ago", f(age))
+				case c.objAgeWarn > 0 && age >= c.objAgeWarn:
+					check.Warnf("last modified %v ago", f(age))
+				default:
+					check.Okf("last modified %v ago", f(age))
+				}
+			}
+		}
+
+		stream, err := mgr.LoadStream("OBJ_" + c.objBucket)
+		if err != nil {
+			return err
+		}
+
+		nfo, err := stream.LatestInformation()
+		if err != nil {
+			return err
+		}
+
+		c.checkStreamReplicas(check, nfo)
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nats-io/jsm.go/monitor"
+)
+
+// renderCheck renders the check in the selected format, formats not supported by the monitor package are rendered here
+func renderCheck(check *monitor.Result) string {
+	// String() also prepares the status
+	out := check.String()
+
+	switch checkRenderFormatText {
+	case "zabbix":
+		return renderZabbix(check)
+	case "checkmk":
+		return renderCheckmk(check)
+	default:
+		return out
+	}
+}
+
+// checkStatusCode is the nagios compatible code for the status of a prepared result
+func checkStatusCode(check *monitor.Result) int {
+	switch check.Status {
+	case monitor.OKStatus:
+		return 0
+	case monitor.WarningStatus:
+		return 1
+	case monitor.CriticalStatus:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// checkPolicyCode is the status code adjusted by the --critical-only and --warning-as-ok exit code policies
+func checkPolicyCode(check *monitor.Result) int {
+	code := checkStatusCode(check)
+
+	switch {
+	case checkCriticalOnly && code >= 2:
+		return 1
+	case checkCriticalOnly:
+		return 0
+	case checkWarningAsOK && code == 1:
+		return 0
+	default:
+		return code
+	}
+}
+
+// checkExitCode is the exit code for a prepared result, formats that carry the status in their output always exit 0
+func checkExitCode(check *monitor.Result) int {
+	switch checkRenderFormatText {
+	case "prometheus", "zabbix", "checkmk":
+		return 0
+	default:
+		return checkPolicyCode(check)
+	}
+}
+
+// exitCheck writes the rendered check to the outfile or STDOUT and exits with the code matching its status
+func exitCheck(check *monitor.Result, out string) {
+	if check.OutFile != "" {
+		err := writeCheckOutFile(check.OutFile, out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not write %s: %v\n", check.OutFile, err)
+			os.Exit(1)
+		}
+	} else {
+		fmt.Println(out)
+	}
+
+	os.Exit(checkExitCode(check))
+}
+
+// writeCheckOutFile replaces the file atomically so collectors never read partial results
+func writeCheckOutFile(path string, out string) error {
+	return writeFileAtomic(path, []byte(out+"\n"))
+}
+
+// writeFileAtomic replaces path with data using a temporary file in the same directory, the file is only readable by the owner
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// checkMessages combines all messages like the nagios format
+func checkMessages(check *monitor.Result) string {
+	var res []string
+	for _, c := range check.Criticals {
+		res = append(res, fmt.Sprintf("Crit:%s", c))
+	}
+	for _, w := range check.Warnings {
+		res = append(res, fmt.Sprintf("Warn:%s", w))
+	}
+
+	if check.Output != "" {
+		res = append(res, check.Output)
+	} else {
+		for _, ok := range check.OKs {
+			res = append(res, fmt.Sprintf("OK:%s", ok))
+		}
+	}
+
+	return strings.Join(res, " ")
+}
+
+// renderZabbix renders a low level discovery item listing the perf data followed by key=value items in the form namespace.check[name,item]
+func renderZabbix(check *monitor.Result) string {
+	prefix := fmt.Sprintf("%s.%s", check.NameSpace, check.Check)
+	key := func(item string) string {
+		return fmt.Sprintf("%s[%s,%s]", prefix, zabbixKeyParam(check.Name), zabbixKeyParam(item))
+	}
+
+	discovery := struct {
+		Data []map[string]string `json:"data"`
+	}{Data: []map[string]string{}}
+	for _, pd := range check.PerfData {
+		discovery.Data = append(discovery.Data, map[string]string{"{#NAME}": check.Name, "{#ITEM}": pd.Name, "{#UNIT}": pd.Unit})
+	}
+
+	dj, err := json.Marshal(discovery)
+	if err != nil {
+		dj = []byte(`{"data":[]}`)
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s.discovery=%s", prefix, dj),
+		fmt.Sprintf("%s=%d", key("status"), checkStatusCode(check)),
+		fmt.Sprintf("%s=%s", key("message"), checkMessages(check)),
+	}
+
+	for _, pd := range check.PerfData {
+		lines = append(lines, fmt.Sprintf("%s=%s", key(pd.Name), strconv.FormatFloat(pd.Value, 'f', -1, 64)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// zabbixKeyParam quotes key parameters that contain characters with special meaning in item keys
+func zabbixKeyParam(p string) string {
+	if !strings.ContainsAny(p, `,[]" `) {
+		return p
+	}
+
+	return strconv.Quote(p)
+}
+
+// renderCheckmk renders a Checkmk local check line: status "service" perfdata message
+func renderCheckmk(check *monitor.Result) string {
+	var perf []string
+	for _, pd := range check.PerfData {
+		item := fmt.Sprintf("%s=%s", pd.Name, strconv.FormatFloat(pd.Value, 'f', -1, 64))
+
+		var warn, crit string
+		if pd.Warn != 0 {
+			warn = strconv.FormatFloat(pd.Warn, 'f', -1, 64)
+		}
+		if pd.Crit != 0 {
+			crit = strconv.FormatFloat(pd.Crit, 'f', -1, 64)
+		}
+		if warn != "" || crit != "" {
+			item = fmt.Sprintf("%s;%s;%s", item, warn, crit)
+		}
+
+		perf = append(perf, item)
+	}
+
+	perfData := "-"
+	if len(perf) > 0 {
+		perfData = strings.Join(perf, "|")
+	}
+
+	service := strings.ReplaceAll(fmt.Sprintf("NATS %s %s", check.Check, check.Name), `"`, "'")
+
+	return fmt.Sprintf(`%d "%s" %s %s`, checkStatusCode(check), service, perfData, checkMessages(check))
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/nats.go"
+)
+
+// checkNatsOpts are the connection options used by checks, adding --connect-timeout to the usual options
+func (c *SrvCheckCmd) checkNatsOpts() []nats.Option {
+	// checks close connections they are done with, like between retries, which should not terminate the cli
+	nopts := append(natsOpts(), nats.ClosedHandler(func(_ *nats.Conn) {}))
+	if c.connectTimeout > 0 {
+		nopts = append(nopts, nats.Timeout(c.connectTimeout))
+	}
+
+	return nopts
+}
+
+// connectWithRetries calls connect up to --retries more times while it fails, waiting --retry-interval between attempts
+func (c *SrvCheckCmd) connectWithRetries(connect func() error) error {
+	start := time.Now()
+	defer func() { c.connectSpent += time.Since(start) }()
+
+	var err error
+	for attempt := 0; attempt <= c.connectRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c.connectRetryInterval):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		c.connectAttempts++
+		err = connect()
+		if err == nil {
+			c.connectErr = nil
+			return nil
+		}
+	}
+
+	c.connectErr = err
+	if c.connectRetries > 0 {
+		return fmt.Errorf("%w after %d attempts", err, c.connectRetries+1)
+	}
+
+	return err
+}
+
+// recordConnectFailure adds the connection attempts and time spent to a check that could not connect
+func (c *SrvCheckCmd) recordConnectFailure(check *monitor.Result) {
+	if c.connectErr == nil {
+		return
+	}
+
+	check.Pd(
+		&monitor.PerfDataItem{Name: "connect_attempts", Value: float64(c.connectAttempts), Help: "Connection attempts made before the check failed"},
+		&monitor.PerfDataItem{Name: "connect_duration", Value: c.connectSpent.Seconds(), Unit: "s", Help: "Time spent connecting before the check failed"},
+	)
+}
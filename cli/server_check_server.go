@@ -0,0 +1,526 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/jsm.go/serverdata"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// checkDataSource creates a live server data source that waits for a single response
+func (c *SrvCheckCmd) checkDataSource(nc *nats.Conn) (serverdata.Source, error) {
+	reqFn := func(req any, subj string, waitFor int, nc *nats.Conn) ([][]byte, error) {
+		return serverdata.DoReq(ctx, req, subj, waitFor, nc, c.requestTimeout(), traceLogger())
+	}
+
+	return serverdata.NewLive(nc, reqFn, 1)
+}
+
+// fetchVarz retrieves Varz for a specific server, used as the server check resolver so the cli can perform additional checks
+func (c *SrvCheckCmd) fetchVarz(nc *nats.Conn, name string) (*server.Varz, error) {
+	if name == "" {
+		return nil, fmt.Errorf("server name is required")
+	}
+
+	ds, err := c.checkDataSource(nc)
+	if err != nil {
+		return nil, err
+	}
+	defer ds.Close()
+
+	res, err := ds.Varz(server.VarzEventOptions{EventFilterOptions: server.EventFilterOptions{Name: name, ExactMatch: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no data received for %s", name)
+	}
+	if res[0].Error != nil {
+		return nil, fmt.Errorf("invalid response received: %v", res[0].Error.Error())
+	}
+	if res[0].Data == nil {
+		return nil, fmt.Errorf("no data received for %s", name)
+	}
+
+	return res[0].Data, nil
+}
+
+// checkRoutes checks the number of distinct servers the named server is routed to, pooled connections to the same server are counted once
+func (c *SrvCheckCmd) checkRoutes(nc *nats.Conn, check *monitor.Result, name string) error {
+	if c.srvRoutesExpect <= 0 {
+		return nil
+	}
+
+	ds, err := c.checkDataSource(nc)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	res, err := ds.Routez(server.RoutezEventOptions{EventFilterOptions: server.EventFilterOptions{Name: name, ExactMatch: true}})
+	if err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		return fmt.Errorf("no route data received for %s", name)
+	}
+	if res[0].Error != nil {
+		return fmt.Errorf("invalid response received: %v", res[0].Error.Error())
+	}
+	if res[0].Data == nil {
+		return fmt.Errorf("no route data received for %s", name)
+	}
+
+	peers := make(map[string]struct{})
+	for _, route := range res[0].Data.Routes {
+		peers[route.RemoteID] = struct{}{}
+	}
+
+	check.Pd(&monitor.PerfDataItem{Name: "route_peers", Value: float64(len(peers)), Crit: float64(c.srvRoutesExpect), Help: "Servers this server has routes to"})
+
+	if len(peers) < c.srvRoutesExpect {
+		check.Criticalf("%d routed servers, expected %d", len(peers), c.srvRoutesExpect)
+	} else {
+		check.Okf("%d routed servers", len(peers))
+	}
+
+	return nil
+}
+
+// checkThreshold checks value against warn and crit, a 0 threshold is not checked, when invertible and crit is smaller than warn the check alerts on low values
+func checkThreshold(check *monitor.Result, name string, crit float64, warn float64, value float64, invertible bool) {
+	if crit == 0 && warn == 0 {
+		return
+	}
+
+	bothSet := crit != 0 && warn != 0
+
+	if bothSet && crit < warn {
+		if !invertible {
+			check.Criticalf("%s invalid thresholds", name)
+			return
+		}
+
+		switch {
+		case value <= crit:
+			check.Criticalf("%s %.2f", name, value)
+		case value <= warn:
+			check.Warnf("%s %.2f", name, value)
+		default:
+			check.Okf("%s %.2f", name, value)
+		}
+
+		return
+	}
+
+	switch {
+	case crit != 0 && value >= crit:
+		check.Criticalf("%s %.2f", name, value)
+	case warn != 0 && value >= warn:
+		check.Warnf("%s %.2f", name, value)
+	default:
+		check.Okf("%s %.2f", name, value)
+	}
+}
+
+// checkVarz performs checks on server variables that are not supported by the monitor package
+func (c *SrvCheckCmd) checkVarz(check *monitor.Result, vz *server.Varz) {
+	// perf data is numeric only so the cluster is reported as an ok message for inventory purposes
+	if vz.Cluster.Name != "" {
+		check.Okf("Cluster %s", vz.Cluster.Name)
+	}
+
+	// with route pooling every peer has pool size connections so this is not the number of peers
+	check.Pd(&monitor.PerfDataItem{Name: "routes", Value: float64(vz.Routes), Help: "Active route connections"})
+
+	if c.srvConnPoolWarn > 0 || c.srvConnPoolCrit > 0 {
+		if vz.MaxConn <= 0 {
+			check.Criticalf("Connection limit is not known")
+		} else {
+			pct := float64(vz.Connections) / float64(vz.MaxConn) * 100
+			check.Pd(&monitor.PerfDataItem{Name: "connection_pool_pct", Value: pct, Warn: float64(c.srvConnPoolWarn), Crit: float64(c.srvConnPoolCrit), Unit: "%", Help: "Connections in percent of the maximum connections"})
+			checkThreshold(check, "Connection Pool", float64(c.srvConnPoolCrit), float64(c.srvConnPoolWarn), pct, false)
+		}
+	}
+
+	if c.srvJSAPIQueueWarn > 0 || c.srvJSAPIQueueCrit > 0 {
+		// the queue depth is reported with the meta group information, standalone servers and older versions do not report it
+		if vz.JetStream.Meta == nil {
+			check.Warnf("JetStream API queue depth unknown, not reported by %s version %s", vz.Name, vz.Version)
+		} else {
+			pending := float64(vz.JetStream.Meta.PendingRequests)
+			check.Pd(&monitor.PerfDataItem{Name: "js_api_pending", Value: pending, Warn: float64(c.srvJSAPIQueueWarn), Crit: float64(c.srvJSAPIQueueCrit), Help: "JetStream API requests queued for processing"})
+			checkThreshold(check, "JetStream API Queue", float64(c.srvJSAPIQueueCrit), float64(c.srvJSAPIQueueWarn), pending, false)
+		}
+	}
+
+	if c.srvRequireWS {
+		if vz.Websocket.Port > 0 {
+			check.Okf("WebSocket listening on port %d", vz.Websocket.Port)
+		} else {
+			check.Criticalf("WebSocket not enabled")
+		}
+	}
+}
+
+// checkServerAccounts checks the number of accounts loaded on the server
+func (c *SrvCheckCmd) checkServerAccounts(nc *nats.Conn, check *monitor.Result, name string) error {
+	if c.srvExpectAccounts == 0 && c.srvMaxAccounts == 0 {
+		return nil
+	}
+
+	ds, err := c.checkDataSource(nc)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	res, err := ds.Accountz(server.AccountzEventOptions{EventFilterOptions: server.EventFilterOptions{Name: name, ExactMatch: true}})
+	if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		return fmt.Errorf("no account information received")
+	}
+	if res[0].Error != nil {
+		return fmt.Errorf("invalid response received: %v", res[0].Error.Description)
+	}
+	if res[0].Data == nil {
+		return fmt.Errorf("no account information received")
+	}
+
+	count := len(res[0].Data.Accounts)
+
+	check.Pd(&monitor.PerfDataItem{Name: "accounts", Value: float64(count), Warn: float64(c.srvMaxAccounts), Help: "Accounts loaded on the server"})
+
+	if c.srvExpectAccounts > 0 {
+		if count != c.srvExpectAccounts {
+			check.Criticalf("%d accounts, expected %d", count, c.srvExpectAccounts)
+		} else {
+			check.Okf("%d accounts", count)
+		}
+	}
+
+	if c.srvMaxAccounts > 0 && count > c.srvMaxAccounts {
+		check.Warnf("%d accounts exceeds maximum %d", count, c.srvMaxAccounts)
+	}
+
+	return nil
+}
+
+// discoverServers retrieves Varz from every server that responds within the timeout, sorted by name
+func (c *SrvCheckCmd) discoverServers(nc *nats.Conn) ([]*server.Varz, error) {
+	reqFn := func(req any, subj string, waitFor int, nc *nats.Conn) ([][]byte, error) {
+		return serverdata.DoReq(ctx, req, subj, waitFor, nc, c.requestTimeout(), traceLogger())
+	}
+
+	ds, err := serverdata.NewLive(nc, reqFn, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer ds.Close()
+
+	res, err := ds.Varz(server.VarzEventOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []*server.Varz
+	for _, r := range res {
+		if r.Error != nil || r.Data == nil {
+			continue
+		}
+
+		servers = append(servers, r.Data)
+	}
+
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no servers discovered, ensure the system account is used")
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+
+	return servers, nil
+}
+
+// checkEachServer discovers all servers and calls cb for each, the combined result names failing servers
+func (c *SrvCheckCmd) checkEachServer(nc *nats.Conn, check *monitor.Result, cb func(vz *server.Varz, res *monitor.Result) error) error {
+	servers, err := c.discoverServers(nc)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(servers))
+	for i, vz := range servers {
+		names[i] = vz.Name
+	}
+
+	i := 0
+	checkEach(check, "servers", names, func(_ string, res *monitor.Result) error {
+		vz := servers[i]
+		i++
+
+		return cb(vz, res)
+	})
+
+	return nil
+}
+
+// serverClientURL determines the client URL for a server, preferring advertised URLs
+func serverClientURL(vz *server.Varz) string {
+	if len(vz.ClientConnectURLs) > 0 {
+		return vz.ClientConnectURLs[0]
+	}
+
+	return fmt.Sprintf("nats://%s", net.JoinHostPort(vz.Host, strconv.Itoa(vz.Port)))
+}
+
+// slowConsumerState is the state persisted between runs of the slow consumer check to detect new slow consumers
+type slowConsumerState struct {
+	ServerID      string `json:"server_id"`
+	SlowConsumers int64  `json:"slow_consumers"`
+}
+
+func (c *SrvCheckCmd) checkSlowConsumers(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.srvName, Check: "slow_consumers", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		vz, err := c.fetchVarz(nc, c.srvName)
+		if err != nil {
+			return err
+		}
+
+		if vz.SlowConsumersStats != nil {
+			check.Pd(
+				&monitor.PerfDataItem{Name: "slow_consumers_clients", Value: float64(vz.SlowConsumersStats.Clients), Help: "Slow consumer client connections since server start"},
+				&monitor.PerfDataItem{Name: "slow_consumers_routes", Value: float64(vz.SlowConsumersStats.Routes), Help: "Slow consumer route connections since server start"},
+				&monitor.PerfDataItem{Name: "slow_consumers_gateways", Value: float64(vz.SlowConsumersStats.Gateways), Help: "Slow consumer gateway connections since server start"},
+				&monitor.PerfDataItem{Name: "slow_consumers_leafs", Value: float64(vz.SlowConsumersStats.Leafs), Help: "Slow consumer leafnode connections since server start"},
+			)
+		}
+
+		count, err := c.slowConsumersDelta(vz)
+		if err != nil {
+			return err
+		}
+
+		check.Pd(&monitor.PerfDataItem{Name: "slow_consumers", Value: float64(count), Warn: float64(c.slowWarn), Crit: float64(c.slowCrit), Help: "Slow consumers since server start or since the previous check when using a state file"})
+
+		if c.slowWarn == 0 && c.slowCrit == 0 {
+			check.Okf("%d slow consumers", count)
+			return nil
+		}
+
+		checkThreshold(check, "Slow Consumers", float64(c.slowCrit), float64(c.slowWarn), float64(count), false)
+		if len(check.Criticals) == 0 && len(check.Warnings) == 0 {
+			return nil
+		}
+
+		closed, err := c.closedSlowConsumers(nc)
+		if err != nil {
+			return err
+		}
+
+		if len(closed) > 0 {
+			switch {
+			case len(check.Criticals) > 0:
+				check.Criticalf("recently closed slow consumers: %s", strings.Join(closed, ", "))
+			case len(check.Warnings) > 0:
+				check.Warnf("recently closed slow consumers: %s", strings.Join(closed, ", "))
+			}
+		}
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// slowConsumersDelta returns the slow consumer count, when using a state file the count is relative to the previous run
+func (c *SrvCheckCmd) slowConsumersDelta(vz *server.Varz) (int64, error) {
+	if c.slowStateFile == "" {
+		return vz.SlowConsumers, nil
+	}
+
+	var state slowConsumerState
+	sb, err := os.ReadFile(c.slowStateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return 0, err
+	default:
+		err = json.Unmarshal(sb, &state)
+		if err != nil {
+			return 0, fmt.Errorf("invalid slow consumer state file %s: %v", c.slowStateFile, err)
+		}
+	}
+
+	delta := vz.SlowConsumers
+	switch {
+	case state.ServerID == "":
+		// first run has nothing to compare against
+		delta = 0
+	case state.ServerID == vz.ID && vz.SlowConsumers >= state.SlowConsumers:
+		delta = vz.SlowConsumers - state.SlowConsumers
+	}
+
+	sb, err = json.Marshal(slowConsumerState{ServerID: vz.ID, SlowConsumers: vz.SlowConsumers})
+	if err != nil {
+		return 0, err
+	}
+
+	err = writeFileAtomic(c.slowStateFile, sb)
+	if err != nil {
+		return 0, err
+	}
+
+	return delta, nil
+}
+
+// closedSlowConsumers finds connections in the closed connection buffer that were closed for being slow consumers
+func (c *SrvCheckCmd) closedSlowConsumers(nc *nats.Conn) ([]string, error) {
+	ds, err := c.checkDataSource(nc)
+	if err != nil {
+		return nil, err
+	}
+	defer ds.Close()
+
+	res, err := ds.Connz(server.ConnzEventOptions{ConnzOptions: server.ConnzOptions{State: server.ConnClosed}, EventFilterOptions: server.EventFilterOptions{Name: c.srvName, ExactMatch: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 || res[0].Data == nil {
+		return nil, nil
+	}
+
+	var found []string
+	for _, conn := range res[0].Data.Conns {
+		if !strings.HasPrefix(conn.Reason, "Slow Consumer") {
+			continue
+		}
+
+		name := conn.Name
+		if name == "" {
+			name = "unnamed"
+		}
+
+		found = append(found, fmt.Sprintf("%s (cid %d, account %s)", name, conn.Cid, conn.Account))
+	}
+
+	return found, nil
+}
+
+func (c *SrvCheckCmd) checkAccountConnections(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.acctName, Check: "account_connections", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		count, servers, err := c.accountConnections(nc)
+		if err != nil {
+			return err
+		}
+
+		if servers == 0 {
+			check.Critical("no servers responded, ensure the system account is used")
+			return nil
+		}
+
+		check.Pd(&monitor.PerfDataItem{Name: "connections", Value: float64(count), Warn: float64(c.acctConnWarn), Crit: float64(c.acctConnCrit), Help: "Client connections in the account"})
+
+		if c.acctConnMin > 0 && count < c.acctConnMin {
+			check.Criticalf("%d connections, expected at least %d", count, c.acctConnMin)
+		}
+
+		checkThreshold(check, "Connections", float64(c.acctConnCrit), float64(c.acctConnWarn), float64(count), true)
+
+		check.OkIfNoWarningsOrCriticalsf("%d connections on %d servers", count, servers)
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// accountConnections counts the open client connections in the account across all servers, or only the server named by --name
+func (c *SrvCheckCmd) accountConnections(nc *nats.Conn) (int, int, error) {
+	reqFn := func(req any, subj string, waitFor int, nc *nats.Conn) ([][]byte, error) {
+		return serverdata.DoReq(ctx, req, subj, waitFor, nc, c.requestTimeout(), traceLogger())
+	}
+
+	waitFor := 0
+	if c.srvName != "" {
+		waitFor = 1
+	}
+
+	ds, err := serverdata.NewLive(nc, reqFn, waitFor)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer ds.Close()
+
+	// limit 1 as only the totals are needed
+	res, err := ds.Connz(server.ConnzEventOptions{
+		ConnzOptions:       server.ConnzOptions{Account: c.acctName, State: server.ConnOpen, Limit: 1},
+		EventFilterOptions: server.EventFilterOptions{Name: c.srvName, ExactMatch: c.srvName != ""},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var count, servers int
+	for _, r := range res {
+		if r.Error != nil {
+			return 0, 0, fmt.Errorf("invalid response received: %v", r.Error.Description)
+		}
+		if r.Data == nil {
+			continue
+		}
+
+		servers++
+		count += r.Data.Total
+	}
+
+	return count, servers, nil
+}
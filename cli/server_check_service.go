@@ -0,0 +1,152 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/jsm.go/serverdata"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+)
+
+// serviceEndpointStats are the statistics of an endpoint combined across all instances
+type serviceEndpointStats struct {
+	requests       int
+	errors         int
+	processingTime time.Duration
+}
+
+var perfDataNameRe = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func (c *SrvCheckCmd) checkService(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.svcName, Check: "service", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		stats, err := c.serviceStats(nc)
+		if err != nil {
+			return err
+		}
+
+		check.Pd(&monitor.PerfDataItem{Name: "instances", Value: float64(len(stats)), Crit: float64(c.svcInstances), Help: "The number of service instances that responded"})
+		if len(stats) < c.svcInstances {
+			check.Criticalf("%d instances, expected %d", len(stats), c.svcInstances)
+		} else {
+			check.Okf("%d instances", len(stats))
+		}
+
+		endpoints := map[string]*serviceEndpointStats{}
+		for _, s := range stats {
+			for _, e := range s.Endpoints {
+				es, ok := endpoints[e.Name]
+				if !ok {
+					es = &serviceEndpointStats{}
+					endpoints[e.Name] = es
+				}
+
+				es.requests += e.NumRequests
+				es.errors += e.NumErrors
+				es.processingTime += e.ProcessingTime
+			}
+		}
+
+		names := make([]string, 0, len(endpoints))
+		for name := range endpoints {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			es := endpoints[name]
+			pdName := perfDataNameRe.ReplaceAllString(name, "_")
+
+			var rate float64
+			var avg time.Duration
+			if es.requests > 0 {
+				rate = float64(es.errors) / float64(es.requests) * 100
+				avg = es.processingTime / time.Duration(es.requests)
+			}
+
+			check.Pd(
+				&monitor.PerfDataItem{Name: pdName + "_requests", Value: float64(es.requests), Help: fmt.Sprintf("Requests handled by the %s endpoint", name)},
+				&monitor.PerfDataItem{Name: pdName + "_errors", Value: float64(es.errors), Help: fmt.Sprintf("Errors returned by the %s endpoint", name)},
+				&monitor.PerfDataItem{Name: pdName + "_error_rate", Value: rate, Warn: c.svcErrorRateWarn, Crit: c.svcErrorRateCrit, Unit: "%", Help: fmt.Sprintf("Percentage of requests to the %s endpoint that failed", name)},
+				&monitor.PerfDataItem{Name: pdName + "_average_time", Value: avg.Seconds(), Warn: c.svcAvgTimeWarn.Seconds(), Crit: c.svcAvgTimeCrit.Seconds(), Unit: "s", Help: fmt.Sprintf("Average processing time of the %s endpoint", name)},
+			)
+
+			switch {
+			case c.svcErrorRateCrit > 0 && rate >= c.svcErrorRateCrit:
+				check.Criticalf("%s error rate %.2f%%", name, rate)
+			case c.svcErrorRateWarn > 0 && rate >= c.svcErrorRateWarn:
+				check.Warnf("%s error rate %.2f%%", name, rate)
+			}
+
+			switch {
+			case c.svcAvgTimeCrit > 0 && avg >= c.svcAvgTimeCrit:
+				check.Criticalf("%s average processing time %v", name, f(avg))
+			case c.svcAvgTimeWarn > 0 && avg >= c.svcAvgTimeWarn:
+				check.Warnf("%s average processing time %v", name, f(avg))
+			}
+		}
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// serviceStats gathers the statistics of all instances of the service
+func (c *SrvCheckCmd) serviceStats(nc *nats.Conn) ([]*micro.Stats, error) {
+	subj := fmt.Sprintf("%s.%s.%s", micro.APIPrefix, micro.StatsVerb, c.svcName)
+
+	resp, err := serverdata.DoReq(ctx, nil, subj, 0, nc, c.requestTimeout(), traceLogger())
+	if errors.Is(err, nats.ErrNoResponders) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []*micro.Stats
+	for _, r := range resp {
+		s := &micro.Stats{}
+		err = json.Unmarshal(r, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid statistics received: %w", err)
+		}
+
+		if s.Type != micro.StatsResponseType {
+			return nil, fmt.Errorf("invalid response type %s", s.Type)
+		}
+
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/jsm.go/monitor"
+)
+
+// streamRateState is the state persisted per stream between runs of the stream check to calculate message rates
+type streamRateState struct {
+	LastSeq uint64    `json:"last_seq"`
+	Time    time.Time `json:"time"`
+}
+
+// loadStreamRates reads the message rate state file, a missing file is not an error
+func (c *SrvCheckCmd) loadStreamRates() (map[string]streamRateState, error) {
+	state := map[string]streamRateState{}
+
+	sb, err := os.ReadFile(c.streamRateStateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return state, nil
+	case err != nil:
+		return nil, err
+	}
+
+	err = json.Unmarshal(sb, &state)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stream state file %s: %v", c.streamRateStateFile, err)
+	}
+
+	return state, nil
+}
+
+// saveStreamRates replaces the message rate state file
+func (c *SrvCheckCmd) saveStreamRates(state map[string]streamRateState) error {
+	sb, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	// concurrent or interrupted checks must not leave a truncated state file behind
+	return writeFileAtomic(c.streamRateStateFile, sb)
+}
+
+// streamRateCheck creates a stream health check that calculates the ingest rate since the previous run and records the current state
+func (c *SrvCheckCmd) streamRateCheck(state map[string]streamRateState) monitor.StreamHealthCheckF {
+	return func(stream *jsm.Stream, check *monitor.Result, _ monitor.CheckStreamHealthOptions, _ api.Logger) {
+		nfo, err := stream.LatestInformation()
+		if check.CriticalIfErrf(err, "could not load stream information: %v", err) {
+			return
+		}
+
+		now := time.Now()
+		prev, ok := state[stream.Name()]
+		state[stream.Name()] = streamRateState{LastSeq: nfo.State.LastSeq, Time: now}
+
+		// first run or a recreated stream has nothing to compare against
+		if !ok || nfo.State.LastSeq < prev.LastSeq || !now.After(prev.Time) {
+			check.Ok("message rate not yet known")
+			return
+		}
+
+		rate := float64(nfo.State.LastSeq-prev.LastSeq) / now.Sub(prev.Time).Seconds()
+		check.Pd(&monitor.PerfDataItem{Name: "msgs_rate", Value: rate, Warn: c.streamRateWarn, Crit: c.streamRateCrit, Unit: "msgs/s", Help: "Messages received per second since the previous check"})
+		checkThreshold(check, "Message Rate", c.streamRateCrit, c.streamRateWarn, rate, true)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/natscli/internal/exporter"
+)
+
+func (c *SrvCheckCmd) checkSuite(_ *fisk.ParseContext) error {
+	exp, err := exporter.NewExporter(opts().PrometheusNamespace, c.suiteConfigFile)
+	if err != nil {
+		return err
+	}
+
+	check := &monitor.Result{Name: "Check Suite", Check: "suite", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+
+	if c.suiteIndividual {
+		var results []*monitor.Result
+		err = c.runCheckWithTimeout(check, func(ctx context.Context, _ *monitor.Result) error {
+			results = exp.RunChecks()
+			return nil
+		})
+		if err != nil {
+			// the individual results are not known so the suite is reported as a whole
+			check.Criticalf("Check failed: %v", err)
+			results = []*monitor.Result{check}
+		}
+
+		return c.renderSuiteResults(results)
+	}
+
+	defer c.finish(check)
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		results := exp.RunChecks()
+		if len(results) == 0 {
+			check.Critical("no checks configured")
+			return nil
+		}
+
+		names := make([]string, len(results))
+		for i, res := range results {
+			names[i] = res.Name
+		}
+
+		i := 0
+		checkEach(check, "checks", names, func(_ string, res *monitor.Result) error {
+			res.Criticals = results[i].Criticals
+			res.Warnings = results[i].Warnings
+			i++
+
+			return nil
+		})
+
+		return nil
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+// renderSuiteResults renders every result and exits with the most severe status, when watching every result is published
+func (c *SrvCheckCmd) renderSuiteResults(results []*monitor.Result) error {
+	var out []string
+	worst := 0
+
+	for _, res := range results {
+		res.RenderFormat = checkRenderFormat
+		res.NameSpace = opts().PrometheusNamespace
+		res.Trace = opts().Trace
+		c.filterPerfData(res)
+
+		if c.watchInterval > 0 {
+			c.finish(res)
+			continue
+		}
+
+		out = append(out, renderCheck(res))
+		c.publishStatus(res)
+		worst = max(worst, checkExitCode(res))
+	}
+
+	if c.watchInterval > 0 {
+		return nil
+	}
+
+	if checkRenderOutFile != "" {
+		err := writeCheckOutFile(checkRenderOutFile, strings.Join(out, "\n"))
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(strings.Join(out, "\n"))
+	}
+
+	os.Exit(worst)
+
+	return nil
+}
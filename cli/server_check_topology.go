@@ -0,0 +1,245 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/monitor"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// leafnodeRateState is the state persisted between runs of the leafnode check to calculate message rates
+type leafnodeRateState struct {
+	ServerID string    `json:"server_id"`
+	InMsgs   int64     `json:"in_msgs"`
+	OutMsgs  int64     `json:"out_msgs"`
+	Time     time.Time `json:"time"`
+}
+
+func (c *SrvCheckCmd) checkLeafnodes(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.srvName, Check: "leafnodes", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		return c.checkLeafnodesWithConnection(nc, check)
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+func (c *SrvCheckCmd) checkLeafnodesWithConnection(nc *nats.Conn, check *monitor.Result) error {
+	ds, err := c.checkDataSource(nc)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	res, err := ds.Leafz(server.LeafzEventOptions{EventFilterOptions: server.EventFilterOptions{Name: c.srvName, ExactMatch: true}})
+	if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		return fmt.Errorf("no leafnode information received")
+	}
+	if res[0].Error != nil {
+		return fmt.Errorf("invalid response received: %v", res[0].Error.Description)
+	}
+	if res[0].Data == nil {
+		return fmt.Errorf("no leafnode information received")
+	}
+
+	var maxRTT time.Duration
+	var inMsgs, outMsgs int64
+
+	for _, leaf := range res[0].Data.Leafs {
+		inMsgs += leaf.InMsgs
+		outMsgs += leaf.OutMsgs
+
+		if leaf.RTT == "" {
+			continue
+		}
+
+		rtt, err := time.ParseDuration(leaf.RTT)
+		if err != nil {
+			return fmt.Errorf("invalid rtt %q for leafnode %s: %v", leaf.RTT, leaf.Name, err)
+		}
+		maxRTT = max(maxRTT, rtt)
+	}
+
+	count := len(res[0].Data.Leafs)
+
+	check.Pd(
+		&monitor.PerfDataItem{Name: "leafnodes", Value: float64(count), Crit: float64(c.leafExpect), Help: "Connected leafnodes"},
+		&monitor.PerfDataItem{Name: "rtt_max", Value: maxRTT.Seconds(), Unit: "s", Help: "The highest round trip time of all leafnodes"},
+		&monitor.PerfDataItem{Name: "in_msgs", Value: float64(inMsgs), Help: "Messages received from all leafnodes since they connected"},
+		&monitor.PerfDataItem{Name: "out_msgs", Value: float64(outMsgs), Help: "Messages sent to all leafnodes since they connected"},
+	)
+
+	if c.leafStateFile != "" {
+		err = c.leafnodeRates(check, leafnodeRateState{ServerID: res[0].Data.ID, InMsgs: inMsgs, OutMsgs: outMsgs, Time: res[0].Data.Now})
+		if err != nil {
+			return err
+		}
+	}
+
+	if count < c.leafExpect {
+		check.Criticalf("%d leafnodes, expected %d", count, c.leafExpect)
+	} else {
+		check.Okf("%d leafnodes", count)
+	}
+
+	return nil
+}
+
+// leafnodeRates reports the message rates since the previous run and records the current counts in the state file
+func (c *SrvCheckCmd) leafnodeRates(check *monitor.Result, current leafnodeRateState) error {
+	var prev leafnodeRateState
+	sb, err := os.ReadFile(c.leafStateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		err = json.Unmarshal(sb, &prev)
+		if err != nil {
+			return fmt.Errorf("invalid leafnode state file %s: %v", c.leafStateFile, err)
+		}
+	}
+
+	sb, err = json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	err = writeFileAtomic(c.leafStateFile, sb)
+	if err != nil {
+		return err
+	}
+
+	// first run, a restarted server or reconnected leafnodes have nothing to compare against
+	if prev.ServerID != current.ServerID || current.InMsgs < prev.InMsgs || current.OutMsgs < prev.OutMsgs || !current.Time.After(prev.Time) {
+		check.Ok("message rates not yet known")
+		return nil
+	}
+
+	secs := current.Time.Sub(prev.Time).Seconds()
+	check.Pd(
+		&monitor.PerfDataItem{Name: "in_msgs_rate", Value: float64(current.InMsgs-prev.InMsgs) / secs, Unit: "msgs/s", Help: "Messages received from all leafnodes per second since the previous check"},
+		&monitor.PerfDataItem{Name: "out_msgs_rate", Value: float64(current.OutMsgs-prev.OutMsgs) / secs, Unit: "msgs/s", Help: "Messages sent to all leafnodes per second since the previous check"},
+	)
+
+	return nil
+}
+
+func (c *SrvCheckCmd) checkGateways(_ *fisk.ParseContext) error {
+	check := &monitor.Result{Name: c.srvName, Check: "gateway", OutFile: checkRenderOutFile, NameSpace: opts().PrometheusNamespace, RenderFormat: checkRenderFormat, Trace: opts().Trace}
+	defer c.finish(check)
+
+	nc, err := c.checkConn()
+	if check.CriticalIfErrf(err, "connection failed: %v", err) {
+		return nil
+	}
+
+	err = c.runCheck(check, func(ctx context.Context, check *monitor.Result) error {
+		return c.checkGatewaysWithConnection(nc, check)
+	})
+	check.CriticalIfErrf(err, "Check failed: %v", err)
+
+	return nil
+}
+
+func (c *SrvCheckCmd) checkGatewaysWithConnection(nc *nats.Conn, check *monitor.Result) error {
+	ds, err := c.checkDataSource(nc)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	res, err := ds.Gatewayz(server.GatewayzEventOptions{EventFilterOptions: server.EventFilterOptions{Name: c.srvName, ExactMatch: true}})
+	if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		return fmt.Errorf("no gateway information received")
+	}
+	if res[0].Error != nil {
+		return fmt.Errorf("invalid response received: %v", res[0].Error.Description)
+	}
+	if res[0].Data == nil {
+		return fmt.Errorf("no gateway information received")
+	}
+
+	gwz := res[0].Data
+	if gwz.Name == "" {
+		check.Critical("gateways are not enabled")
+		return nil
+	}
+
+	expected := c.gwExpect
+	if len(expected) == 0 {
+		for name, gw := range gwz.OutboundGateways {
+			if gw.IsConfigured {
+				expected = append(expected, name)
+			}
+		}
+	}
+	sort.Strings(expected)
+
+	var connected int
+
+	for _, name := range expected {
+		gw, ok := gwz.OutboundGateways[name]
+		if !ok || gw.Connection == nil {
+			check.Criticalf("gateway %s not connected", name)
+			continue
+		}
+
+		connected++
+		check.Pd(&monitor.PerfDataItem{Name: fmt.Sprintf("pending_%s", name), Value: float64(gw.Connection.Pending), Unit: "B", Help: fmt.Sprintf("Bytes pending to be sent to gateway %s", name)})
+
+		uptime := gwz.Now.Sub(gw.Connection.Start)
+		if c.gwMinUptime > 0 && uptime < c.gwMinUptime {
+			check.Warnf("gateway %s connected %v ago", name, uptime.Round(time.Second))
+		}
+	}
+
+	for name, gw := range gwz.OutboundGateways {
+		if gw.Connection != nil && !slices.Contains(expected, name) {
+			check.Warnf("unexpected gateway %s connected", name)
+		}
+	}
+
+	check.Pd(&monitor.PerfDataItem{Name: "gateways", Value: float64(connected), Crit: float64(len(expected)), Help: "Connected gateways"})
+	check.OkIfNoWarningsOrCriticalsf("%d gateways connected", connected)
+
+	return nil
+}
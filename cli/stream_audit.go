@@ -0,0 +1,222 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// streamAuditBaseline is the policy streams are audited against
+type streamAuditBaseline struct {
+	// Streams limits the baseline to streams matching any of these wildcards
+	Streams []string `yaml:"streams"`
+	// Exclude skips streams matching any of these wildcards
+	Exclude            []string              `yaml:"exclude"`
+	MinReplicas        int                   `yaml:"min_replicas"`
+	MinMaxAge          string                `yaml:"min_max_age"`
+	MaxMaxAge          string                `yaml:"max_max_age"`
+	ForbiddenRetention []api.RetentionPolicy `yaml:"forbidden_retention"`
+	ForbiddenStorage   []api.StorageType     `yaml:"forbidden_storage"`
+	RequiredTags       []string              `yaml:"required_tags"`
+
+	minMaxAge time.Duration
+	maxMaxAge time.Duration
+}
+
+type streamAuditViolation struct {
+	Stream    string `json:"stream"`
+	Rule      string `json:"rule"`
+	Violation string `json:"violation"`
+}
+
+func loadStreamAuditBaseline(file string) (*streamAuditBaseline, error) {
+	body, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := &streamAuditBaseline{}
+	err = yaml.Unmarshal(body, baseline)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", file, err)
+	}
+
+	if baseline.MinMaxAge != "" {
+		baseline.minMaxAge, err = fisk.ParseDuration(baseline.MinMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_max_age: %w", err)
+		}
+	}
+
+	if baseline.MaxMaxAge != "" {
+		baseline.maxMaxAge, err = fisk.ParseDuration(baseline.MaxMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_max_age: %w", err)
+		}
+	}
+
+	for _, pattern := range append(baseline.Streams, baseline.Exclude...) {
+		_, err = filepath.Match(pattern, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid stream pattern %q: %w", pattern, err)
+		}
+	}
+
+	return baseline, nil
+}
+
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *streamAuditBaseline) applies(stream string) bool {
+	if len(b.Streams) > 0 && !matchesAnyPattern(stream, b.Streams) {
+		return false
+	}
+
+	return !matchesAnyPattern(stream, b.Exclude)
+}
+
+// audit checks cfg against the baseline and returns all violations found
+func (b *streamAuditBaseline) audit(cfg api.StreamConfig) []streamAuditViolation {
+	var violations []streamAuditViolation
+	violation := func(rule string, format string, a ...any) {
+		violations = append(violations, streamAuditViolation{Stream: cfg.Name, Rule: rule, Violation: fmt.Sprintf(format, a...)})
+	}
+
+	if b.MinReplicas > 0 && cfg.Replicas < b.MinReplicas {
+		violation("min_replicas", "has %d replicas, %d required", cfg.Replicas, b.MinReplicas)
+	}
+
+	if b.minMaxAge > 0 && cfg.MaxAge > 0 && cfg.MaxAge < b.minMaxAge {
+		violation("min_max_age", "maximum age %s is below %s", f(cfg.MaxAge), f(b.minMaxAge))
+	}
+
+	if b.maxMaxAge > 0 {
+		switch {
+		case cfg.MaxAge == 0:
+			violation("max_max_age", "messages are kept forever, at most %s allowed", f(b.maxMaxAge))
+		case cfg.MaxAge > b.maxMaxAge:
+			violation("max_max_age", "maximum age %s exceeds %s", f(cfg.MaxAge), f(b.maxMaxAge))
+		}
+	}
+
+	if slices.Contains(b.ForbiddenRetention, cfg.Retention) {
+		violation("forbidden_retention", "uses forbidden %s retention", strings.ToLower(cfg.Retention.String()))
+	}
+
+	if slices.Contains(b.ForbiddenStorage, cfg.Storage) {
+		violation("forbidden_storage", "uses forbidden %s storage", strings.ToLower(cfg.Storage.String()))
+	}
+
+	var tags []string
+	if cfg.Placement != nil {
+		tags = cfg.Placement.Tags
+	}
+	for _, tag := range b.RequiredTags {
+		if !slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			violation("required_tags", "is not placed using tag %s", tag)
+		}
+	}
+
+	return violations
+}
+
+func (c *streamCmd) auditAction(_ *fisk.ParseContext) error {
+	baseline, err := loadStreamAuditBaseline(c.auditBaseline)
+	if err != nil {
+		return err
+	}
+
+	if c.stream != "" {
+		_, err = filepath.Match(c.stream, "")
+		if err != nil {
+			return fmt.Errorf("invalid stream pattern %q: %w", c.stream, err)
+		}
+	}
+
+	_, mgr, err := prepareHelper("", natsOpts()...)
+	if err != nil {
+		return fmt.Errorf("setup failed: %v", err)
+	}
+
+	var audited int
+	violations := []streamAuditViolation{}
+
+	_, _, err = mgr.EachStream(nil, func(stream *jsm.Stream) {
+		if c.stream != "" {
+			if ok, _ := filepath.Match(c.stream, stream.Name()); !ok {
+				return
+			}
+		}
+
+		if !baseline.applies(stream.Name()) {
+			return
+		}
+
+		audited++
+		violations = append(violations, baseline.audit(stream.Configuration())...)
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortStableFunc(violations, func(a, b streamAuditViolation) int {
+		return strings.Compare(a.Stream, b.Stream)
+	})
+
+	failed := make(map[string]struct{})
+	for _, v := range violations {
+		failed[v.Stream] = struct{}{}
+	}
+
+	if c.json {
+		err = iu.PrintJSON(violations)
+		if err != nil {
+			return err
+		}
+	} else if len(violations) == 0 {
+		fmt.Printf("All %d streams comply with baseline %s\n", audited, c.auditBaseline)
+	} else {
+		table := iu.NewTableWriterf(opts(), "Baseline violations in %d of %d streams", len(failed), audited)
+		table.AddHeaders("Stream", "Rule", "Violation")
+		for _, v := range violations {
+			table.AddRow(v.Stream, v.Rule, v.Violation)
+		}
+		fmt.Println(table.Render())
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%d streams violate baseline %s", len(failed), c.auditBaseline)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+const scheduledBackupTimeFormat = "20060102T150405.000Z"
+
+// streamBackupEvent is published after every scheduled backup of a stream
+type streamBackupEvent struct {
+	Stream    string        `json:"stream"`
+	Directory string        `json:"directory"`
+	Time      time.Time     `json:"time"`
+	Duration  time.Duration `json:"duration"`
+	Removed   []string      `json:"removed,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+func (c *streamCmd) scheduledBackups(chunkSize, wndSize int) error {
+	schedule, err := iu.ParseSchedule(c.backupSchedule)
+	if err != nil {
+		return err
+	}
+
+	if c.backupKeep < 0 || c.backupRuns < 0 {
+		return fmt.Errorf("--keep and --runs can not be negative")
+	}
+
+	_, err = filepath.Match(c.stream, "")
+	if err != nil {
+		return fmt.Errorf("invalid stream pattern %q: %w", c.stream, err)
+	}
+
+	err = os.MkdirAll(c.backupDirectory, 0700)
+	if err != nil {
+		return err
+	}
+
+	sctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	for runs := 0; c.backupRuns == 0 || runs < c.backupRuns; runs++ {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("schedule %q will never run", c.backupSchedule)
+		}
+
+		fmt.Printf("Next backup of streams matching %q at %s\n", c.stream, next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-sctx.Done():
+			timer.Stop()
+			return nil
+		}
+
+		err = c.scheduledBackupRun(chunkSize, wndSize)
+		if err != nil {
+			fmt.Printf("Scheduled backup failed: %v\n", err)
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func (c *streamCmd) scheduledBackupRun(chunkSize, wndSize int) error {
+	names, err := c.mgr.StreamNames(nil)
+	if err != nil {
+		return err
+	}
+
+	var matched []string
+	for _, name := range names {
+		if ok, _ := filepath.Match(c.stream, name); ok {
+			matched = append(matched, name)
+		}
+	}
+
+	if len(matched) == 0 {
+		return fmt.Errorf("no streams match %q", c.stream)
+	}
+
+	for _, name := range matched {
+		event := streamBackupEvent{
+			Stream:    name,
+			Time:      time.Now().UTC(),
+			Directory: filepath.Join(c.backupDirectory, name, time.Now().UTC().Format(scheduledBackupTimeFormat)),
+		}
+
+		err = c.scheduledBackupStream(name, event.Directory, chunkSize, wndSize)
+		event.Duration = time.Since(event.Time)
+		if err != nil {
+			event.Error = err.Error()
+			fmt.Printf("Backup of Stream %s failed: %v\n", name, err)
+		} else if c.backupKeep > 0 {
+			event.Removed, err = rotateScheduledBackups(filepath.Join(c.backupDirectory, name), c.backupKeep)
+			if err != nil {
+				fmt.Printf("Could not remove old backups of Stream %s: %v\n", name, err)
+			}
+			for _, dir := range event.Removed {
+				fmt.Printf("Removed old backup %s\n", dir)
+			}
+		}
+
+		if c.backupEventSubject != "" {
+			ej, err := json.Marshal(event)
+			if err == nil {
+				err = c.nc.Publish(c.backupEventSubject, ej)
+			}
+			if err != nil {
+				fmt.Printf("Could not publish backup event: %v\n", err)
+			}
+		}
+	}
+
+	return c.nc.Flush()
+}
+
+func (c *streamCmd) scheduledBackupStream(name string, dir string, chunkSize, wndSize int) error {
+	stream, err := c.mgr.LoadStream(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(dir), 0700)
+	if err != nil {
+		return err
+	}
+
+	return backupStream(stream, false, c.snapShotConsumers, c.healthCheck, dir, chunkSize, wndSize)
+}
+
+// rotateScheduledBackups removes all but the newest keep backups made by the scheduler in dir
+func rotateScheduledBackups(dir string, keep int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		// only touch directories the scheduler created and that holds a complete backup
+		_, err := time.Parse(scheduledBackupTimeFormat, entry.Name())
+		if err != nil || !iu.FileExists(filepath.Join(dir, entry.Name(), "backup.json")) {
+			continue
+		}
+
+		backups = append(backups, entry.Name())
+	}
+
+	if len(backups) <= keep {
+		return nil, nil
+	}
+
+	slices.Sort(backups)
+
+	var removed []string
+	for _, name := range backups[:len(backups)-keep] {
+		path := filepath.Join(dir, name)
+		err = os.RemoveAll(path)
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// streamColumn is a column that can be shown and sorted on in stream ls and stream report
+type streamColumn struct {
+	header string
+	// value is the raw value used for sorting and JSON output
+	value func(s *streamStat) any
+	// render formats the value for table output
+	render func(s *streamStat, raw bool) any
+	// consumers indicates the value requires consumer details to be loaded
+	consumers bool
+}
+
+var streamColumns = map[string]streamColumn{
+	"name": {
+		header: "Name",
+		value:  func(s *streamStat) any { return s.Name },
+		render: func(s *streamStat, _ bool) any { return s.Name },
+	},
+	"description": {
+		header: "Description",
+		value:  func(s *streamStat) any { return s.Description },
+		render: func(s *streamStat, _ bool) any { return s.Description },
+	},
+	"created": {
+		header: "Created",
+		value:  func(s *streamStat) any { return s.Created },
+		render: func(s *streamStat, raw bool) any {
+			if raw {
+				return s.Created.Format(time.RFC3339)
+			}
+			return f(s.Created.Local())
+		},
+	},
+	"storage": {
+		header: "Storage",
+		value:  func(s *streamStat) any { return s.Storage },
+		render: func(s *streamStat, _ bool) any { return s.Storage },
+	},
+	"placement": {
+		header: "Placement",
+		value:  func(s *streamStat) any { return renderStreamPlacement(s.Placement) },
+		render: func(s *streamStat, _ bool) any { return renderStreamPlacement(s.Placement) },
+	},
+	"consumers": {
+		header: "Consumers",
+		value:  func(s *streamStat) any { return s.Consumers },
+		render: func(s *streamStat, raw bool) any { return fRaw(s.Consumers, raw) },
+	},
+	"messages": {
+		header: "Messages",
+		value:  func(s *streamStat) any { return s.Msgs },
+		render: func(s *streamStat, raw bool) any { return fRaw(s.Msgs, raw) },
+	},
+	"bytes": {
+		header: "Bytes",
+		value:  func(s *streamStat) any { return s.Bytes },
+		render: func(s *streamStat, raw bool) any {
+			if raw {
+				return s.Bytes
+			}
+			return humanize.IBytes(s.Bytes)
+		},
+	},
+	"lost": {
+		header: "Lost",
+		value:  func(s *streamStat) any { return s.LostMsgs },
+		render: func(s *streamStat, raw bool) any {
+			switch {
+			case s.LostMsgs == 0:
+				return "0"
+			case raw:
+				return fmt.Sprintf("%d (%d)", s.LostMsgs, s.LostBytes)
+			default:
+				return fmt.Sprintf("%s (%s)", f(s.LostMsgs), humanize.IBytes(s.LostBytes))
+			}
+		},
+	},
+	"deleted": {
+		header: "Deleted",
+		value:  func(s *streamStat) any { return s.Deleted },
+		render: func(s *streamStat, raw bool) any { return fRaw(s.Deleted, raw) },
+	},
+	"api": {
+		header: "API Level",
+		value:  func(s *streamStat) any { return s.APILevel },
+		render: func(s *streamStat, _ bool) any { return s.APILevel },
+	},
+	"replicas": {
+		header: "Replicas",
+		value:  func(s *streamStat) any { return s.Replicas },
+		render: func(s *streamStat, _ bool) any {
+			if s.Cluster == nil {
+				return s.Replicas
+			}
+			return renderCluster(s.Cluster)
+		},
+	},
+	"leader": {
+		header: "Leader",
+		value:  func(s *streamStat) any { return s.Leader },
+		render: func(s *streamStat, _ bool) any { return s.Leader },
+	},
+	"ack_pending": {
+		header:    "Largest Ack Pending",
+		value:     func(s *streamStat) any { return s.LargestAckPending },
+		render:    func(s *streamStat, raw bool) any { return fRaw(s.LargestAckPending, raw) },
+		consumers: true,
+	},
+	"unprocessed": {
+		header:    "Unprocessed",
+		value:     func(s *streamStat) any { return s.Unprocessed },
+		render:    func(s *streamStat, raw bool) any { return fRaw(s.Unprocessed, raw) },
+		consumers: true,
+	},
+	"lagged": {
+		header:    "Most Lagged",
+		value:     func(s *streamStat) any { return s.MostLagged },
+		render:    func(s *streamStat, _ bool) any { return s.MostLagged },
+		consumers: true,
+	},
+	"last": {
+		header: "Last Message",
+		value:  func(s *streamStat) any { return s.LastActivity },
+		render: func(s *streamStat, raw bool) any {
+			if raw {
+				return s.LastActivity.Format(time.RFC3339)
+			}
+			return f(sinceRefOrNow(s.TimeStamp, s.LastActivity))
+		},
+	},
+}
+
+var (
+	streamLsDefaultColumns      = []string{"name", "description", "created", "messages", "bytes", "last"}
+	streamReportDefaultColumns  = []string{"name", "storage", "placement", "consumers", "messages", "bytes", "lost", "deleted", "api", "replicas"}
+	streamReportConsumerColumns = []string{"name", "consumers", "messages", "ack_pending", "unprocessed", "lagged"}
+)
+
+func streamColumnNames() []string {
+	var names []string
+	for k := range streamColumns {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func fRaw(v any, raw bool) any {
+	if raw {
+		return v
+	}
+	return f(v)
+}
+
+func renderStreamPlacement(p *api.Placement) string {
+	if p == nil {
+		return ""
+	}
+
+	placement := ""
+	if p.Cluster != "" {
+		placement = fmt.Sprintf("cluster: %s ", p.Cluster)
+	}
+	if len(p.Tags) > 0 {
+		placement = fmt.Sprintf("%stags: %s", placement, f(p.Tags))
+	}
+
+	return placement
+}
+
+func streamColumnsNeedConsumers(columns []string) bool {
+	for _, col := range columns {
+		if streamColumns[col].consumers {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addConsumerStats aggregates the state of all consumers on stream into s
+func (s *streamStat) addConsumerStats(stream *jsm.Stream) error {
+	_, _, err := stream.EachConsumer(func(consumer *jsm.Consumer) {
+		state, err := consumer.LatestState()
+		if err != nil {
+			return
+		}
+
+		if state.NumAckPending > s.LargestAckPending {
+			s.LargestAckPending = state.NumAckPending
+		}
+
+		s.Unprocessed += state.NumPending
+		if s.MostLagged == "" || state.NumPending > s.mostLaggedPending {
+			s.MostLagged = consumer.Name()
+			s.mostLaggedPending = state.NumPending
+		}
+	})
+
+	return err
+}
+
+func validateStreamColumns(columns []string) error {
+	for _, col := range columns {
+		_, ok := streamColumns[col]
+		if !ok {
+			return fmt.Errorf("unknown column %q, valid columns are: %s", col, strings.Join(streamColumnNames(), ", "))
+		}
+	}
+
+	return nil
+}
+
+func compareStreamColumnValues(a any, b any) int {
+	switch av := a.(type) {
+	case string:
+		return strings.Compare(av, b.(string))
+	case int:
+		return cmp.Compare(av, b.(int))
+	case int64:
+		return cmp.Compare(av, b.(int64))
+	case uint64:
+		return cmp.Compare(av, b.(uint64))
+	case time.Time:
+		return av.Compare(b.(time.Time))
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
+
+// sortStreamStats sorts stats by the named column, ties are broken by stream name
+func sortStreamStats(stats []streamStat, column string, reverse bool) error {
+	col, ok := streamColumns[column]
+	if !ok {
+		return fmt.Errorf("unknown sort column %q, valid columns are: %s", column, strings.Join(streamColumnNames(), ", "))
+	}
+
+	slices.SortStableFunc(stats, func(a, b streamStat) int {
+		res := compareStreamColumnValues(col.value(&a), col.value(&b))
+		if res == 0 {
+			res = strings.Compare(a.Name, b.Name)
+		}
+		if reverse {
+			return -res
+		}
+		return res
+	})
+
+	return nil
+}
+
+func streamStatsColumnsJSON(stats []streamStat, columns []string) []map[string]any {
+	res := make([]map[string]any, len(stats))
+	for i := range stats {
+		res[i] = make(map[string]any)
+		for _, c := range columns {
+			res[i][c] = streamColumns[c].value(&stats[i])
+		}
+	}
+
+	return res
+}
+
+func renderStreamStatsColumns(table *iu.Table, stats []streamStat, columns []string, raw bool) {
+	var headers []any
+	for _, c := range columns {
+		headers = append(headers, streamColumns[c].header)
+	}
+	table.AddHeaders(headers...)
+
+	for i := range stats {
+		var row []any
+		for _, c := range columns {
+			row = append(row, streamColumns[c].render(&stats[i], raw))
+		}
+		table.AddRow(row...)
+	}
+}
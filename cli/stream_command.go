@@ -14,15 +14,21 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -43,8 +49,12 @@ import (
 	"github.com/nats-io/jsm.go"
 	"github.com/nats-io/jsm.go/api"
 	"github.com/nats-io/jsm.go/balancer"
+	"github.com/nats-io/jsm.go/serverdata"
+	"github.com/nats-io/nats-server/v2/server"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/nats-io/natscli/columns"
+	"github.com/synadia-io/orbit.go/jetstreamext"
 	"gopkg.in/yaml.v3"
 
 	"github.com/choria-io/fisk"
@@ -65,6 +75,10 @@ type streamCmd struct {
 	destination            string
 	subjects               []string
 	ack                    bool
+	ackSet                 bool
+	profile                string
+	previewPlacement       bool
+	previewPlacementSet    bool
 	storage                string
 	maxMsgLimit            int64
 	maxMsgPerSubjectLimit  int64
@@ -75,20 +89,49 @@ type streamCmd struct {
 	maxMsgSize             int64
 	maxConsumers           int
 	reportSortConsumers    bool
+	reportConsumerStats    bool
+	skipCompatCheck        bool
 	reportSortMsgs         bool
 	reportSortName         bool
 	reportSortReverse      bool
 	reportSortStorage      bool
 	reportSort             string
+	listColumns            []string
+	listSort               string
 	reportRaw              bool
 	reportLimitCluster     string
 	reportLeaderDistrib    bool
 	discardPolicy          string
 	validateOnly           bool
 	backupDirectory        string
+	downloadRetries        int
+	restoreTransforms      []string
+	restoreTransformData   bool
+	restoreKeepTTL         bool
+	copyTargetContext      string
+	copyMessages           bool
+	copyMsgID              bool
+	copyProgressFile       string
+	copyTransforms         []string
+	exportOutput           string
+	exportFormat           string
+	exportEncoding         string
+	exportSubjects         []string
+	exportStartSeq         uint64
+	exportEndSeq           uint64
+	exportSince            string
+	exportUntil            string
+	importFile             string
+	importRate             int
+	importMsgID            bool
+	importDryRun           bool
 	showProgress           bool
 	healthCheck            bool
 	snapShotConsumers      bool
+	backupSchedule         string
+	backupKeep             int
+	backupRuns             int
+	backupEventSubject     string
 	dupeWindow             string
 	replicas               int64
 	placementCluster       string
@@ -102,6 +145,43 @@ type streamCmd struct {
 	purgeKeep              uint64
 	purgeSubject           string
 	purgeSequence          uint64
+	purgeOlderThan         string
+	editSelect             string
+	editSelectExpr         string
+	verifyReplicas         bool
+	systemContext          string
+	graphRecordFile        string
+	graphDuration          time.Duration
+	subjectsFilters        []string
+	subjectsPageSize       int
+	subjectsPageToken      string
+	subjectsMinMsgs        uint64
+	stateBySubject         bool
+	csv                    bool
+	subjectsMaxMsgs        uint64
+	subjectsSortSet        bool
+	rmmSubject             string
+	rmmOlderThan           string
+	rmmErase               bool
+	republishSubjects      []string
+	diffTargetContext      string
+	diffState              bool
+	auditBaseline          string
+	dedupeWindow           time.Duration
+	dedupeProducerHeader   string
+	dedupeTop              int
+	moveTargetContext      string
+	moveTargetName         string
+	moveAPIPrefix          string
+	moveDeliverPrefix      string
+	moveOriginal           string
+	moveCheckpoint         string
+	moveAbort              bool
+	moveDetach             bool
+	moveWait               time.Duration
+	rollupSubject          string
+	rollupPayload          string
+	rollupMode             string
 	description            string
 	subjectTransformSource string
 	subjectTransformDest   string
@@ -155,6 +235,11 @@ type streamCmd struct {
 	vwStartDelta time.Duration
 	vwPageSize   int
 	vwRaw        bool
+	vwFollow     bool
+	vwOrdered    bool
+	getFrom      uint64
+	getTo        uint64
+	getLast      uint64
 	vwTranslate  string
 	vwSubject    string
 
@@ -179,19 +264,73 @@ type streamCmd struct {
 }
 
 type streamStat struct {
-	Name      string
-	Consumers int
-	Msgs      int64
-	Bytes     uint64
-	Storage   string
-	Cluster   *api.ClusterInfo
-	LostBytes uint64
-	LostMsgs  int
-	Deleted   int
-	Mirror    *api.StreamSourceInfo
-	Sources   []*api.StreamSourceInfo
-	Placement *api.Placement
-	APILevel  string
+	Name         string
+	Description  string
+	Created      time.Time
+	Consumers    int
+	Msgs         int64
+	Bytes        uint64
+	Storage      string
+	Cluster      *api.ClusterInfo
+	LostBytes    uint64
+	LostMsgs     int
+	Deleted      int
+	Mirror       *api.StreamSourceInfo
+	Sources      []*api.StreamSourceInfo
+	Placement    *api.Placement
+	APILevel     string
+	Replicas     int
+	Leader       string
+	LastActivity time.Time
+	TimeStamp    time.Time
+
+	LargestAckPending int
+	Unprocessed       uint64
+	MostLagged        string
+	mostLaggedPending uint64
+}
+
+func newStreamStat(info *api.StreamInfo) streamStat {
+	deleted := info.State.NumDeleted
+	// backward compat with servers that predate the num_deleted response
+	if len(info.State.Deleted) > 0 {
+		deleted = len(info.State.Deleted)
+	}
+
+	apiLevel := info.Config.Metadata[api.JsMetaRequiredServerLevel]
+	if apiLevel == "" {
+		apiLevel = "0"
+	}
+
+	s := streamStat{
+		Name:         info.Config.Name,
+		Description:  info.Config.Description,
+		Created:      info.Created,
+		Consumers:    info.State.Consumers,
+		Msgs:         int64(info.State.Msgs),
+		Bytes:        info.State.Bytes,
+		Storage:      info.Config.Storage.String(),
+		Cluster:      info.Cluster,
+		Deleted:      deleted,
+		Mirror:       info.Mirror,
+		Sources:      info.Sources,
+		Placement:    info.Config.Placement,
+		APILevel:     apiLevel,
+		Replicas:     info.Config.Replicas,
+		LastActivity: info.State.LastTime,
+		TimeStamp:    info.TimeStamp,
+	}
+
+	if info.Cluster != nil {
+		s.Leader = info.Cluster.Leader
+	}
+
+	if info.State.Lost != nil {
+		s.LostBytes = info.State.Lost.Bytes
+		s.LostMsgs = len(info.State.Lost.Msgs)
+	}
+
+	return s
 }
 
 func configureStreamCommand(app commandHost) {
@@ -208,12 +347,12 @@ func configureStreamCommand(app commandHost) {
 		f.Flag("tag", "Place the stream on servers that has specific tags (pass multiple times)").IsSetByUser(&c.placementTagsSet).StringsVar(&c.placementTags)
 		f.Flag("tags", "Backward compatibility only, use --tag").Hidden().IsSetByUser(&c.placementTagsSet).StringsVar(&c.placementTags)
 		f.Flag("cluster", "Place the stream on a specific cluster").IsSetByUser(&c.placementClusterSet).StringVar(&c.placementCluster)
-		f.Flag("ack", "Acknowledge publishes").Default("true").BoolVar(&c.ack)
+		f.Flag("ack", "Acknowledge publishes").IsSetByUser(&c.ackSet).Default("true").BoolVar(&c.ack)
 		f.Flag("retention", "Defines a retention policy (limits, interest, work)").EnumVar(&c.retentionPolicyS, "limits", "interest", "workq", "work")
 		f.Flag("discard", "Defines the discard policy (new, old)").EnumVar(&c.discardPolicy, "new", "old")
 		f.Flag("discard-per-subject", "Sets the 'new' discard policy and applies it to every subject in the stream").IsSetByUser(&c.discardPerSubjSet).BoolVar(&c.discardPerSubj)
 		if !edit {
-			f.Flag("first-sequence", "Sets the starting sequence").Uint64Var(&c.firstSeq)
+			f.Flag("first-sequence", "Sets the starting sequence, this can not be changed once the stream is created").PlaceHolder("SEQUENCE").Uint64Var(&c.firstSeq)
 		}
 		f.Flag("max-age", "Maximum age of messages to keep").Default("").StringVar(&c.maxAgeLimit)
 		f.Flag("max-bytes", "Maximum bytes to keep").PlaceHolder("BYTES").StringVar(&c.maxBytesLimitString)
@@ -256,6 +395,7 @@ func configureStreamCommand(app commandHost) {
 			f.Flag("persist-mode", "Configures the persistence mode").EnumVar(&c.persistMode, "default", "async")
 		}
 		f.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+		f.Flag("skip-compat-check", "Do not check that the JetStream API supports all the settings").UnNegatableBoolVar(&c.skipCompatCheck)
 
 		f.PreAction(c.parseLimitStrings)
 	}
@@ -268,28 +408,39 @@ func configureStreamCommand(app commandHost) {
 	strAdd.Tag("scope:user", "impact:rw")
 	strAdd.Arg("stream", "Stream name").StringVar(&c.stream)
 	strAdd.Flag("config", "JSON file to read configuration from").ExistingFileVar(&c.inputFile)
+	strAdd.Flag("profile", "Uses a named profile from the configuration directory as defaults").PlaceHolder("PROFILE").StringVar(&c.profile)
 	strAdd.Flag("validate", "Only validates the configuration against the official Schema").UnNegatableBoolVar(&c.validateOnly)
 	strAdd.Flag("output", "Save configuration instead of creating").PlaceHolder("FILE").StringVar(&c.outFile)
 	addCreateFlags(strAdd, false)
 	strAdd.Flag("defaults", "Accept default values for all prompts").UnNegatableBoolVar(&c.acceptDefaults)
+	strAdd.Flag("preview-placement", "Shows the likely placement of the stream before creating it, on by default for interactive replicated streams with system account access").IsSetByUser(&c.previewPlacementSet).BoolVar(&c.previewPlacement)
+	strAdd.Flag("system-context", "Context with system account access used to preview placement, implies --preview-placement").PlaceHolder("CONTEXT").StringVar(&c.systemContext)
 
 	strLs := str.Command("ls", "List all known streams").Alias("list").Alias("l").Action(c.lsAction)
 	strLs.Tag("scope:user", "impact:ro")
 	strLs.Flag("subject", "Limit the list to streams with matching subjects").StringVar(&c.filterSubject)
 	strLs.Flag("names", "Show just the stream names").Short('n').UnNegatableBoolVar(&c.listNames)
 	strLs.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+	strLs.Flag("columns", fmt.Sprintf("Columns to show, comma separated (%s)", strings.Join(streamColumnNames(), ", "))).PlaceHolder("COLUMNS").StringsVar(&c.listColumns)
+	strLs.Flag("sort", "Sort by a column").Default("bytes").EnumVar(&c.listSort, streamColumnNames()...)
+	strLs.Flag("reverse", "Reverse the sort order").Short('R').UnNegatableBoolVar(&c.reportSortReverse)
 
 	strReport := str.Command("report", "Reports on stream statistics").Action(c.reportAction)
 	strReport.Tag("scope:user", "impact:ro")
 	strReport.Flag("subject", "Limit the report to streams with matching subjects").StringVar(&c.filterSubject)
 	strReport.Flag("cluster", "Limit report to streams within a specific cluster").StringVar(&c.reportLimitCluster)
 	strReport.Flag("consumers", "Sort by number of Consumers").Short('o').UnNegatableBoolVar(&c.reportSortConsumers)
+	strReport.Flag("consumer-stats", "Show Consumer ack pending, unprocessed and lag statistics").UnNegatableBoolVar(&c.reportConsumerStats)
 	strReport.Flag("messages", "Sort by number of Messages").Short('m').UnNegatableBoolVar(&c.reportSortMsgs)
 	strReport.Flag("name", "Sort by stream name").Short('n').UnNegatableBoolVar(&c.reportSortName)
 	strReport.Flag("storage", "Sort by Storage type").Short('t').UnNegatableBoolVar(&c.reportSortStorage)
 	strReport.Flag("raw", "Show un-formatted numbers").Short('r').UnNegatableBoolVar(&c.reportRaw)
 	strReport.Flag("dot", "Produce a GraphViz graph of replication topology").StringVar(&c.outFile)
 	strReport.Flag("leaders", "Show details about cluster leaders").Short('l').UnNegatableBoolVar(&c.reportLeaderDistrib)
+	strReport.Flag("columns", fmt.Sprintf("Columns to show, comma separated (%s)", strings.Join(streamColumnNames(), ", "))).PlaceHolder("COLUMNS").StringsVar(&c.listColumns)
+	strReport.Flag("sort", "Sort by a column").EnumVar(&c.listSort, streamColumnNames()...)
+	strReport.Flag("reverse", "Reverse the sort order").Short('R').UnNegatableBoolVar(&c.reportSortReverse)
+	strReport.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
 
 	findHelp := `Expression format:
 
@@ -319,6 +470,12 @@ Finding streams in multiple clusters:
 Finding streams with certain subjects configured:
 
    nats s find --expression '"js.in.orders_1" in config.subjects'
+
+Durations like 24h or 7d and sizes like 1GB or 512MiB can be used as literals,
+durations in config, state and info are strings that are compared using duration():
+
+   nats s find --expression 'duration(config.max_age) > 24h && state.messages == 0'
+   nats s find --expression 'state.bytes > 10GiB && config.num_replicas < 3'
 `
 	strFind := str.Command("find", "Finds streams matching certain criteria").Alias("query").Action(c.findAction)
 	strFind.Tag("scope:user", "impact:ro")
@@ -351,15 +508,24 @@ Finding streams with certain subjects configured:
 	strState.Arg("stream", "Stream to retrieve state information for").StringVar(&c.stream)
 	strState.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
 	strState.Flag("no-select", "Do not select streams from a list").Default("false").UnNegatableBoolVar(&c.force)
+	strState.Flag("by-subject", "Show the state of every subject in the stream").UnNegatableBoolVar(&c.stateBySubject)
+	strState.Flag("filter", "Limit the subjects to those matching a filter (pass multiple times)").PlaceHolder("SUBJECT").StringsVar(&c.subjectsFilters)
+	strState.Flag("min-msgs", "Only show subjects holding at least this many messages").PlaceHolder("MESSAGES").Uint64Var(&c.subjectsMinMsgs)
+	strState.Flag("csv", "Produce CSV output").UnNegatableBoolVar(&c.csv)
 
 	strSubs := str.Command("subjects", "Query subjects held in a stream").Alias("subj").Action(c.subjectsAction)
 	strSubs.Tag("scope:user", "impact:ro")
 	strSubs.Arg("stream", "Stream name").StringVar(&c.stream)
 	strSubs.Arg("filter", "Limit the subjects to those matching a filter").Default(">").StringVar(&c.filterSubject)
+	strSubs.Flag("filter", "Limit the subjects to those matching a filter (pass multiple times)").PlaceHolder("SUBJECT").StringsVar(&c.subjectsFilters)
 	strSubs.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
-	strSubs.Flag("sort", "Adjusts the sorting order (name, messages)").Default("messages").EnumVar(&c.reportSort, "name", "subjects", "messages", "count")
+	strSubs.Flag("sort", "Adjusts the sorting order (name, messages)").Default("messages").IsSetByUser(&c.subjectsSortSet).EnumVar(&c.reportSort, "name", "subjects", "messages", "count")
 	strSubs.Flag("reverse", "Reverse sort servers").Short('R').UnNegatableBoolVar(&c.reportSortReverse)
 	strSubs.Flag("names", "List only subject names").BoolVar(&c.listNames)
+	strSubs.Flag("min-msgs", "Only show subjects holding at least this many messages").PlaceHolder("MESSAGES").Uint64Var(&c.subjectsMinMsgs)
+	strSubs.Flag("max-msgs", "Only show subjects holding at most this many messages").PlaceHolder("MESSAGES").Uint64Var(&c.subjectsMaxMsgs)
+	strSubs.Flag("page-size", "Shows subjects in pages of this size ordered by name").PlaceHolder("SUBJECTS").IntVar(&c.subjectsPageSize)
+	strSubs.Flag("page-token", "Resumes listing from a token shown on a previous page").PlaceHolder("TOKEN").StringVar(&c.subjectsPageToken)
 
 	strEdit := str.Command("edit", "Edits an existing stream").Alias("update").Action(c.editAction)
 	strEdit.Tag("scope:user", "impact:rw")
@@ -368,6 +534,8 @@ Finding streams with certain subjects configured:
 	strEdit.Flag("force", "Force edit without prompting").Short('f').UnNegatableBoolVar(&c.force)
 	strEdit.Flag("interactive", "Edit the configuring using your editor").Short('i').BoolVar(&c.interactive)
 	strEdit.Flag("dry-run", "Only shows differences, do not edit the stream").UnNegatableBoolVar(&c.dryRun)
+	strEdit.Flag("select", "Edits all streams with names matching a glob like ORDERS_*").PlaceHolder("GLOB").StringVar(&c.editSelect)
+	strEdit.Flag("select-expr", "Edits all streams matching a filter expression, see 'stream find --help'").PlaceHolder("EXPRESSION").StringVar(&c.editSelectExpr)
 	addCreateFlags(strEdit, true)
 
 	strRm := str.Command("rm", "Removes a stream").Alias("delete").Alias("del").Action(c.rmAction)
@@ -383,17 +551,44 @@ Finding streams with certain subjects configured:
 	strPurge.Flag("subject", "Limits the purge to a specific subject").PlaceHolder("SUBJECT").StringVar(&c.purgeSubject)
 	strPurge.Flag("seq", "Purge up to but not including a specific message sequence").PlaceHolder("SEQUENCE").Uint64Var(&c.purgeSequence)
 	strPurge.Flag("keep", "Keeps a certain number of messages after the purge").PlaceHolder("MESSAGES").Uint64Var(&c.purgeKeep)
+	strPurge.Flag("older-than", "Purge only messages older than a duration like 1h or 7d").PlaceHolder("DURATION").StringVar(&c.purgeOlderThan)
+	strPurge.Flag("dry-run", "Reports how many messages would be purged per subject without removing any").UnNegatableBoolVar(&c.dryRun)
 
-	strCopy := str.Command("copy", "Creates a new stream based on the configuration of another, does not copy data").Alias("cp").Action(c.cpAction)
+	strCopy := str.Command("copy", "Creates a new stream based on the configuration of another, optionally copying data").Alias("cp").Action(c.cpAction)
 	strCopy.Tag("scope:user", "impact:rw")
+	strCopy.HelpLong(`Creates the destination stream using the source configuration, with --messages
+the messages are read from the source and published to the destination preserving
+subjects and headers, the Nats-Rollup and Nats-Expected-* headers are not copied.
+
+The destination can be in another account or cluster using --target-context, the
+destination stream may then have the same name as the source. When copying messages
+into an existing destination stream it is not created again.
+
+When copying messages within the same context the source stream would capture the
+copied messages, --transform 'orders.>:copy.orders.>' rewrites the destination subjects
+and the subjects of the copied messages so they do not overlap with the source.
+
+Use --progress-file to record the last copied sequence, running the copy again with
+the same file resumes after that sequence.`)
 	strCopy.Arg("source", "Source stream to copy").Required().StringVar(&c.stream)
 	strCopy.Arg("destination", "New stream to create").Required().StringVar(&c.destination)
+	strCopy.Flag("target-context", "Creates the destination stream using a different context").PlaceHolder("CONTEXT").StringVar(&c.copyTargetContext)
+	strCopy.Flag("messages", "Copies the messages in the source stream to the destination").UnNegatableBoolVar(&c.copyMessages)
+	strCopy.Flag("msg-id", "Preserves Nats-Msg-Id headers when copying messages").Default("true").BoolVar(&c.copyMsgID)
+	strCopy.Flag("progress-file", "Records the last copied sequence in this file to support resuming").PlaceHolder("FILE").StringVar(&c.copyProgressFile)
+	strCopy.Flag("transform", "Rewrites the destination subjects and copied messages using a SOURCE:DESTINATION subject transform (pass multiple times)").PlaceHolder("TRANSFORM").StringsVar(&c.copyTransforms)
+	strCopy.Flag("progress", "Enables or disables progress reporting using a progress bar").Default("true").BoolVar(&c.showProgress)
 	addCreateFlags(strCopy, false)
 
-	strRmMsg := str.Command("rmm", "Securely removes an individual message from a stream").Action(c.rmMsgAction)
+	strRmMsg := str.Command("rmm", "Securely removes an individual message, or all messages matching a filter, from a stream").Action(c.rmMsgAction)
 	strRmMsg.Tag("scope:user", "impact:rw")
 	strRmMsg.Arg("stream", "Stream name").StringVar(&c.stream)
 	strRmMsg.Arg("id", "Message Sequence to remove").Int64Var(&c.msgID)
+	strRmMsg.Flag("subject", "Removes all messages matching a subject filter").PlaceHolder("SUBJECT").StringVar(&c.rmmSubject)
+	strRmMsg.Flag("older-than", "Removes all messages older than a duration like 1h or 7d").PlaceHolder("DURATION").StringVar(&c.rmmOlderThan)
+	strRmMsg.Flag("erase", "Securely erase removed messages by overwriting them").Default("true").BoolVar(&c.rmmErase)
+	strRmMsg.Flag("dry-run", "Reports how many messages would be removed without removing any").UnNegatableBoolVar(&c.dryRun)
+	strRmMsg.Flag("progress", "Enables or disables progress reporting using a progress bar").Default("true").BoolVar(&c.showProgress)
 	strRmMsg.Flag("force", "Force removal without prompting").Short('f').UnNegatableBoolVar(&c.force)
 
 	strView := str.Command("view", "View messages in a stream").Action(c.viewAction)
@@ -405,6 +600,8 @@ Finding streams with certain subjects configured:
 	strView.Flag("raw", "Show the raw data received").UnNegatableBoolVar(&c.vwRaw)
 	strView.Flag("translate", "Translate the message data by running it through the given command before output").StringVar(&c.vwTranslate)
 	strView.Flag("subject", "Filter the stream using a subject").StringVar(&c.vwSubject)
+	strView.Flag("follow", "Shows the last messages and then new messages as they arrive").Short('f').UnNegatableBoolVar(&c.vwFollow)
+	strView.Flag("ordered", "Reads the stream using an ordered consumer, pages interactively on a terminal").UnNegatableBoolVar(&c.vwOrdered)
 
 	strGet := str.Command("get", "Retrieves a specific message from a Stream").Action(c.getAction)
 	strGet.Tag("scope:user", "impact:ro")
@@ -413,29 +610,106 @@ Finding streams with certain subjects configured:
 	strGet.Flag("last-for", "Retrieves the message for a specific subject").Short('S').PlaceHolder("SUBJECT").StringVar(&c.filterSubject)
 	strGet.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
 	strGet.Flag("translate", "Translate the message data by running it through the given command before output").StringVar(&c.vwTranslate)
+	strGet.Flag("from", "Retrieves all messages starting at this sequence").PlaceHolder("SEQUENCE").Uint64Var(&c.getFrom)
+	strGet.Flag("to", "Retrieves all messages up to and including this sequence").PlaceHolder("SEQUENCE").Uint64Var(&c.getTo)
+	strGet.Flag("last", "Retrieves the last messages in the Stream").PlaceHolder("MESSAGES").Uint64Var(&c.getLast)
+
+	strExport := str.Command("export", "Exports messages from a stream to a JSONL or CSV file").Action(c.exportAction)
+	strExport.Tag("scope:user", "impact:ro")
+	strExport.HelpLong(`Every message is exported with its sequence, time, subject, headers and data.
+
+The format defaults to csv for files ending in .csv and jsonl otherwise. Data is
+base64 encoded by default, use --encoding string for text data or --encoding json
+to embed JSON data as-is in jsonl files.
+
+The --since and --until flags accept a duration like 1h or a RFC3339 time.`)
+	strExport.Arg("stream", "Stream name").StringVar(&c.stream)
+	strExport.Flag("output", "File to write the messages to, - for STDOUT").Short('o').Default("-").PlaceHolder("FILE").StringVar(&c.exportOutput)
+	strExport.Flag("format", "The output format (jsonl, csv)").EnumVar(&c.exportFormat, "jsonl", "csv")
+	strExport.Flag("encoding", "How to encode message data (base64, string, json)").Default("base64").EnumVar(&c.exportEncoding, "base64", "string", "json")
+	strExport.Flag("subject", "Only exports messages matching a subject (pass multiple times)").StringsVar(&c.exportSubjects)
+	strExport.Flag("start-seq", "Exports messages starting at this sequence").PlaceHolder("SEQUENCE").Uint64Var(&c.exportStartSeq)
+	strExport.Flag("end-seq", "Exports messages up to and including this sequence").PlaceHolder("SEQUENCE").Uint64Var(&c.exportEndSeq)
+	strExport.Flag("since", "Exports messages received since a duration or time").PlaceHolder("DURATION|TIME").StringVar(&c.exportSince)
+	strExport.Flag("until", "Exports messages received before a duration or time").PlaceHolder("DURATION|TIME").StringVar(&c.exportUntil)
+	strExport.Flag("progress", "Enables or disables progress reporting using a progress bar").Default("true").BoolVar(&c.showProgress)
+
+	strImport := str.Command("import", "Imports messages into a stream from a JSONL file created by stream export").Action(c.importAction)
+	strImport.Tag("scope:user", "impact:rw")
+	strImport.HelpLong(`Every record is published with its original subject and headers, records
+without a Nats-Msg-Id header are given one based on the stream and sequence they
+were exported from so importing the same file twice within the duplicate window
+of the stream does not duplicate messages. Files exported from different streams
+can be imported into the same stream, files without the source stream name use the
+name of the stream being imported into.
+
+The Nats-Rollup and Nats-Expected-* headers are not published as the server would
+act on them again.
+
+The --encoding flag must match the one used during export.
+
+Use --dry-run to validate the file against the subjects of the stream.`)
+	strImport.Arg("stream", "Stream name").Required().StringVar(&c.stream)
+	strImport.Arg("file", "The JSONL file to import, - for STDIN").Required().StringVar(&c.importFile)
+	strImport.Flag("encoding", "How message data was encoded during export (base64, string, json)").Default("base64").EnumVar(&c.exportEncoding, "base64", "string", "json")
+	strImport.Flag("rate", "Limits publishing to this many messages per second").PlaceHolder("MSGS").IntVar(&c.importRate)
+	strImport.Flag("msg-id", "Sets a Nats-Msg-Id header on records that do not have one").Default("true").BoolVar(&c.importMsgID)
+	strImport.Flag("dry-run", "Validates the file without publishing any messages").UnNegatableBoolVar(&c.importDryRun)
 
 	strBackup := str.Command("backup", "Creates a backup of a stream over the NATS network").Alias("snapshot").Action(c.backupAction)
 	strBackup.Tag("scope:user", "impact:ro")
-	strBackup.Arg("stream", "Stream to backup").Required().StringVar(&c.stream)
+	strBackup.HelpLong(`When a schedule is given the command keeps running and takes a backup of
+every matching stream each time the schedule fires. The stream name may then
+be a wildcard like ORDERS_* and backups are stored in TARGET/STREAM/TIMESTAMP.
+
+Schedules are standard 5 field cron specifications like "0 2 * * *", aliases
+like daily or intervals like "every 6h".`)
+	strBackup.Arg("stream", "Stream to backup, may be a wildcard when scheduling backups").Required().StringVar(&c.stream)
 	strBackup.Arg("target", "Directory to create the backup in").Required().StringVar(&c.backupDirectory)
 	strBackup.Flag("progress", "Enables or disables progress reporting using a progress bar").Default("true").BoolVar(&c.showProgress)
 	strBackup.Flag("check", "Checks the stream for health prior to backup").UnNegatableBoolVar(&c.healthCheck)
 	strBackup.Flag("consumers", "Enable or disable consumer backups").Default("true").BoolVar(&c.snapShotConsumers)
 	strBackup.Flag("chunk-size", "Sets a specific chunk size that the server will send").StringVar(&c.chunkSize)
 	strBackup.Flag("window-size", "Sets a specific window size that the server will send").StringVar(&c.wndSize)
+	strBackup.Flag("schedule", "Keeps running and takes backups on a cron schedule").PlaceHolder("CRON").StringVar(&c.backupSchedule)
+	strBackup.Flag("keep", "Number of scheduled backups to keep per stream, 0 keeps all").PlaceHolder("COUNT").IntVar(&c.backupKeep)
+	strBackup.Flag("runs", "Exit after this many scheduled runs, 0 runs forever").PlaceHolder("COUNT").IntVar(&c.backupRuns)
+	strBackup.Flag("event-subject", "Publish a completion event to this subject after every scheduled backup").PlaceHolder("SUBJECT").StringVar(&c.backupEventSubject)
 
 	strRestore := str.Command("restore", "Restore a stream over the NATS network").Action(c.restoreAction)
 	strRestore.Tag("scope:user", "impact:rw")
-	strRestore.Arg("file", "The directory holding the backup to restore").Required().ExistingDirVar(&c.backupDirectory)
+	strRestore.HelpLong(`The backup can be a local directory or a http://, https:// or s3:// URL.
+
+URLs may point to a directory holding backup.json and stream.tar.s2 or to a
+.tar, .tgz or .tar.gz archive holding both files with backup.json first. The
+stream data is restored while it downloads, interrupted downloads are resumed
+using range requests.
+
+s3:// URLs use the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN,
+AWS_REGION and AWS_ENDPOINT_URL_S3 environment variables.
+
+Subjects can be rewritten using --transform 'orders.>:restored.orders.>' so the
+restored stream does not listen on the same subjects as the original. Stored
+messages keep their subjects unless --transform-data is given, this republishes
+every message and so temporarily doubles the size of the stream. Republished
+messages have their Nats-Msg-Id, Nats-Rollup, Nats-Expected-* and Nats-TTL
+headers removed, use --transform-keep-ttl to keep the TTLs.`)
+	strRestore.Arg("file", "The directory or URL holding the backup to restore").Required().StringVar(&c.backupDirectory)
 	strRestore.Flag("progress", "Enables or disables progress reporting using a progress bar").Default("true").BoolVar(&c.showProgress)
 	strRestore.Flag("config", "Load a different configuration when restoring the stream").ExistingFileVar(&c.inputFile)
 	strRestore.Flag("cluster", "Place the stream in a specific cluster").StringVar(&c.placementCluster)
 	strRestore.Flag("tag", "Place the stream on servers that has specific tags (pass multiple times)").StringsVar(&c.placementTags)
 	strRestore.Flag("replicas", "Override how many replicas of the data to create").Int64Var(&c.replicas)
+	strRestore.Flag("download-retries", "Number of times to resume an interrupted download of a remote backup").Default("5").IntVar(&c.downloadRetries)
+	strRestore.Flag("transform", "Rewrites the stream subjects using a SOURCE:DESTINATION subject transform (pass multiple times)").PlaceHolder("TRANSFORM").StringsVar(&c.restoreTransforms)
+	strRestore.Flag("transform-data", "Republishes the restored messages through the subject transforms").UnNegatableBoolVar(&c.restoreTransformData)
+	strRestore.Flag("transform-keep-ttl", "Keeps per message TTLs when republishing, the TTLs restart from the time of republishing").UnNegatableBoolVar(&c.restoreKeepTTL)
 
 	strSeal := str.Command("seal", "Seals a stream preventing further updates").Action(c.sealAction)
 	strSeal.Tag("scope:user", "impact:rw")
 	strSeal.Arg("stream", "The name of the stream to seal").Required().StringVar(&c.stream)
+	strSeal.Flag("backup-dir", "Backs the stream up to a directory before sealing it").PlaceHolder("DIR").StringVar(&c.backupDirectory)
+	strSeal.Flag("progress", "Enables or disables backup progress reporting using a progress bar").Default("true").BoolVar(&c.showProgress)
 	strSeal.Flag("force", "Force sealing without prompting").Short('f').UnNegatableBoolVar(&c.force)
 
 	gapDetect := str.Command("gaps", "Detect gaps in the stream content that would be reported as deleted messages").Action(c.detectGaps)
@@ -445,9 +719,126 @@ Finding streams with certain subjects configured:
 	gapDetect.Flag("progress", "Enable progress bar").Default("true").BoolVar(&c.showProgress)
 	gapDetect.Flag("json", "Show detected gaps in JSON format").UnNegatableBoolVar(&c.json)
 
+	strVerify := str.Command("verify", "Verifies the consistency of a stream and its replicas").Action(c.verifyAction)
+	strVerify.Tag("scope:user", "impact:ro")
+	strVerify.HelpLong(`Walks the entire stream looking for sequence gaps that are not explained
+by deleted messages and compares the state of every replica using JSZ
+requests, reporting any drift from the stream leader.
+
+Querying replica state requires system account access, use --system-context
+to name a context with such access when the current one does not have it.
+
+Replicas of streams receiving writes may briefly report drift while they
+catch up with the leader.`)
+	strVerify.Arg("stream", "Stream to verify").StringVar(&c.stream)
+	strVerify.Flag("replicas", "Compares the state of each replica").Default("true").BoolVar(&c.verifyReplicas)
+	strVerify.Flag("system-context", "Context with system account access used to query replica state").PlaceHolder("CONTEXT").StringVar(&c.systemContext)
+	strVerify.Flag("force", "Act without prompting").Short('f').UnNegatableBoolVar(&c.force)
+	strVerify.Flag("progress", "Enable progress bar").Default("true").BoolVar(&c.showProgress)
+	strVerify.Flag("json", "Produce JSON output").UnNegatableBoolVar(&c.json)
+
+	strDiff := str.Command("diff", "Compares the configuration of two streams").Action(c.diffAction)
+	strDiff.Tag("scope:user", "impact:ro")
+	strDiff.HelpLong(`Compares two streams in the same account or, using --target-context, a stream
+with one in another account or cluster, for example to verify that a disaster
+recovery copy matches production.
+
+The stream name is not compared. When the streams differ the differences are
+shown and the command exits with a non zero exit code.`)
+	strDiff.Arg("stream", "The stream to compare").Required().StringVar(&c.stream)
+	strDiff.Arg("other", "The stream to compare with, defaults to the same name").StringVar(&c.destination)
+	strDiff.Flag("target-context", "Loads the other stream using a different context").PlaceHolder("CONTEXT").StringVar(&c.diffTargetContext)
+	strDiff.Flag("state", "Also compares the stream state").UnNegatableBoolVar(&c.diffState)
+
+	strAudit := str.Command("audit", "Audits stream configurations against a policy baseline").Action(c.auditAction)
+	strAudit.Tag("scope:user", "impact:ro")
+	strAudit.HelpLong(`The baseline is a YAML file describing the policy all streams should comply with:
+
+   # streams the baseline applies to, defaults to all
+   streams: ["ORDERS_*"]
+   exclude: ["KV_*", "OBJ_*"]
+   min_replicas: 3
+   min_max_age: 1h
+   max_max_age: 90d
+   forbidden_retention: [interest]
+   forbidden_storage: [memory]
+   required_tags: [ssd]
+
+Every violation is reported and the command exits with a non zero exit code
+when any stream does not comply, making it suitable for use in CI.`)
+	strAudit.Arg("stream", "Only audit streams matching a wildcard").StringVar(&c.stream)
+	strAudit.Flag("baseline", "YAML file holding the baseline policy").Required().ExistingFileVar(&c.auditBaseline)
+	strAudit.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+
+	strDedupe := str.Command("dedupe-report", "Reports on messages stored with duplicate message IDs").Action(c.dedupeReportAction)
+	strDedupe.Tag("scope:user", "impact:ro")
+	strDedupe.HelpLong(`The server rejects messages with a Nats-Msg-Id seen within the duplicate window
+of the stream, messages with a repeated ID found in the stream were therefore
+retried by publishers after the duplicate window passed.
+
+Messages are grouped by subject or by the value of a header that identifies
+the producer.`)
+	strDedupe.Arg("stream", "The stream to analyze").StringVar(&c.stream)
+	strDedupe.Flag("window", "How far back to scan the stream, defaults to 10 times the duplicate window").DurationVar(&c.dedupeWindow)
+	strDedupe.Flag("producer-header", "Groups messages by the value of a header identifying the producer").PlaceHolder("HEADER").StringVar(&c.dedupeProducerHeader)
+	strDedupe.Flag("top", "Number of duplicated message IDs to show").Default("10").IntVar(&c.dedupeTop)
+	strDedupe.Flag("json", "Produce JSON output").Short('j').UnNegatableBoolVar(&c.json)
+
+	strMoveAccount := str.Command("move-account", "Moves a stream to another account").Action(c.moveAccountAction)
+	strMoveAccount.Tag("scope:user", "impact:rw")
+	strMoveAccount.HelpLong(`Creates a stream in the account of --target-context that sources all messages
+from the original stream using the JetStream API of the original account, this
+requires the original account to export its JetStream API and delivery subjects
+and the target account to import them using --api-prefix and --deliver-prefix.
+
+Once the target stream has caught up publishers should be moved to the target
+account, after confirming this the target stream is detached from the original
+which can then be kept, purged or removed. Use --no-detach to stop once caught up
+and run the command again after moving publishers.
+
+With --checkpoint the progress is recorded and running the command again resumes
+the move, a move that was not yet detached can be undone using --abort.`)
+	strMoveAccount.Arg("stream", "The stream to move").Required().StringVar(&c.stream)
+	strMoveAccount.Flag("target-context", "Context for the account to move the stream to").Required().PlaceHolder("CONTEXT").StringVar(&c.moveTargetContext)
+	strMoveAccount.Flag("target-name", "Name of the stream in the target account, defaults to the current name").PlaceHolder("NAME").StringVar(&c.moveTargetName)
+	strMoveAccount.Flag("api-prefix", "Subject prefix the original JetStream API is imported on in the target account").PlaceHolder("PREFIX").StringVar(&c.moveAPIPrefix)
+	strMoveAccount.Flag("deliver-prefix", "Subject prefix the original account delivers sourced messages on").PlaceHolder("PREFIX").StringVar(&c.moveDeliverPrefix)
+	strMoveAccount.Flag("original", "What to do with the original stream once moved (keep, purge, remove)").Default("keep").EnumVar(&c.moveOriginal, "keep", "purge", "remove")
+	strMoveAccount.Flag("checkpoint", "File recording progress, used to resume or abort the move").PlaceHolder("FILE").StringVar(&c.moveCheckpoint)
+	strMoveAccount.Flag("detach", "Detaches the target stream from the original once caught up").Default("true").BoolVar(&c.moveDetach)
+	strMoveAccount.Flag("wait", "Maximum time to wait for the target to catch up").PlaceHolder("DURATION").DurationVar(&c.moveWait)
+	strMoveAccount.Flag("abort", "Aborts a move by removing the target stream").UnNegatableBoolVar(&c.moveAbort)
+	strMoveAccount.Flag("force", "Act without prompting").Short('f').UnNegatableBoolVar(&c.force)
+
+	strRollup := str.Command("rollup", "Publishes a message that replaces earlier messages in a stream").Action(c.rollupAction)
+	strRollup.Tag("scope:user", "impact:rw")
+	strRollup.HelpLong(`Publishes a message with the Nats-Rollup header set, in sub mode all earlier
+messages on the subject are removed while in all mode every earlier message
+in the stream is removed.
+
+The stream must allow roll-ups, see the --allow-rollup option of stream edit.`)
+	strRollup.Arg("stream", "The stream to roll up").Required().StringVar(&c.stream)
+	strRollup.Arg("subject", "The subject to publish the roll-up message to").Required().StringVar(&c.rollupSubject)
+	strRollup.Flag("payload", "The body of the roll-up message").PlaceHolder("BODY").StringVar(&c.rollupPayload)
+	strRollup.Flag("mode", "Rolls up the subject (sub) or the entire stream (all)").Default("sub").EnumVar(&c.rollupMode, "sub", "all")
+	strRollup.Flag("force", "Act without prompting").Short('f').UnNegatableBoolVar(&c.force)
+
+	strRepublishTest := str.Command("republish-test", "Shows how subjects would be transformed and republished by a stream").Action(c.republishTestAction)
+	strRepublishTest.Tag("scope:user", "impact:ro")
+	strRepublishTest.HelpLong(`Evaluates the stream subject transform and RePublish configuration for the given
+subjects without publishing any messages.
+
+Subjects that would not be stored in the stream or that would not match the
+RePublish source are flagged and cause a non zero exit code.`)
+	strRepublishTest.Arg("stream", "The name of the stream to test").StringVar(&c.stream)
+	strRepublishTest.Flag("subject", "Subject to test, can be passed multiple times").Required().PlaceHolder("SUBJECT").StringsVar(&c.republishSubjects)
+	strRepublishTest.Flag("json", "Produce JSON output").UnNegatableBoolVar(&c.json)
+
 	graph := str.Command("graph", "View a graph of stream activity").Action(c.graphAction)
 	graph.Tag("scope:user", "impact:ro")
 	graph.Arg("stream", "The name of the stream to graph").StringVar(&c.stream)
+	graph.Flag("record", "Writes every sampled datapoint to a CSV file").PlaceHolder("FILE").StringVar(&c.graphRecordFile)
+	graph.Flag("duration", "Stops sampling after a period, without a terminal only data is recorded").PlaceHolder("DURATION").DurationVar(&c.graphDuration)
 
 	strCluster := str.Command("cluster", "Manages a clustered stream").Alias("c")
 	strClusterDown := strCluster.Command("step-down", "Force a new leader election by standing down the current leader").Alias("stepdown").Alias("sd").Alias("elect").Alias("down").Alias("d").Action(c.leaderStandDown)
@@ -506,17 +897,23 @@ func (c *streamCmd) kvAbstractionWarn(stream string, prompt string) error {
 }
 
 func (c *streamCmd) graphAction(_ *fisk.ParseContext) error {
-	if !iu.IsTerminal() {
-		return fmt.Errorf("can only graph data on an interactive terminal")
+	headless := !iu.IsTerminal()
+	if headless && c.graphRecordFile == "" {
+		return fmt.Errorf("can only graph data on an interactive terminal, use --record to capture data without one")
 	}
 
-	width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
-	if err != nil {
-		return fmt.Errorf("failed to get terminal dimensions: %w", err)
-	}
+	var width, height int
+	var err error
+
+	if !headless {
+		width, height, err = terminal.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to get terminal dimensions: %w", err)
+		}
 
-	if width < 20 || height < 20 {
-		return fmt.Errorf("please increase terminal dimensions")
+		if width < 20 || height < 20 {
+			return fmt.Errorf("please increase terminal dimensions")
+		}
 	}
 
 	c.connectAndAskStream()
@@ -529,6 +926,27 @@ func (c *streamCmd) graphAction(_ *fisk.ParseContext) error {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
 
+	if c.graphDuration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.graphDuration)
+		defer cancel()
+	}
+
+	var recorder *csv.Writer
+	if c.graphRecordFile != "" {
+		rf, err := os.Create(c.graphRecordFile)
+		if err != nil {
+			return err
+		}
+		defer rf.Close()
+
+		recorder = csv.NewWriter(rf)
+		err = recorder.Write([]string{"time", "messages", "bytes", "stored_rate", "removed_rate", "lag"})
+		if err != nil {
+			return err
+		}
+		recorder.Flush()
+	}
+
 	nfo, err := stream.State()
 	if err != nil {
 		return err
@@ -540,6 +958,7 @@ func (c *streamCmd) graphAction(_ *fisk.ParseContext) error {
 	lastLastSeq := nfo.LastSeq
 	lastFirstSeq := nfo.FirstSeq
 	lastStateTs := time.Now()
+	var samples int
 
 	resizeData := func(data []float64, width int) []float64 {
 		if width <= 0 {
@@ -559,35 +978,65 @@ func (c *streamCmd) graphAction(_ *fisk.ParseContext) error {
 	for {
 		select {
 		case <-ticker.C:
-			width, height, err = terminal.GetSize(int(os.Stdout.Fd()))
-			if err != nil {
-				height = 40
-				width = 80
-			}
-			if width > 15 {
-				width -= 11
-			}
-			if height > 10 {
-				height -= 6
-			}
+			if !headless {
+				width, height, err = terminal.GetSize(int(os.Stdout.Fd()))
+				if err != nil {
+					height = 40
+					width = 80
+				}
+				if width > 15 {
+					width -= 11
+				}
+				if height > 10 {
+					height -= 6
+				}
 
-			if width < 20 || height < 20 {
-				return fmt.Errorf("please increase terminal dimensions")
+				if width < 20 || height < 20 {
+					return fmt.Errorf("please increase terminal dimensions")
+				}
 			}
 
-			nfo, err := stream.State()
+			info, err := stream.Information()
 			if err != nil {
 				continue
 			}
+			nfo := info.State
 
-			messagesStored = append(messagesStored, float64(nfo.Msgs))
-			messageRates = append(messageRates, calculateRate(float64(nfo.LastSeq), float64(lastLastSeq), time.Since(lastStateTs)))
-			limitedRates = append(limitedRates, calculateRate(float64(nfo.FirstSeq), float64(lastFirstSeq), time.Since(lastStateTs)))
+			storedRate := calculateRate(float64(nfo.LastSeq), float64(lastLastSeq), time.Since(lastStateTs))
+			removedRate := calculateRate(float64(nfo.FirstSeq), float64(lastFirstSeq), time.Since(lastStateTs))
 
 			lastStateTs = time.Now()
 			lastLastSeq = nfo.LastSeq
 			lastFirstSeq = nfo.FirstSeq
 
+			if recorder != nil {
+				err = recorder.Write([]string{
+					lastStateTs.UTC().Format(time.RFC3339),
+					strconv.FormatUint(nfo.Msgs, 10),
+					strconv.FormatUint(nfo.Bytes, 10),
+					strconv.FormatFloat(storedRate, 'f', 2, 64),
+					strconv.FormatFloat(removedRate, 'f', 2, 64),
+					strconv.FormatUint(streamReplicationLag(info), 10),
+				})
+				if err != nil {
+					return err
+				}
+				recorder.Flush()
+				if recorder.Error() != nil {
+					return recorder.Error()
+				}
+				samples++
+			}
+
+			// without a terminal nothing is plotted so the graph data is not kept
+			if headless {
+				continue
+			}
+
+			messagesStored = append(messagesStored, float64(nfo.Msgs))
+			messageRates = append(messageRates, storedRate)
+			limitedRates = append(limitedRates, removedRate)
+
 			messageRates = resizeData(messageRates, width)
 			messagesStored = resizeData(messagesStored, width)
 			limitedRates = resizeData(limitedRates, width)
@@ -630,13 +1079,54 @@ func (c *streamCmd) graphAction(_ *fisk.ParseContext) error {
 			fmt.Println(msgRatePlot)
 
 		case <-ctx.Done():
-			iu.ClearScreen()
+			if !headless {
+				iu.ClearScreen()
+			}
+			if recorder != nil {
+				fmt.Printf("Recorded %s samples to %s\n", f(samples), c.graphRecordFile)
+			}
 			return nil
 		}
 	}
 }
 
-func (c *streamCmd) detectGaps(_ *fisk.ParseContext) error {
+// streamReplicationLag is the total lag of the mirror and all sources of a stream
+func streamReplicationLag(info *api.StreamInfo) uint64 {
+	var lag uint64
+	if info.Mirror != nil {
+		lag += info.Mirror.Lag
+	}
+	for _, source := range info.Sources {
+		if source != nil {
+			lag += source.Lag
+		}
+	}
+
+	return lag
+}
+
+type streamVerifyReplica struct {
+	Server   string   `json:"server"`
+	Leader   bool     `json:"leader"`
+	Messages uint64   `json:"messages"`
+	Bytes    uint64   `json:"bytes"`
+	FirstSeq uint64   `json:"first_seq"`
+	LastSeq  uint64   `json:"last_seq"`
+	Drift    []string `json:"drift,omitempty"`
+}
+
+type streamVerifyResult struct {
+	Stream       string                 `json:"stream"`
+	FirstSeq     uint64                 `json:"first_seq"`
+	LastSeq      uint64                 `json:"last_seq"`
+	Deleted      int                    `json:"deleted"`
+	Gaps         [][2]uint64            `json:"unexplained_gaps"`
+	Replicas     []*streamVerifyReplica `json:"replicas,omitempty"`
+	ReplicaError string                 `json:"replica_error,omitempty"`
+	Consistent   bool                   `json:"consistent"`
+}
+
+func (c *streamCmd) verifyAction(_ *fisk.ParseContext) error {
 	c.connectAndAskStream()
 
 	stream, err := c.loadStream(c.stream)
@@ -649,19 +1139,10 @@ func (c *streamCmd) detectGaps(_ *fisk.ParseContext) error {
 		return err
 	}
 
-	if info.State.NumDeleted == 0 {
-		if c.json {
-			fmt.Println("{}")
-		}
-
-		fmt.Printf("No deleted messages in %s\n", c.stream)
-		return nil
-	}
-
-	if !c.force {
-		fmt.Println("WARNING: Detecting gaps in a stream consumes the entire stream and can be resource intensive on the Server, Client and Network.")
+	if !c.force && info.State.Msgs > 0 {
+		fmt.Println("WARNING: Verifying a stream consumes the entire stream and can be resource intensive on the Server, Client and Network.")
 		fmt.Println()
-		ok, err := askConfirmation(fmt.Sprintf("Really detect gaps in stream %s with %s messages and %s bytes", c.stream, humanize.Comma(int64(info.State.Msgs)), humanize.IBytes(info.State.Bytes)), false)
+		ok, err := askConfirmation(fmt.Sprintf("Really verify stream %s with %s messages and %s bytes", c.stream, humanize.Comma(int64(info.State.Msgs)), humanize.IBytes(info.State.Bytes)), false)
 		fisk.FatalIfError(err, "could not obtain confirmation")
 
 		if !ok {
@@ -673,11 +1154,48 @@ func (c *streamCmd) detectGaps(_ *fisk.ParseContext) error {
 		c.showProgress = false
 	}
 
+	res := &streamVerifyResult{Stream: stream.Name(), Gaps: [][2]uint64{}}
+
+	err = c.verifyStreamSequences(stream, res)
+	if err != nil {
+		return err
+	}
+
+	if c.verifyReplicas {
+		err = c.verifyStreamReplicas(stream, res)
+		if err != nil {
+			res.ReplicaError = err.Error()
+		}
+	}
+
+	res.Consistent = len(res.Gaps) == 0
+	for _, r := range res.Replicas {
+		if len(r.Drift) > 0 {
+			res.Consistent = false
+		}
+	}
+
+	if c.json {
+		err = iu.PrintJSON(res)
+		if err != nil {
+			return err
+		}
+	} else {
+		c.renderStreamVerify(res)
+	}
+
+	if !res.Consistent {
+		return fmt.Errorf("stream %s failed verification", stream.Name())
+	}
+
+	return nil
+}
+
+// verifyStreamSequences walks the stream and records gaps that are not accounted for by deleted messages
+func (c *streamCmd) verifyStreamSequences(stream *jsm.Stream, res *streamVerifyResult) error {
 	var progbar progress.Writer
 	var tracker *progress.Tracker
-
-	var gaps [][2]uint64
-	var cnt int
+	var err error
 
 	progressCb := func(seq uint64, pending uint64) {
 		if !c.showProgress {
@@ -688,7 +1206,6 @@ func (c *streamCmd) detectGaps(_ *fisk.ParseContext) error {
 				Total: int64(pending),
 			})
 		}
-		cnt++
 
 		tracker.SetValue(int64(seq))
 
@@ -698,6 +1215,7 @@ func (c *streamCmd) detectGaps(_ *fisk.ParseContext) error {
 		}
 	}
 
+	var gaps [][2]uint64
 	gapCb := func(start, end uint64) {
 		gaps = append(gaps, [2]uint64{start, end})
 	}
@@ -712,289 +1230,512 @@ func (c *streamCmd) detectGaps(_ *fisk.ParseContext) error {
 		return err
 	}
 
-	if c.json {
-		iu.PrintJSON(gaps)
-		return nil
+	// deletes are fetched after the walk so any made while walking are known
+	info, err := stream.Information(api.JSApiStreamInfoRequest{DeletedDetails: true})
+	if err != nil {
+		return err
 	}
 
-	if len(gaps) == 0 {
-		fmt.Printf("No deleted messages in %s\n", c.stream)
-		return nil
+	res.FirstSeq = info.State.FirstSeq
+	res.LastSeq = info.State.LastSeq
+	res.Deleted = info.State.NumDeleted
+	if len(info.State.Deleted) > res.Deleted {
+		res.Deleted = len(info.State.Deleted)
 	}
 
-	var table *iu.Table
-	if len(gaps) == 1 {
-		table = iu.NewTableWriterf(opts(), "1 gap found in Stream %s", c.stream)
-	} else {
-		table = iu.NewTableWriterf(opts(), "%s gaps found in Stream %s", f(len(gaps)), c.stream)
+	deleted := make(map[uint64]struct{}, len(info.State.Deleted))
+	for _, seq := range info.State.Deleted {
+		deleted[seq] = struct{}{}
 	}
 
-	table.AddHeaders("First Message", "Last Message")
+	var missing uint64
+	var unexplained [][2]uint64
+
 	for _, gap := range gaps {
-		table.AddRow(f(gap[0]), f(gap[1]))
+		start := gap[0]
+		// messages below the first sequence were removed by limits or purges
+		if start < info.State.FirstSeq {
+			start = info.State.FirstSeq
+		}
+
+		var open bool
+		for seq := start; seq <= gap[1]; seq++ {
+			missing++
+
+			_, isDeleted := deleted[seq]
+			switch {
+			case isDeleted && open:
+				open = false
+			case !isDeleted && len(deleted) > 0 && !open:
+				unexplained = append(unexplained, [2]uint64{seq, seq})
+				open = true
+			case !isDeleted && len(deleted) > 0:
+				unexplained[len(unexplained)-1][1] = seq
+			}
+		}
+	}
+
+	// servers that do not report deleted details can only be compared by count
+	if len(deleted) == 0 && missing != uint64(info.State.NumDeleted) {
+		unexplained = gaps
+	}
+
+	if unexplained != nil {
+		res.Gaps = unexplained
 	}
-	fmt.Println(table.Render())
 
 	return nil
 }
 
-func (c *streamCmd) subjectsAction(_ *fisk.ParseContext) (err error) {
-	asked := c.connectAndAskStream()
-
-	subs, err := c.mgr.StreamContainedSubjects(c.stream, c.filterSubject)
+// verifyStreamReplicas gathers the state of every replica using JSZ and compares them to the leader
+func (c *streamCmd) verifyStreamReplicas(stream *jsm.Stream, res *streamVerifyResult) error {
+	info, err := stream.LatestInformation()
 	if err != nil {
 		return err
 	}
 
-	if c.json {
-		iu.PrintJSON(subs)
+	if info.Cluster == nil {
 		return nil
 	}
 
-	if asked {
-		fmt.Println()
+	account := ""
+	resp, err := c.nc.Request("$SYS.REQ.USER.INFO", nil, opts().Timeout)
+	if err == nil {
+		var ui struct {
+			Data *server.UserInfo `json:"data"`
+		}
+		if json.Unmarshal(resp.Data, &ui) == nil && ui.Data != nil {
+			account = ui.Data.Account
+		}
 	}
 
-	if len(subs) == 0 {
-		fmt.Printf("No subjects found matching %s\n", c.filterSubject)
-		return nil
+	nc := c.nc
+	if c.systemContext != "" {
+		nc, _, _, err = connectToContext(c.systemContext)
+		if err != nil {
+			return err
+		}
+		defer nc.Close()
 	}
 
-	var longest int
-	var most uint64
-	var names []string
+	expected, err := serverdata.CurrentActiveServers(ctx, nc, opts().Timeout, traceLogger())
+	if err != nil {
+		return fmt.Errorf("could not determine active servers: %w", err)
+	}
 
-	for s, c := range subs {
-		names = append(names, s)
-		if len(s) > longest {
-			longest = len(s)
-		}
-		if c > most {
-			most = c
-		}
+	reqFn := func(req any, subj string, waitFor int, nc *nats.Conn) ([][]byte, error) {
+		return serverdata.DoReq(ctx, req, subj, waitFor, nc, opts().Timeout, traceLogger())
 	}
 
-	cols := 1
-	countWidth := len(f(most))
-	table := iu.NewTableWriterf(opts(), "%d Subjects in stream %s", len(names), c.stream)
+	ds, err := serverdata.NewLive(nc, reqFn, expected)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
 
-	switch {
-	case longest+countWidth < 20:
-		cols = 3
-		table.AddHeaders("Subject", "Count", "Subject", "Count", "Subject", "Count")
-	case longest+countWidth < 30:
-		cols = 2
-		table.AddHeaders("Subject", "Count", "Subject", "Count")
-	default:
-		table.AddHeaders("Subject", "Count")
+	responses, err := ds.Jsz(server.JszEventOptions{JSzOptions: server.JSzOptions{Account: account, Streams: true}})
+	if err != nil {
+		return err
 	}
 
-	sort.Slice(names, func(i, j int) bool {
-		if c.reportSort == "name" || c.reportSort == "subjects" {
-			return c.boolReverse(names[i] < names[j])
-		} else {
-			return c.boolReverse(subs[names[i]] < subs[names[j]])
+	var leader *streamVerifyReplica
+	for _, resp := range responses {
+		if resp.Server == nil || resp.Data == nil {
+			continue
 		}
-	})
 
-	if c.listNames {
-		for _, n := range names {
-			fmt.Println(n)
+		for _, acc := range resp.Data.AccountDetails {
+			if account != "" && acc.Name != account && acc.Id != account {
+				continue
+			}
+
+			for _, sd := range acc.Streams {
+				if sd.Name != stream.Name() {
+					continue
+				}
+
+				r := &streamVerifyReplica{
+					Server:   resp.Server.Name,
+					Messages: sd.State.Msgs,
+					Bytes:    sd.State.Bytes,
+					FirstSeq: sd.State.FirstSeq,
+					LastSeq:  sd.State.LastSeq,
+					Leader:   resp.Server.Name == info.Cluster.Leader,
+				}
+				if r.Leader {
+					leader = r
+				}
+
+				res.Replicas = append(res.Replicas, r)
+			}
 		}
-		return
 	}
 
-	comma := func(i uint64) string {
-		if i == 0 {
-			return ""
-		}
-		return f(i)
+	if len(res.Replicas) == 0 {
+		return fmt.Errorf("no replica state received, system account access is required")
 	}
 
-	iu.SliceGroups(names, cols, func(g []string) {
-		if cols == 1 {
-			table.AddRow(g[0], comma(subs[g[0]]))
-		} else if cols == 2 {
-			table.AddRow(g[0], comma(subs[g[0]]), g[1], comma(subs[g[1]]))
-		} else {
-			table.AddRow(g[0], comma(subs[g[0]]), g[1], comma(subs[g[1]]), g[2], comma(subs[g[2]]))
-		}
-	})
+	sort.Slice(res.Replicas, func(i, j int) bool { return res.Replicas[i].Server < res.Replicas[j].Server })
 
-	fmt.Println(table.Render())
+	if leader == nil {
+		return fmt.Errorf("no state received from the stream leader %q", info.Cluster.Leader)
+	}
+
+	for _, r := range res.Replicas {
+		if r.FirstSeq != leader.FirstSeq {
+			r.Drift = append(r.Drift, "first sequence")
+		}
+		if r.LastSeq != leader.LastSeq {
+			r.Drift = append(r.Drift, "last sequence")
+		}
+		if r.Messages != leader.Messages {
+			r.Drift = append(r.Drift, "messages")
+		}
+		if r.Bytes != leader.Bytes {
+			r.Drift = append(r.Drift, "bytes")
+		}
+	}
 
 	return nil
 }
 
-func (c *streamCmd) parseLimitStrings(_ *fisk.ParseContext) (err error) {
-	if c.maxBytesLimitString != "" {
-		c.maxBytesLimit, err = iu.ParseStringAsBytes(c.maxBytesLimitString, 32)
-		if err != nil {
-			return err
+func (c *streamCmd) renderStreamVerify(res *streamVerifyResult) {
+	cols := newColumnsf("Verification of Stream %s", res.Stream)
+	defer cols.Frender(os.Stdout)
+
+	cols.AddRow("First Sequence", res.FirstSeq)
+	cols.AddRow("Last Sequence", res.LastSeq)
+	cols.AddRow("Deleted Messages", res.Deleted)
+	cols.AddRow("Unexplained Gaps", len(res.Gaps))
+	if res.ReplicaError != "" {
+		cols.AddRow("Replica Check", res.ReplicaError)
+	}
+	cols.AddRow("Consistent", res.Consistent)
+
+	if len(res.Gaps) > 0 {
+		table := iu.NewTableWriter(opts(), "Gaps not explained by deleted messages")
+		table.AddHeaders("First Message", "Last Message")
+		for _, gap := range res.Gaps {
+			table.AddRow(f(gap[0]), f(gap[1]))
 		}
+		cols.Println()
+		cols.Println(table.Render())
 	}
 
-	if c.maxMsgSizeString != "" {
-		c.maxMsgSize, err = iu.ParseStringAsBytes(c.maxMsgSizeString, 32)
-		if err != nil {
-			return err
+	if len(res.Replicas) > 0 {
+		table := iu.NewTableWriter(opts(), "Replica State")
+		table.AddHeaders("Server", "Leader", "Messages", "Bytes", "First Sequence", "Last Sequence", "Drift")
+		for _, r := range res.Replicas {
+			leader := ""
+			if r.Leader {
+				leader = "yes"
+			}
+			table.AddRow(r.Server, leader, f(r.Messages), humanize.IBytes(r.Bytes), f(r.FirstSeq), f(r.LastSeq), strings.Join(r.Drift, ", "))
 		}
+		cols.Println()
+		cols.Println(table.Render())
 	}
+}
 
-	return nil
+// streamDiffValue is the normalized view of a stream compared by stream diff
+type streamDiffValue struct {
+	Config api.StreamConfig `json:"config"`
+	State  *streamDiffState `json:"state,omitempty"`
 }
 
-func (c *streamCmd) findAction(_ *fisk.ParseContext) (err error) {
-	c.nc, c.mgr, err = prepareHelper("", natsOpts()...)
+type streamDiffState struct {
+	Messages  uint64 `json:"messages"`
+	Bytes     uint64 `json:"bytes"`
+	FirstSeq  uint64 `json:"first_seq"`
+	LastSeq   uint64 `json:"last_seq"`
+	Subjects  int    `json:"subjects"`
+	Deleted   int    `json:"deleted"`
+	Consumers int    `json:"consumers"`
+}
+
+func (c *streamCmd) streamDiffValue(stream *jsm.Stream) (*streamDiffValue, error) {
+	nfo, err := stream.Information()
 	if err != nil {
-		return fmt.Errorf("setup failed: %v", err)
+		return nil, err
 	}
 
-	var opts []jsm.StreamQueryOpt
-	if c.fServer != "" {
-		opts = append(opts, jsm.StreamQueryServerName(c.fServer))
-	}
-	if c.fCluster != "" {
-		opts = append(opts, jsm.StreamQueryClusterName(c.fCluster))
+	res := &streamDiffValue{Config: nfo.Config}
+	res.Config.Name = ""
+	res.Config.Metadata = iu.RemoveReservedMetadata(res.Config.Metadata)
+	if len(res.Config.Metadata) == 0 {
+		res.Config.Metadata = nil
 	}
-	if c.fEmpty {
-		opts = append(opts, jsm.StreamQueryWithoutMessages())
+
+	// streams listening on the same subjects or placed using the same tags in a different order are considered equal
+	res.Config.Subjects = slices.Sorted(slices.Values(res.Config.Subjects))
+	if res.Config.Placement != nil {
+		placement := *res.Config.Placement
+		placement.Tags = slices.Sorted(slices.Values(placement.Tags))
+		res.Config.Placement = &placement
 	}
-	if c.fIdle > 0 {
-		opts = append(opts, jsm.StreamQueryIdleLongerThan(c.fIdle))
-	}
-	if c.fCreated > 0 {
-		opts = append(opts, jsm.StreamQueryOlderThan(c.fCreated))
-	}
-	if c.fConsumers >= 0 {
-		opts = append(opts, jsm.StreamQueryFewerConsumersThan(uint(c.fConsumers)))
-	}
-	if c.fInvert {
-		opts = append(opts, jsm.StreamQueryInvert())
-	}
-	if c.filterSubject != "" {
-		opts = append(opts, jsm.StreamQuerySubjectWildcard(c.filterSubject))
+
+	if c.diffState {
+		res.State = &streamDiffState{
+			Messages:  nfo.State.Msgs,
+			Bytes:     nfo.State.Bytes,
+			FirstSeq:  nfo.State.FirstSeq,
+			LastSeq:   nfo.State.LastSeq,
+			Subjects:  nfo.State.NumSubjects,
+			Deleted:   nfo.State.NumDeleted,
+			Consumers: nfo.State.Consumers,
+		}
 	}
-	if c.fSourcedSet {
-		opts = append(opts, jsm.StreamQueryIsSourced())
+
+	return res, nil
+}
+
+// render formats the value as indented JSON, wildcards in subjects are not escaped so the diff stays readable
+func (v *streamDiffValue) render() (string, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(v)
+	if err != nil {
+		return "", err
 	}
-	if c.fMirroredSet {
-		opts = append(opts, jsm.StreamQueryIsMirror())
+
+	return buf.String(), nil
+}
+
+func (c *streamCmd) diffAction(_ *fisk.ParseContext) error {
+	if c.destination == "" {
+		c.destination = c.stream
 	}
-	if c.fReplicas > 0 {
-		opts = append(opts, jsm.StreamQueryReplicas(c.fReplicas))
+
+	if c.destination == c.stream && c.diffTargetContext == "" {
+		return fmt.Errorf("comparing a stream with itself requires --target-context")
 	}
-	if c.fExpression != "" {
-		opts = append(opts, jsm.StreamQueryExpression(c.fExpression))
+
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
 	}
-	if c.fLeader != "" {
-		opts = append(opts, jsm.StreamQueryLeaderServer(c.fLeader))
+
+	mgr := c.mgr
+	otherLabel := c.destination
+	if c.diffTargetContext != "" {
+		var nc *nats.Conn
+		nc, mgr, _, err = connectToContext(c.diffTargetContext)
+		if err != nil {
+			return fmt.Errorf("could not connect to context %s: %w", c.diffTargetContext, err)
+		}
+		defer nc.Close()
+
+		// contexts may be given as a path to a context file
+		otherLabel = fmt.Sprintf("%s/%s", strings.TrimSuffix(filepath.Base(c.diffTargetContext), ".json"), c.destination)
 	}
-	if c.apiLevel > 0 {
-		opts = append(opts, jsm.StreamQueryApiLevelMin(c.apiLevel))
+
+	other, err := mgr.LoadStream(c.destination)
+	if err != nil {
+		return fmt.Errorf("could not load Stream %s: %w", otherLabel, err)
 	}
 
-	found, err := c.mgr.QueryStreams(opts...)
+	a, err := c.streamDiffValue(stream)
+	if err != nil {
+		return err
+	}
+	b, err := c.streamDiffValue(other)
 	if err != nil {
 		return err
 	}
 
-	out := ""
-	switch {
-	case c.listNames:
-		out = c.renderStreamsAsList(found, nil)
-	default:
-		out, err = c.renderStreamsAsTable(found, nil, nil)
+	aj, err := a.render()
+	if err != nil {
+		return err
 	}
+	bj, err := b.render()
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(out)
-
-	return nil
-}
-
-func (c *streamCmd) loadStream(stream string) (*jsm.Stream, error) {
-	if c.selectedStream != nil && c.selectedStream.Name() == stream {
-		return c.selectedStream, nil
+	diff := iu.UnifiedDiff(c.stream, otherLabel, aj, bj, 3)
+	if diff == "" {
+		fmt.Printf("Stream %s and %s are identical\n", c.stream, otherLabel)
+		return nil
 	}
 
-	return c.mgr.LoadStream(stream)
+	fmt.Print(diff)
+	os.Exit(1)
+
+	return nil
 }
 
-func (c *streamCmd) balanceAction(_ *fisk.ParseContext) error {
-	var err error
+func (c *streamCmd) rollupAction(_ *fisk.ParseContext) error {
+	c.connectAndAskStream()
 
-	c.nc, c.mgr, err = prepareHelper("", natsOpts()...)
+	stream, err := c.loadStream(c.stream)
 	if err != nil {
-		return fmt.Errorf("setup failed: %v", err)
+		return err
 	}
 
-	var opts []jsm.StreamQueryOpt
-	if c.fServer != "" {
-		opts = append(opts, jsm.StreamQueryServerName(c.fServer))
-	}
-	if c.fCluster != "" {
-		opts = append(opts, jsm.StreamQueryClusterName(c.fCluster))
-	}
-	if c.fEmpty {
-		opts = append(opts, jsm.StreamQueryWithoutMessages())
+	if !stream.RollupAllowed() {
+		return fmt.Errorf("stream %s does not allow roll-ups", stream.Name())
 	}
-	if c.fIdle > 0 {
-		opts = append(opts, jsm.StreamQueryIdleLongerThan(c.fIdle))
+
+	matched := false
+	for _, subj := range stream.Subjects() {
+		if jsm.SubjectIsSubsetMatch(c.rollupSubject, subj) {
+			matched = true
+			break
+		}
 	}
-	if c.fCreated > 0 {
-		opts = append(opts, jsm.StreamQueryOlderThan(c.fCreated))
+	if !matched {
+		return fmt.Errorf("subject %s is not stored in Stream %s", c.rollupSubject, stream.Name())
 	}
-	if c.fConsumers >= 0 {
-		opts = append(opts, jsm.StreamQueryFewerConsumersThan(uint(c.fConsumers)))
+
+	if !c.force {
+		msg := fmt.Sprintf("Really replace all messages on subject %s in Stream %s", c.rollupSubject, stream.Name())
+		if c.rollupMode == "all" {
+			msg = fmt.Sprintf("Really replace all messages in Stream %s", stream.Name())
+		}
+
+		ok, err := askConfirmation(msg, false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
 	}
-	if c.fInvert {
-		opts = append(opts, jsm.StreamQueryInvert())
+
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return err
 	}
-	if c.filterSubject != "" {
-		opts = append(opts, jsm.StreamQuerySubjectWildcard(c.filterSubject))
+
+	msg := nats.NewMsg(c.rollupSubject)
+	msg.Header.Set(api.JSRollup, c.rollupMode)
+	msg.Data = []byte(c.rollupPayload)
+
+	ack, err := js.PublishMsg(ctx, msg, jetstream.WithExpectStream(stream.Name()))
+	if err != nil {
+		return fmt.Errorf("roll-up failed: %w", err)
 	}
-	if c.fSourcedSet {
-		opts = append(opts, jsm.StreamQueryIsSourced())
+
+	state, err := stream.State()
+	if err != nil {
+		return err
 	}
-	if c.fMirroredSet {
-		opts = append(opts, jsm.StreamQueryIsMirror())
+
+	fmt.Printf("Published roll-up message to %s at sequence %s, Stream %s now holds %s messages\n", c.rollupSubject, f(ack.Sequence), stream.Name(), f(state.Msgs))
+
+	return nil
+}
+
+type streamRepublishTestResult struct {
+	Subject     string `json:"subject"`
+	Stored      string `json:"stored_subject,omitempty"`
+	Republished string `json:"republished_subject,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// republishSubject follows the path a message published on subject takes through the stream
+// subject transform and the RePublish mapping, mirroring the order applied by the server
+func republishSubject(cfg api.StreamConfig, subject string) streamRepublishTestResult {
+	res := streamRepublishTestResult{Subject: subject}
+
+	if len(cfg.Subjects) > 0 {
+		stored := false
+		for _, s := range cfg.Subjects {
+			if jsm.SubjectIsSubsetMatch(subject, s) {
+				stored = true
+				break
+			}
+		}
+
+		if !stored {
+			res.Error = "not stored in the stream"
+			return res
+		}
 	}
-	if c.fReplicas > 0 {
-		opts = append(opts, jsm.StreamQueryReplicas(c.fReplicas))
+
+	res.Stored = subject
+	if cfg.SubjectTransform != nil {
+		trans, err := server.NewSubjectTransform(cfg.SubjectTransform.Source, cfg.SubjectTransform.Destination)
+		if err != nil {
+			res.Error = fmt.Sprintf("invalid subject transform: %v", err)
+			return res
+		}
+
+		// subjects not matching the transform source are stored unchanged
+		stored, err := trans.Match(subject)
+		if err == nil {
+			res.Stored = stored
+		}
 	}
-	if c.fExpression != "" {
-		opts = append(opts, jsm.StreamQueryExpression(c.fExpression))
+
+	trans, err := server.NewSubjectTransform(cfg.RePublish.Source, cfg.RePublish.Destination)
+	if err != nil {
+		res.Error = fmt.Sprintf("invalid republish mapping: %v", err)
+		return res
 	}
-	if c.fLeader != "" {
-		opts = append(opts, jsm.StreamQueryLeaderServer(c.fLeader))
+
+	res.Republished, err = trans.Match(res.Stored)
+	if err != nil {
+		res.Error = "does not match the republish source"
 	}
 
-	found, err := c.mgr.QueryStreams(opts...)
+	return res
+}
+
+func (c *streamCmd) republishTestAction(_ *fisk.ParseContext) error {
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
 	if err != nil {
 		return err
 	}
 
-	if len(found) > 0 {
-		balancer, err := balancer.New(c.mgr.NatsConn(), api.NewDefaultLogger(api.InfoLevel))
+	cfg := stream.Configuration()
+	if cfg.RePublish == nil {
+		return fmt.Errorf("stream %s does not republish messages", stream.Name())
+	}
+
+	var results []streamRepublishTestResult
+	failed := 0
+	for _, subj := range c.republishSubjects {
+		res := republishSubject(cfg, subj)
+		if res.Error != "" {
+			failed++
+		}
+		results = append(results, res)
+	}
+
+	if c.json {
+		err := iu.PrintJSON(results)
 		if err != nil {
 			return err
 		}
+	} else {
+		source := cfg.RePublish.Source
+		if source == "" {
+			source = ">"
+		}
 
-		balanced, err := balancer.BalanceStreams(found)
-		if err != nil {
-			return fmt.Errorf("failed to balance streams: %s", err)
+		table := iu.NewTableWriterf(opts(), "Republishing from %s to %s", source, cfg.RePublish.Destination)
+		table.AddHeaders("Subject", "Stored Subject", "Republished Subject", "Problem")
+		for _, res := range results {
+			table.AddRow(res.Subject, res.Stored, res.Republished, res.Error)
 		}
-		fmt.Printf("Balanced %d streams.\n", balanced)
+		fmt.Println(table.Render())
+	}
 
+	if failed > 0 {
+		return fmt.Errorf("%d of %d subjects would not be republished", failed, len(results))
 	}
 
 	return nil
 }
 
-func (c *streamCmd) leaderStandDown(_ *fisk.ParseContext) error {
+func (c *streamCmd) detectGaps(_ *fisk.ParseContext) error {
 	c.connectAndAskStream()
 
 	stream, err := c.loadStream(c.stream)
@@ -1007,244 +1748,1614 @@ func (c *streamCmd) leaderStandDown(_ *fisk.ParseContext) error {
 		return err
 	}
 
-	if info.Cluster == nil || len(info.Cluster.Replicas) == 0 {
-		return fmt.Errorf("stream %q is not clustered", stream.Name())
+	if info.State.NumDeleted == 0 {
+		if c.json {
+			fmt.Println("{}")
+		}
+
+		fmt.Printf("No deleted messages in %s\n", c.stream)
+		return nil
 	}
 
-	leader := info.Cluster.Leader
-	if leader == "" && !c.force {
-		return fmt.Errorf("stream %q has no current leader", stream.Name())
-	} else if leader == "" {
-		leader = "<unknown>"
+	if !c.force {
+		fmt.Println("WARNING: Detecting gaps in a stream consumes the entire stream and can be resource intensive on the Server, Client and Network.")
+		fmt.Println()
+		ok, err := askConfirmation(fmt.Sprintf("Really detect gaps in stream %s with %s messages and %s bytes", c.stream, humanize.Comma(int64(info.State.Msgs)), humanize.IBytes(info.State.Bytes)), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
 	}
 
-	var p *api.Placement
-	if c.placementPreferred != "" {
-		err = iu.RequireAPILevel(c.mgr, 1, "placement hints during step-down requires NATS Server 2.11")
-		if err != nil {
-			return err
+	if c.json {
+		c.showProgress = false
+	}
+
+	var progbar progress.Writer
+	var tracker *progress.Tracker
+
+	var gaps [][2]uint64
+	var cnt int
+
+	progressCb := func(seq uint64, pending uint64) {
+		if !c.showProgress {
+			return
+		}
+		if tracker == nil {
+			progbar, tracker, err = iu.NewProgress(opts(), &progress.Tracker{
+				Total: int64(pending),
+			})
 		}
+		cnt++
 
-		p = &api.Placement{Preferred: c.placementPreferred}
+		tracker.SetValue(int64(seq))
+
+		if pending == 0 {
+			tracker.SetValue(tracker.Total)
+			tracker.MarkAsDone()
+		}
 	}
 
-	log.Printf("Requesting leader step down of %q for stream %q in a %d peer cluster group", leader, stream.Name(), len(info.Cluster.Replicas)+1)
-	err = stream.LeaderStepDown(p)
+	gapCb := func(start, end uint64) {
+		gaps = append(gaps, [2]uint64{start, end})
+	}
+
+	err = stream.DetectGaps(ctx, progressCb, gapCb)
+	if tracker != nil {
+		time.Sleep(250 * time.Millisecond) // let it draw
+		progbar.Stop()
+		fmt.Println()
+	}
 	if err != nil {
 		return err
 	}
 
-	ctr := 0
-	start := time.Now()
-	for range time.NewTicker(500 * time.Millisecond).C {
-		if ctr == 10 {
-			return fmt.Errorf("stream %q did not elect a new leader in time", stream.Name())
+	if c.json {
+		iu.PrintJSON(gaps)
+		return nil
+	}
+
+	if len(gaps) == 0 {
+		fmt.Printf("No deleted messages in %s\n", c.stream)
+		return nil
+	}
+
+	var table *iu.Table
+	if len(gaps) == 1 {
+		table = iu.NewTableWriterf(opts(), "1 gap found in Stream %s", c.stream)
+	} else {
+		table = iu.NewTableWriterf(opts(), "%s gaps found in Stream %s", f(len(gaps)), c.stream)
+	}
+
+	table.AddHeaders("First Message", "Last Message")
+	for _, gap := range gaps {
+		table.AddRow(f(gap[0]), f(gap[1]))
+	}
+	fmt.Println(table.Render())
+
+	return nil
+}
+
+// streamSubjectsToken marks the position to resume listing subjects from
+type streamSubjectsToken struct {
+	Filter int `json:"f"`
+	Offset int `json:"o"`
+}
+
+func (t streamSubjectsToken) String() string {
+	j, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(j)
+}
+
+func parseStreamSubjectsToken(token string) (streamSubjectsToken, error) {
+	var t streamSubjectsToken
+	if token == "" {
+		return t, nil
+	}
+
+	j, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return t, fmt.Errorf("invalid page token")
+	}
+
+	err = json.Unmarshal(j, &t)
+	if err != nil || t.Filter < 0 || t.Offset < 0 {
+		return t, fmt.Errorf("invalid page token")
+	}
+
+	return t, nil
+}
+
+func (c *streamCmd) streamSubjectFilters() []string {
+	filters := c.subjectsFilters
+	if len(filters) == 0 || (c.filterSubject != "" && c.filterSubject != ">") {
+		filters = append(filters, c.filterSubject)
+	}
+
+	for i, f := range filters {
+		if f == "" {
+			filters[i] = ">"
+		}
+	}
+
+	return filters
+}
+
+// eachStreamSubject walks the subjects matching filters in name order one server page at a time, subjects
+// matching more than one filter are reported once. cb receives the token to resume after the subject and
+// can stop the walk by returning false
+func (c *streamCmd) eachStreamSubject(stream *jsm.Stream, filters []string, start streamSubjectsToken, cb func(subject string, count uint64, next streamSubjectsToken) bool) error {
+	for fi := start.Filter; fi < len(filters); fi++ {
+		offset := 0
+		if fi == start.Filter {
+			offset = start.Offset
+		}
+
+		for {
+			nfo, err := stream.Information(api.JSApiStreamInfoRequest{
+				JSApiIterableRequest: api.JSApiIterableRequest{Offset: offset},
+				SubjectsFilter:       filters[fi],
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(nfo.State.Subjects) == 0 {
+				break
+			}
+
+			names := make([]string, 0, len(nfo.State.Subjects))
+			for subj := range nfo.State.Subjects {
+				names = append(names, subj)
+			}
+			sort.Strings(names)
+
+			for i, subj := range names {
+				count := nfo.State.Subjects[subj]
+				if c.subjectsMinMsgs > 0 && count < c.subjectsMinMsgs {
+					continue
+				}
+				if c.subjectsMaxMsgs > 0 && count > c.subjectsMaxMsgs {
+					continue
+				}
+
+				seen := false
+				for _, earlier := range filters[:fi] {
+					if jsm.SubjectIsSubsetMatch(subj, earlier) {
+						seen = true
+						break
+					}
+				}
+				if seen {
+					continue
+				}
+
+				if !cb(subj, count, streamSubjectsToken{Filter: fi, Offset: offset + i + 1}) {
+					return nil
+				}
+			}
+
+			offset += len(names)
+		}
+	}
+
+	return nil
+}
+
+func (c *streamCmd) subjectsPage(stream *jsm.Stream, filters []string) error {
+	start, err := parseStreamSubjectsToken(c.subjectsPageToken)
+	if err != nil {
+		return err
+	}
+
+	type subjectCount struct {
+		Subject  string `json:"subject"`
+		Messages uint64 `json:"messages"`
+	}
+
+	page := []subjectCount{}
+	var next string
+
+	pageSize := c.subjectsPageSize
+	if pageSize <= 0 {
+		pageSize = math.MaxInt
+	}
+
+	var last streamSubjectsToken
+	err = c.eachStreamSubject(stream, filters, start, func(subject string, count uint64, resume streamSubjectsToken) bool {
+		// one more than the page size is fetched to know if another page exists
+		if len(page) == pageSize {
+			next = last.String()
+			return false
+		}
+
+		page = append(page, subjectCount{Subject: subject, Messages: count})
+		last = resume
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return iu.PrintJSON(map[string]any{"subjects": page, "next": next})
+	}
+
+	if c.listNames {
+		for _, s := range page {
+			fmt.Println(s.Subject)
+		}
+	} else if len(page) > 0 {
+		table := iu.NewTableWriterf(opts(), "%s Subjects in stream %s", f(len(page)), stream.Name())
+		table.AddHeaders("Subject", "Count")
+		for _, s := range page {
+			table.AddRow(s.Subject, f(s.Messages))
+		}
+		fmt.Println(table.Render())
+	} else {
+		fmt.Printf("No subjects found matching %s\n", strings.Join(filters, ", "))
+	}
+
+	if next != "" {
+		fmt.Println()
+		fmt.Printf("Next page token: %s\n", next)
+	}
+
+	return nil
+}
+
+// subjectsJSON writes the subjects as a JSON object while walking the server pages rather than holding all in memory
+func (c *streamCmd) subjectsJSON(stream *jsm.Stream, filters []string) error {
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	var cnt int
+	var werr error
+
+	fmt.Fprint(w, "{")
+	err := c.eachStreamSubject(stream, filters, streamSubjectsToken{}, func(subject string, count uint64, _ streamSubjectsToken) bool {
+		key, err := json.Marshal(subject)
+		if err != nil {
+			werr = err
+			return false
+		}
+
+		if cnt > 0 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintf(w, "\n  %s: %d", key, count)
+		cnt++
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if werr != nil {
+		return werr
+	}
+
+	if cnt > 0 {
+		fmt.Fprint(w, "\n")
+	}
+	fmt.Fprintln(w, "}")
+
+	return nil
+}
+
+func (c *streamCmd) subjectsAction(_ *fisk.ParseContext) (err error) {
+	asked := c.connectAndAskStream()
+
+	if c.subjectsMaxMsgs > 0 && c.subjectsMinMsgs > c.subjectsMaxMsgs {
+		return fmt.Errorf("--min-msgs cannot be larger than --max-msgs")
+	}
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	filters := c.streamSubjectFilters()
+
+	if c.subjectsPageSize > 0 || c.subjectsPageToken != "" {
+		if c.subjectsSortSet || c.reportSortReverse {
+			return fmt.Errorf("paged results are always sorted by subject name")
+		}
+
+		return c.subjectsPage(stream, filters)
+	}
+
+	if c.json {
+		return c.subjectsJSON(stream, filters)
+	}
+
+	subs := make(map[string]uint64)
+	err = c.eachStreamSubject(stream, filters, streamSubjectsToken{}, func(subject string, count uint64, _ streamSubjectsToken) bool {
+		subs[subject] = count
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if asked {
+		fmt.Println()
+	}
+
+	if len(subs) == 0 {
+		fmt.Printf("No subjects found matching %s\n", strings.Join(filters, ", "))
+		return nil
+	}
+
+	var longest int
+	var most uint64
+	var names []string
+
+	for s, c := range subs {
+		names = append(names, s)
+		if len(s) > longest {
+			longest = len(s)
+		}
+		if c > most {
+			most = c
+		}
+	}
+
+	cols := 1
+	countWidth := len(f(most))
+	table := iu.NewTableWriterf(opts(), "%d Subjects in stream %s", len(names), c.stream)
+
+	switch {
+	case longest+countWidth < 20:
+		cols = 3
+		table.AddHeaders("Subject", "Count", "Subject", "Count", "Subject", "Count")
+	case longest+countWidth < 30:
+		cols = 2
+		table.AddHeaders("Subject", "Count", "Subject", "Count")
+	default:
+		table.AddHeaders("Subject", "Count")
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if c.reportSort == "name" || c.reportSort == "subjects" {
+			return c.boolReverse(names[i] < names[j])
+		} else {
+			return c.boolReverse(subs[names[i]] < subs[names[j]])
+		}
+	})
+
+	if c.listNames {
+		for _, n := range names {
+			fmt.Println(n)
+		}
+		return
+	}
+
+	comma := func(i uint64) string {
+		if i == 0 {
+			return ""
+		}
+		return f(i)
+	}
+
+	iu.SliceGroups(names, cols, func(g []string) {
+		if cols == 1 {
+			table.AddRow(g[0], comma(subs[g[0]]))
+		} else if cols == 2 {
+			table.AddRow(g[0], comma(subs[g[0]]), g[1], comma(subs[g[1]]))
+		} else {
+			table.AddRow(g[0], comma(subs[g[0]]), g[1], comma(subs[g[1]]), g[2], comma(subs[g[2]]))
+		}
+	})
+
+	fmt.Println(table.Render())
+
+	return nil
+}
+
+func (c *streamCmd) parseLimitStrings(_ *fisk.ParseContext) (err error) {
+	if c.maxBytesLimitString != "" {
+		c.maxBytesLimit, err = iu.ParseStringAsBytes(c.maxBytesLimitString, 32)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.maxMsgSizeString != "" {
+		c.maxMsgSize, err = iu.ParseStringAsBytes(c.maxMsgSizeString, 32)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *streamCmd) findAction(_ *fisk.ParseContext) (err error) {
+	c.nc, c.mgr, err = prepareHelper("", natsOpts()...)
+	if err != nil {
+		return fmt.Errorf("setup failed: %v", err)
+	}
+
+	var opts []jsm.StreamQueryOpt
+	if c.fServer != "" {
+		opts = append(opts, jsm.StreamQueryServerName(c.fServer))
+	}
+	if c.fCluster != "" {
+		opts = append(opts, jsm.StreamQueryClusterName(c.fCluster))
+	}
+	if c.fEmpty {
+		opts = append(opts, jsm.StreamQueryWithoutMessages())
+	}
+	if c.fIdle > 0 {
+		opts = append(opts, jsm.StreamQueryIdleLongerThan(c.fIdle))
+	}
+	if c.fCreated > 0 {
+		opts = append(opts, jsm.StreamQueryOlderThan(c.fCreated))
+	}
+	if c.fConsumers >= 0 {
+		opts = append(opts, jsm.StreamQueryFewerConsumersThan(uint(c.fConsumers)))
+	}
+	if c.fInvert {
+		opts = append(opts, jsm.StreamQueryInvert())
+	}
+	if c.filterSubject != "" {
+		opts = append(opts, jsm.StreamQuerySubjectWildcard(c.filterSubject))
+	}
+	if c.fSourcedSet {
+		opts = append(opts, jsm.StreamQueryIsSourced())
+	}
+	if c.fMirroredSet {
+		opts = append(opts, jsm.StreamQueryIsMirror())
+	}
+	if c.fReplicas > 0 {
+		opts = append(opts, jsm.StreamQueryReplicas(c.fReplicas))
+	}
+	if c.fExpression != "" {
+		expression, err := expandExpressionLiterals(c.fExpression)
+		if err != nil {
+			return fmt.Errorf("invalid expression: %w", err)
+		}
+		opts = append(opts, jsm.StreamQueryExpression(expression))
+	}
+	if c.fLeader != "" {
+		opts = append(opts, jsm.StreamQueryLeaderServer(c.fLeader))
+	}
+	if c.apiLevel > 0 {
+		opts = append(opts, jsm.StreamQueryApiLevelMin(c.apiLevel))
+	}
+
+	found, err := c.mgr.QueryStreams(opts...)
+	if err != nil {
+		return err
+	}
+
+	out := ""
+	switch {
+	case c.listNames:
+		out = c.renderStreamsAsList(found, nil)
+	default:
+		out, err = c.renderStreamsAsTable(found, nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(out)
+
+	return nil
+}
+
+var (
+	exprDurationLiteral = regexp.MustCompile(`(^|[^\w.])(\d+(?:\.\d+)?(?:ns|us|ms|s|m|h|d|w|y))\b`)
+	exprSizeLiteral     = regexp.MustCompile(`(^|[^\w.])(\d+(?:\.\d+)?[KMGTP]i?B)\b`)
+	exprQuoted          = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|'(?:[^'\\\\]|\\\\.)*'|`[^`]*`")
+)
+
+// expandExpressionLiterals replaces duration and size literals outside of quoted strings with duration values and bytes, durations
+// in the expression environment are strings that have to be compared using duration()
+func expandExpressionLiterals(e string) (string, error) {
+	var err error
+
+	expand := func(part string) string {
+		part = exprDurationLiteral.ReplaceAllStringFunc(part, func(m string) string {
+			sm := exprDurationLiteral.FindStringSubmatch(m)
+			d, perr := fisk.ParseDuration(sm[2])
+			if perr != nil {
+				err = perr
+				return m
+			}
+			return sm[1] + "duration(" + strconv.Quote(d.String()) + ")"
+		})
+
+		return exprSizeLiteral.ReplaceAllStringFunc(part, func(m string) string {
+			sm := exprSizeLiteral.FindStringSubmatch(m)
+			b, perr := humanize.ParseBytes(sm[2])
+			if perr != nil {
+				err = perr
+				return m
+			}
+			return sm[1] + strconv.FormatUint(b, 10)
+		})
+	}
+
+	var res strings.Builder
+	last := 0
+	for _, loc := range exprQuoted.FindAllStringIndex(e, -1) {
+		res.WriteString(expand(e[last:loc[0]]))
+		res.WriteString(e[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	res.WriteString(expand(e[last:]))
+
+	return res.String(), err
+}
+
+func (c *streamCmd) loadStream(stream string) (*jsm.Stream, error) {
+	if c.selectedStream != nil && c.selectedStream.Name() == stream {
+		return c.selectedStream, nil
+	}
+
+	return c.mgr.LoadStream(stream)
+}
+
+func (c *streamCmd) balanceAction(_ *fisk.ParseContext) error {
+	var err error
+
+	c.nc, c.mgr, err = prepareHelper("", natsOpts()...)
+	if err != nil {
+		return fmt.Errorf("setup failed: %v", err)
+	}
+
+	var opts []jsm.StreamQueryOpt
+	if c.fServer != "" {
+		opts = append(opts, jsm.StreamQueryServerName(c.fServer))
+	}
+	if c.fCluster != "" {
+		opts = append(opts, jsm.StreamQueryClusterName(c.fCluster))
+	}
+	if c.fEmpty {
+		opts = append(opts, jsm.StreamQueryWithoutMessages())
+	}
+	if c.fIdle > 0 {
+		opts = append(opts, jsm.StreamQueryIdleLongerThan(c.fIdle))
+	}
+	if c.fCreated > 0 {
+		opts = append(opts, jsm.StreamQueryOlderThan(c.fCreated))
+	}
+	if c.fConsumers >= 0 {
+		opts = append(opts, jsm.StreamQueryFewerConsumersThan(uint(c.fConsumers)))
+	}
+	if c.fInvert {
+		opts = append(opts, jsm.StreamQueryInvert())
+	}
+	if c.filterSubject != "" {
+		opts = append(opts, jsm.StreamQuerySubjectWildcard(c.filterSubject))
+	}
+	if c.fSourcedSet {
+		opts = append(opts, jsm.StreamQueryIsSourced())
+	}
+	if c.fMirroredSet {
+		opts = append(opts, jsm.StreamQueryIsMirror())
+	}
+	if c.fReplicas > 0 {
+		opts = append(opts, jsm.StreamQueryReplicas(c.fReplicas))
+	}
+	if c.fExpression != "" {
+		expression, err := expandExpressionLiterals(c.fExpression)
+		if err != nil {
+			return fmt.Errorf("invalid expression: %w", err)
+		}
+		opts = append(opts, jsm.StreamQueryExpression(expression))
+	}
+	if c.fLeader != "" {
+		opts = append(opts, jsm.StreamQueryLeaderServer(c.fLeader))
+	}
+
+	found, err := c.mgr.QueryStreams(opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(found) > 0 {
+		balancer, err := balancer.New(c.mgr.NatsConn(), api.NewDefaultLogger(api.InfoLevel))
+		if err != nil {
+			return err
+		}
+
+		balanced, err := balancer.BalanceStreams(found)
+		if err != nil {
+			return fmt.Errorf("failed to balance streams: %s", err)
+		}
+		fmt.Printf("Balanced %d streams.\n", balanced)
+
+	}
+
+	return nil
+}
+
+func (c *streamCmd) leaderStandDown(_ *fisk.ParseContext) error {
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	info, err := stream.LatestInformation()
+	if err != nil {
+		return err
+	}
+
+	if info.Cluster == nil || len(info.Cluster.Replicas) == 0 {
+		return fmt.Errorf("stream %q is not clustered", stream.Name())
+	}
+
+	leader := info.Cluster.Leader
+	if leader == "" && !c.force {
+		return fmt.Errorf("stream %q has no current leader", stream.Name())
+	} else if leader == "" {
+		leader = "<unknown>"
+	}
+
+	var p *api.Placement
+	if c.placementPreferred != "" {
+		err = iu.RequireAPILevel(c.mgr, 1, "placement hints during step-down requires NATS Server 2.11")
+		if err != nil {
+			return err
+		}
+
+		p = &api.Placement{Preferred: c.placementPreferred}
+	}
+
+	log.Printf("Requesting leader step down of %q for stream %q in a %d peer cluster group", leader, stream.Name(), len(info.Cluster.Replicas)+1)
+	err = stream.LeaderStepDown(p)
+	if err != nil {
+		return err
+	}
+
+	ctr := 0
+	start := time.Now()
+	for range time.NewTicker(500 * time.Millisecond).C {
+		if ctr == 10 {
+			return fmt.Errorf("stream %q did not elect a new leader in time", stream.Name())
+		}
+		ctr++
+
+		info, err = stream.Information()
+		if err != nil {
+			log.Printf("Failed to retrieve Stream State: %s", err)
+			continue
+		}
+
+		if info.Cluster == nil || info.Cluster.Leader == "" {
+			log.Printf("No leader elected")
+			continue
+		}
+
+		if info.Cluster.Leader != leader {
+			log.Printf("New leader elected %q", info.Cluster.Leader)
+			break
+		}
+	}
+
+	if info.Cluster.Leader == leader {
+		log.Printf("Leader did not change after %s", time.Since(start).Round(time.Millisecond))
+	}
+
+	fmt.Println()
+	return c.showStream(stream)
+}
+
+func (c *streamCmd) removePeer(_ *fisk.ParseContext) error {
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	info, err := stream.Information()
+	if err != nil {
+		return err
+	}
+
+	if info.Cluster == nil {
+		return fmt.Errorf("stream %q is not clustered", stream.Name())
+	}
+
+	peerNames := []string{info.Cluster.Leader}
+	for _, r := range info.Cluster.Replicas {
+		peerNames = append(peerNames, r.Name)
+	}
+
+	if len(peerNames) == 1 && !c.force {
+		return fmt.Errorf("removing the only peer on a stream will result in data loss, use --force to force")
+	}
+
+	if c.peerName == "" {
+		err = iu.AskOne(&survey.Select{
+			Message: "Select a Peer",
+			Options: peerNames,
+		}, &c.peerName)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Removing peer %q", c.peerName)
+
+	err = stream.RemoveRAFTPeer(c.peerName)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Requested removal of peer %q", c.peerName)
+
+	return nil
+}
+
+func (c *streamCmd) viewShowMsg(msg *nats.Msg) {
+	if c.vwRaw {
+		fmt.Println(string(msg.Data))
+		return
+	}
+
+	stream := ""
+	meta, err := jsm.ParseJSMsgMetadata(msg)
+	if err == nil {
+		stream = meta.Stream()
+		fmt.Printf("[%d] Subject: %s Received: %s\n", meta.StreamSequence(), msg.Subject, f(meta.TimeStamp()))
+	} else {
+		fmt.Printf("Subject: %s Reply: %s\n", msg.Subject, msg.Reply)
+	}
+
+	if len(msg.Header) > 0 {
+		fmt.Println()
+		for k, vs := range msg.Header {
+			for _, v := range vs {
+				if k == "Nats-Stream-Source" {
+					v = strings.ReplaceAll(v, "\f", "\u240A")
+				}
+
+				if k == "Nats-Subject" || k == "Nats-Stream" || k == "Nats-Sequence" || k == "Nats-Time-Stamp" || k == "Nats-Num-Pending" || k == "Nats-Last-Sequence" || k == "Nats-UpTo-Sequnce" {
+					continue
+				}
+
+				fmt.Printf("  %s: %s\n", k, v)
+			}
+		}
+	}
+
+	outPutMSGBody(msg.Data, c.vwTranslate, msg.Subject, stream)
+}
+
+// viewTailStart finds the sequence to start at so that the last count messages matching the filter are shown
+func (c *streamCmd) viewTailStart(js jetstream.JetStream, stream *jsm.Stream, count int) (uint64, uint64, error) {
+	return streamTailStart(js, stream, c.vwSubject, count)
+}
+
+// streamTailStart finds the sequence to start at so that the last count messages matching filter are read, skip is the
+// number of messages to discard after the start sequence
+func streamTailStart(js jetstream.JetStream, stream *jsm.Stream, filter string, count int) (uint64, uint64, error) {
+	state, err := stream.State()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if count <= 0 || state.Msgs == 0 {
+		return state.LastSeq + 1, 0, nil
+	}
+
+	// without a filter or deleted messages the sequences can be calculated, else widen the window until enough messages are found
+	window := uint64(count)
+	for {
+		start := state.FirstSeq
+		if state.LastSeq >= window && state.LastSeq-window+1 > start {
+			start = state.LastSeq - window + 1
+		}
+
+		if filter == "" && state.NumDeleted == 0 {
+			return start, 0, nil
+		}
+
+		var filters []string
+		if filter != "" {
+			filters = []string{filter}
+		}
+
+		cons, err := js.OrderedConsumer(ctx, stream.Name(), jetstream.OrderedConsumerConfig{
+			FilterSubjects: filters,
+			DeliverPolicy:  jetstream.DeliverByStartSequencePolicy,
+			OptStartSeq:    start,
+			HeadersOnly:    true,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+
+		nfo, err := cons.Info(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if nfo.NumPending >= uint64(count) || start == state.FirstSeq {
+			var skip uint64
+			if nfo.NumPending > uint64(count) {
+				skip = nfo.NumPending - uint64(count)
+			}
+
+			return start, skip, nil
+		}
+
+		window *= 4
+	}
+}
+
+// viewFollow shows the tail of the stream and then keeps showing new messages until interrupted
+func (c *streamCmd) viewFollow() error {
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ocfg := jetstream.OrderedConsumerConfig{}
+	if c.vwSubject != "" {
+		ocfg.FilterSubjects = []string{c.vwSubject}
+	}
+
+	var skip uint64
+	switch {
+	case c.vwStartDelta > 0:
+		start := time.Now().Add(-c.vwStartDelta)
+		ocfg.DeliverPolicy = jetstream.DeliverByStartTimePolicy
+		ocfg.OptStartTime = &start
+	case c.vwStartId > 0:
+		ocfg.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		ocfg.OptStartSeq = uint64(c.vwStartId)
+	default:
+		ocfg.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		ocfg.OptStartSeq, skip, err = c.viewTailStart(js, stream, c.vwPageSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), ocfg)
+	if err != nil {
+		return err
+	}
+
+	iter, err := cons.Messages()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		iter.Stop()
+	}()
+
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				return nil
+			}
+			return err
+		}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		c.viewShowMsg(&nats.Msg{Subject: msg.Subject(), Reply: msg.Reply(), Header: msg.Headers(), Data: msg.Data()})
+	}
+}
+
+// viewOrdered reads the stream using an ordered consumer that recreates itself on gaps, paging interactively
+// on a terminal and otherwise showing all messages until the end of the stream is reached
+func (c *streamCmd) viewOrdered() error {
+	if c.vwPageSize < 1 {
+		return fmt.Errorf("page size must be at least 1")
+	}
+
+	if c.vwPageSize > 25 {
+		log.Printf("Page size is limited to 25, setting to 25...")
+		c.vwPageSize = 25
+	}
+
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ocfg := jetstream.OrderedConsumerConfig{}
+	if c.vwSubject != "" {
+		ocfg.FilterSubjects = []string{c.vwSubject}
+	}
+
+	switch {
+	case c.vwStartDelta > 0:
+		start := time.Now().Add(-c.vwStartDelta)
+		ocfg.DeliverPolicy = jetstream.DeliverByStartTimePolicy
+		ocfg.OptStartTime = &start
+	case c.vwStartId > 0:
+		ocfg.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		ocfg.OptStartSeq = uint64(c.vwStartId)
+	}
+
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), ocfg)
+	if err != nil {
+		return err
+	}
+
+	nfo, err := cons.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if nfo.NumPending == 0 {
+		log.Println("Reached apparent end of data")
+		return nil
+	}
+
+	iter, err := cons.Messages()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		iter.Stop()
+	}()
+
+	interactive := iu.IsTerminal()
+
+	var shown int
+	var lastSeq uint64
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				return nil
+			}
+			return err
+		}
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return err
+		}
+
+		// the ordered consumer might replay messages when it recreates itself
+		if meta.Sequence.Stream <= lastSeq {
+			continue
+		}
+		lastSeq = meta.Sequence.Stream
+		shown++
+
+		c.viewShowMsg(&nats.Msg{Subject: msg.Subject(), Reply: msg.Reply(), Header: msg.Headers(), Data: msg.Data()})
+
+		if meta.NumPending == 0 {
+			log.Println("Reached apparent end of data")
+			return nil
+		}
+
+		if interactive && shown%c.vwPageSize == 0 {
+			next := false
+			iu.AskOne(&survey.Confirm{Message: "Next Page?", Default: true}, &next)
+			if !next {
+				return nil
+			}
+		}
+	}
+}
+
+func (c *streamCmd) viewAction(_ *fisk.ParseContext) error {
+	if c.vwFollow {
+		return c.viewFollow()
+	}
+
+	if c.vwOrdered {
+		return c.viewOrdered()
+	}
+
+	if !iu.IsTerminal() {
+		return fmt.Errorf("interactive stream paging requires a valid terminal")
+	}
+
+	if c.vwPageSize > 25 {
+		log.Printf("Page size is limited to 25, setting to 25...")
+		c.vwPageSize = 25
+	}
+
+	c.connectAndAskStream()
+
+	str, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	pops := []jsm.PagerOption{
+		jsm.PagerSize(c.vwPageSize),
+	}
+
+	switch {
+	case c.vwStartDelta > 0:
+		pops = append(pops, jsm.PagerStartDelta(c.vwStartDelta))
+	case c.vwStartId > 0:
+		pops = append(pops, jsm.PagerStartId(c.vwStartId))
+	}
+
+	if c.vwSubject != "" {
+		pops = append(pops, jsm.PagerFilterSubject(c.vwSubject))
+	}
+
+	pgr, err := str.PageContents(pops...)
+	if err != nil {
+		return err
+	}
+	defer pgr.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigs:
+			cancel()
+		}
+	}()
+
+	shouldTerminate := false
+
+	for {
+		msg, last, err := pgr.NextMsg(ctx)
+		if err != nil {
+			if !last {
+				return err
+			}
+			// later we know we reached final last after showing the final message
+			shouldTerminate = true
+		}
+
+		switch {
+		case msg == nil:
+			shouldTerminate = true
+		default:
+			c.viewShowMsg(msg)
+		}
+
+		if shouldTerminate {
+			log.Println("Reached apparent end of data")
+			return nil
+		}
+
+		if last {
+			next := false
+			iu.AskOne(&survey.Confirm{Message: "Next Page?", Default: true}, &next)
+			if !next {
+				return nil
+			}
+		}
+	}
+}
+
+func (c *streamCmd) sealAction(_ *fisk.ParseContext) error {
+	c.connectAndAskStream()
+
+	if c.backupDirectory != "" {
+		_, err := os.Stat(c.backupDirectory)
+		if err == nil {
+			return fmt.Errorf("backup directory %s already exists", c.backupDirectory)
+		}
+	}
+
+	if !c.force {
+		msg := fmt.Sprintf("Really seal Stream %s, sealed streams can not be unsealed or modified", c.stream)
+		if c.backupDirectory != "" {
+			msg = fmt.Sprintf("Really back up Stream %s to %s and seal it, sealed streams can not be unsealed or modified", c.stream, c.backupDirectory)
+		}
+
+		ok, err := askConfirmation(msg, false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	stream, err := c.loadStream(c.stream)
+	fisk.FatalIfError(err, "could not seal Stream")
+
+	if c.backupDirectory != "" {
+		err = backupStream(stream, c.showProgress, true, false, c.backupDirectory, 0, 0)
+		fisk.FatalIfError(err, "backup failed, Stream %s was not sealed", c.stream)
+		fmt.Println()
+	}
+
+	err = stream.Seal()
+	fisk.FatalIfError(err, "could not seal Stream")
+
+	return c.showStream(stream)
+}
+
+// streamExportRecord is a message as exported by stream export
+type streamExportRecord struct {
+	Stream   string      `json:"stream,omitempty"`
+	Sequence uint64      `json:"seq"`
+	Time     time.Time   `json:"time"`
+	Subject  string      `json:"subject"`
+	Headers  nats.Header `json:"headers,omitempty"`
+	Data     any         `json:"data"`
+}
+
+// parseExportTime parses a RFC3339 time or a duration relative to now
+func parseExportTime(v string) (time.Time, error) {
+	ts, err := time.Parse(time.RFC3339, v)
+	if err == nil {
+		return ts, nil
+	}
+
+	d, err := fisk.ParseDuration(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a duration or RFC3339 time", v)
+	}
+
+	return time.Now().Add(-d), nil
+}
+
+func (c *streamCmd) exportAction(_ *fisk.ParseContext) error {
+	var since, until time.Time
+	var err error
+
+	if c.exportSince != "" {
+		since, err = parseExportTime(c.exportSince)
+		if err != nil {
+			return err
+		}
+	}
+	if c.exportUntil != "" {
+		until, err = parseExportTime(c.exportUntil)
+		if err != nil {
+			return err
+		}
+	}
+	if c.exportStartSeq > 0 && !since.IsZero() {
+		return fmt.Errorf("--start-seq and --since cannot be used together")
+	}
+
+	if c.exportFormat == "" {
+		c.exportFormat = "jsonl"
+		if strings.HasSuffix(c.exportOutput, ".csv") {
+			c.exportFormat = "csv"
+		}
+	}
+
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	nfo, err := stream.LatestInformation()
+	if err != nil {
+		return err
+	}
+
+	// messages arriving during the export are not exported
+	endSeq := nfo.State.LastSeq
+	if c.exportEndSeq > 0 {
+		endSeq = min(endSeq, c.exportEndSeq)
+	}
+
+	var out io.Writer = os.Stdout
+	if c.exportOutput != "-" {
+		fh, err := os.Create(c.exportOutput)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		out = fh
+	} else {
+		c.showProgress = false
+	}
+
+	bout := bufio.NewWriter(out)
+	var csvw *csv.Writer
+	if c.exportFormat == "csv" {
+		csvw = csv.NewWriter(bout)
+		err = csvw.Write([]string{"seq", "time", "subject", "headers", "data"})
+		if err != nil {
+			return err
+		}
+	}
+
+	exported, err := c.exportMessages(stream, endSeq, since, until, func(rec *streamExportRecord) error {
+		if csvw == nil {
+			rj, err := json.Marshal(rec)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(bout, string(rj))
+			return err
+		}
+
+		var hdrs string
+		if len(rec.Headers) > 0 {
+			hj, err := json.Marshal(rec.Headers)
+			if err != nil {
+				return err
+			}
+			hdrs = string(hj)
+		}
+
+		data := fmt.Sprint(rec.Data)
+		if raw, ok := rec.Data.(json.RawMessage); ok {
+			data = string(raw)
+		}
+
+		return csvw.Write([]string{strconv.FormatUint(rec.Sequence, 10), rec.Time.Format(time.RFC3339Nano), rec.Subject, hdrs, data})
+	})
+	if err != nil {
+		return err
+	}
+
+	if csvw != nil {
+		csvw.Flush()
+		err = csvw.Error()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = bout.Flush()
+	if err != nil {
+		return err
+	}
+
+	if c.exportOutput != "-" {
+		fmt.Printf("Exported %s messages from %s to %s\n", f(exported), c.stream, c.exportOutput)
+	}
+
+	return nil
+}
+
+// exportMessages reads the messages in the requested range calling cb for every message
+func (c *streamCmd) exportMessages(stream *jsm.Stream, endSeq uint64, since time.Time, until time.Time, cb func(*streamExportRecord) error) (int, error) {
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return 0, err
+	}
+
+	ocfg := jetstream.OrderedConsumerConfig{FilterSubjects: c.exportSubjects}
+	switch {
+	case !since.IsZero():
+		ocfg.DeliverPolicy = jetstream.DeliverByStartTimePolicy
+		ocfg.OptStartTime = &since
+	case c.exportStartSeq > 0:
+		ocfg.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		ocfg.OptStartSeq = c.exportStartSeq
+	}
+
+	if c.exportStartSeq > endSeq {
+		return 0, nil
+	}
+
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), ocfg)
+	if err != nil {
+		return 0, err
+	}
+
+	iter, err := cons.Messages()
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Stop()
+
+	// nothing matched the filters and start position
+	nfo, err := cons.Info(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if nfo.NumPending == 0 && nfo.Delivered.Consumer == 0 {
+		return 0, nil
+	}
+
+	var progbar progress.Writer
+	var tracker *progress.Tracker
+	if c.showProgress {
+		progbar, tracker, err = iu.NewProgress(opts(), &progress.Tracker{Total: int64(nfo.NumPending + nfo.Delivered.Consumer)})
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			tracker.MarkAsDone()
+			time.Sleep(300 * time.Millisecond)
+			progbar.Stop()
+		}()
+	}
+
+	var exported int
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			return exported, err
+		}
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return exported, err
+		}
+
+		if meta.Sequence.Stream > endSeq || (!until.IsZero() && !meta.Timestamp.Before(until)) {
+			return exported, nil
+		}
+
+		rec := &streamExportRecord{
+			Stream:   meta.Stream,
+			Sequence: meta.Sequence.Stream,
+			Time:     meta.Timestamp,
+			Subject:  msg.Subject(),
+			Headers:  msg.Headers(),
+		}
+
+		switch {
+		case c.exportEncoding == "string":
+			rec.Data = string(msg.Data())
+		case c.exportEncoding == "json" && json.Valid(msg.Data()):
+			rec.Data = json.RawMessage(msg.Data())
+		case c.exportEncoding == "json":
+			return exported, fmt.Errorf("message %d does not hold valid JSON data", meta.Sequence.Stream)
+		default:
+			rec.Data = base64.StdEncoding.EncodeToString(msg.Data())
 		}
-		ctr++
 
-		info, err = stream.Information()
+		err = cb(rec)
 		if err != nil {
-			log.Printf("Failed to retrieve Stream State: %s", err)
-			continue
+			return exported, err
 		}
+		exported++
 
-		if info.Cluster == nil || info.Cluster.Leader == "" {
-			log.Printf("No leader elected")
-			continue
+		if tracker != nil {
+			tracker.Increment(1)
 		}
 
-		if info.Cluster.Leader != leader {
-			log.Printf("New leader elected %q", info.Cluster.Leader)
-			break
+		if meta.Sequence.Stream >= endSeq || meta.NumPending == 0 {
+			return exported, nil
 		}
 	}
-
-	if info.Cluster.Leader == leader {
-		log.Printf("Leader did not change after %s", time.Since(start).Round(time.Millisecond))
-	}
-
-	fmt.Println()
-	return c.showStream(stream)
 }
 
-func (c *streamCmd) removePeer(_ *fisk.ParseContext) error {
-	c.connectAndAskStream()
-
-	stream, err := c.loadStream(c.stream)
-	if err != nil {
-		return err
+// importRecord parses a JSONL line produced by stream export into a message
+func (c *streamCmd) importRecord(line []byte, subjects []string) (*nats.Msg, string, uint64, error) {
+	var rec struct {
+		Stream   string          `json:"stream"`
+		Sequence uint64          `json:"seq"`
+		Subject  string          `json:"subject"`
+		Headers  nats.Header     `json:"headers"`
+		Data     json.RawMessage `json:"data"`
 	}
 
-	info, err := stream.Information()
+	err := json.Unmarshal(line, &rec)
 	if err != nil {
-		return err
+		return nil, "", 0, fmt.Errorf("invalid record: %w", err)
 	}
 
-	if info.Cluster == nil {
-		return fmt.Errorf("stream %q is not clustered", stream.Name())
+	if rec.Subject == "" || strings.ContainsAny(rec.Subject, "*> \t") {
+		return nil, "", 0, fmt.Errorf("invalid subject %q", rec.Subject)
 	}
 
-	peerNames := []string{info.Cluster.Leader}
-	for _, r := range info.Cluster.Replicas {
-		peerNames = append(peerNames, r.Name)
+	matched := false
+	for _, subj := range subjects {
+		if jsm.SubjectIsSubsetMatch(rec.Subject, subj) {
+			matched = true
+			break
+		}
 	}
-
-	if len(peerNames) == 1 && !c.force {
-		return fmt.Errorf("removing the only peer on a stream will result in data loss, use --force to force")
+	if !matched {
+		return nil, "", 0, fmt.Errorf("subject %q is not bound to the stream", rec.Subject)
 	}
 
-	if c.peerName == "" {
-		err = iu.AskOne(&survey.Select{
-			Message: "Select a Peer",
-			Options: peerNames,
-		}, &c.peerName)
-		if err != nil {
-			return err
+	msg := nats.NewMsg(rec.Subject)
+	for k, v := range rec.Headers {
+		if !republishHeader(k, true, true) {
+			continue
 		}
+		msg.Header[k] = v
 	}
 
-	log.Printf("Removing peer %q", c.peerName)
+	switch c.exportEncoding {
+	case "json":
+		msg.Data = rec.Data
+	default:
+		var data string
+		err = json.Unmarshal(rec.Data, &data)
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("data is not a string: %w", err)
+		}
 
-	err = stream.RemoveRAFTPeer(c.peerName)
-	if err != nil {
-		return err
+		msg.Data = []byte(data)
+		if c.exportEncoding == "base64" {
+			msg.Data, err = base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return nil, "", 0, fmt.Errorf("invalid base64 data: %w", err)
+			}
+		}
 	}
 
-	log.Printf("Requested removal of peer %q", c.peerName)
-
-	return nil
+	return msg, rec.Stream, rec.Sequence, nil
 }
 
-func (c *streamCmd) viewAction(_ *fisk.ParseContext) error {
-	if !iu.IsTerminal() {
-		return fmt.Errorf("interactive stream paging requires a valid terminal")
-	}
-
-	if c.vwPageSize > 25 {
-		log.Printf("Page size is limited to 25, setting to 25...")
-		c.vwPageSize = 25
-	}
-
+func (c *streamCmd) importAction(_ *fisk.ParseContext) error {
 	c.connectAndAskStream()
 
-	str, err := c.loadStream(c.stream)
+	stream, err := c.loadStream(c.stream)
 	if err != nil {
 		return err
 	}
 
-	pops := []jsm.PagerOption{
-		jsm.PagerSize(c.vwPageSize),
+	if stream.IsMirror() {
+		return fmt.Errorf("cannot import into mirror stream %s", stream.Name())
 	}
 
-	switch {
-	case c.vwStartDelta > 0:
-		pops = append(pops, jsm.PagerStartDelta(c.vwStartDelta))
-	case c.vwStartId > 0:
-		pops = append(pops, jsm.PagerStartId(c.vwStartId))
+	subjects := stream.Subjects()
+	if len(subjects) == 0 {
+		subjects = []string{stream.Name()}
 	}
 
-	if c.vwSubject != "" {
-		pops = append(pops, jsm.PagerFilterSubject(c.vwSubject))
+	var in io.Reader = os.Stdin
+	if c.importFile != "-" {
+		fh, err := os.Open(c.importFile)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		in = fh
 	}
 
-	pgr, err := str.PageContents(pops...)
-	if err != nil {
-		return err
+	var js jetstream.JetStream
+	if !c.importDryRun {
+		_, js, err = prepareJSHelper()
+		if err != nil {
+			return err
+		}
 	}
-	defer pgr.Close()
 
-	ctx, cancel := context.WithCancel(ctx)
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	var interval time.Duration
+	if c.importRate > 0 {
+		interval = time.Second / time.Duration(c.importRate)
+	}
 
-	go func() {
-		select {
-		case <-ctx.Done():
-			return
-		case <-sigs:
-			cancel()
+	const batchSize = 256
+	var futures []jetstream.PubAckFuture
+	var imported, duplicates, invalid, lineNo int
+
+	flush := func() error {
+		for _, fut := range futures {
+			select {
+			case ack := <-fut.Ok():
+				imported++
+				if ack.Duplicate {
+					duplicates++
+				}
+			case err := <-fut.Err():
+				return fmt.Errorf("publishing %s failed: %w", fut.Msg().Subject, err)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		}
-	}()
+		futures = futures[:0]
 
-	shouldTerminate := false
+		return nil
+	}
 
-	for {
-		msg, last, err := pgr.NextMsg(ctx)
-		if err != nil {
-			if !last {
-				return err
-			}
-			// later we know we reached final last after showing the final message
-			shouldTerminate = true
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	next := time.Now()
+
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
 		}
 
-		switch {
-		case msg == nil:
-			shouldTerminate = true
-		case c.vwRaw:
-			fmt.Println(string(msg.Data))
-		default:
-			meta, err := jsm.ParseJSMsgMetadata(msg)
-			if err == nil {
-				fmt.Printf("[%d] Subject: %s Received: %s\n", meta.StreamSequence(), msg.Subject, f(meta.TimeStamp()))
-			} else {
-				fmt.Printf("Subject: %s Reply: %s\n", msg.Subject, msg.Reply)
+		msg, source, seq, err := c.importRecord(line, subjects)
+		if err != nil {
+			if !c.importDryRun {
+				return fmt.Errorf("line %d: %w", lineNo, err)
 			}
 
-			if len(msg.Header) > 0 {
-				fmt.Println()
-				for k, vs := range msg.Header {
-					for _, v := range vs {
-						if k == "Nats-Stream-Source" {
-							v = strings.ReplaceAll(v, "\f", "\u240A")
-						}
+			fmt.Printf("line %d: %v\n", lineNo, err)
+			invalid++
+			continue
+		}
 
-						if k == "Nats-Subject" || k == "Nats-Stream" || k == "Nats-Sequence" || k == "Nats-Time-Stamp" || k == "Nats-Num-Pending" || k == "Nats-Last-Sequence" || k == "Nats-UpTo-Sequnce" {
-							continue
-						}
+		if c.importDryRun {
+			imported++
+			continue
+		}
 
-						fmt.Printf("  %s: %s\n", k, v)
-					}
-				}
+		if c.importMsgID && msg.Header.Get(api.JSMsgId) == "" && seq > 0 {
+			if source == "" {
+				source = stream.Name()
 			}
+			msg.Header.Set(api.JSMsgId, fmt.Sprintf("%s-%d", source, seq))
+		}
 
-			outPutMSGBody(msg.Data, c.vwTranslate, msg.Subject, meta.Stream())
+		if interval > 0 {
+			select {
+			case <-time.After(time.Until(next)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			next = next.Add(interval)
 		}
 
-		if shouldTerminate {
-			log.Println("Reached apparent end of data")
-			return nil
+		fut, err := js.PublishMsgAsync(msg, jetstream.WithExpectStream(stream.Name()))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
 		}
+		futures = append(futures, fut)
 
-		if last {
-			next := false
-			iu.AskOne(&survey.Confirm{Message: "Next Page?", Default: true}, &next)
-			if !next {
-				return nil
+		if len(futures) >= batchSize {
+			err = flush()
+			if err != nil {
+				return err
 			}
 		}
 	}
-}
 
-func (c *streamCmd) sealAction(_ *fisk.ParseContext) error {
-	c.connectAndAskStream()
-
-	if !c.force {
-		ok, err := askConfirmation(fmt.Sprintf("Really seal Stream %s, sealed streams can not be unsealed or modified", c.stream), false)
-		fisk.FatalIfError(err, "could not obtain confirmation")
+	err = scanner.Err()
+	if err != nil {
+		return err
+	}
 
-		if !ok {
-			return nil
+	if c.importDryRun {
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d records are invalid", invalid, invalid+imported)
 		}
+
+		fmt.Printf("%s records are valid for stream %s\n", f(imported), stream.Name())
+		return nil
 	}
 
-	stream, err := c.loadStream(c.stream)
-	fisk.FatalIfError(err, "could not seal Stream")
+	err = flush()
+	if err != nil {
+		return err
+	}
 
-	stream.Seal()
-	fisk.FatalIfError(err, "could not seal Stream")
+	fmt.Printf("Imported %s messages into %s, %s were duplicates\n", f(imported), stream.Name(), f(duplicates))
 
-	return c.showStream(stream)
+	return nil
 }
 
 func (c *streamCmd) restoreAction(_ *fisk.ParseContext) error {
@@ -1252,11 +3363,40 @@ func (c *streamCmd) restoreAction(_ *fisk.ParseContext) error {
 	fisk.FatalIfError(err, "setup failed")
 
 	var bm api.JSApiStreamRestoreRequest
-	bmj, err := os.ReadFile(filepath.Join(c.backupDirectory, "backup.json"))
-	fisk.FatalIfError(err, "restore failed")
+	var bmj []byte
+	var data io.ReadCloser
+	var dataSize int64
+
+	remote := isRemoteBackup(c.backupDirectory)
+	if remote {
+		rb, err := newRemoteBackup(c.backupDirectory, c.downloadRetries)
+		fisk.FatalIfError(err, "restore failed")
+
+		bmj, data, dataSize, err = rb.open(ctx)
+		fisk.FatalIfError(err, "restore failed")
+		defer data.Close()
+	} else {
+		stat, err := os.Stat(c.backupDirectory)
+		if err == nil && !stat.IsDir() {
+			err = fmt.Errorf("%s is not a directory", c.backupDirectory)
+		}
+		fisk.FatalIfError(err, "restore failed")
+
+		bmj, err = os.ReadFile(filepath.Join(c.backupDirectory, "backup.json"))
+		fisk.FatalIfError(err, "restore failed")
+	}
+
 	err = json.Unmarshal(bmj, &bm)
 	fisk.FatalIfError(err, "restore failed")
 
+	transforms, err := parseSubjectTransforms(c.restoreTransforms)
+	if err != nil {
+		return err
+	}
+	if c.restoreTransformData && len(transforms) == 0 {
+		return fmt.Errorf("--transform-data requires --transform")
+	}
+
 	var cfg *api.StreamConfig
 
 	known, err := mgr.IsKnownStream(bm.Config.Name)
@@ -1322,32 +3462,89 @@ func (c *streamCmd) restoreAction(_ *fisk.ParseContext) error {
 		cfg.Replicas = int(c.replicas)
 	}
 
+	if len(transforms) > 0 {
+		err = applyRestoreTransforms(cfg, transforms)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cfg != nil {
 		ropts = append(ropts, jsm.RestoreConfiguration(*cfg))
 	}
 
 	fmt.Printf("Starting restore of Stream %q from file %q\n\n", bm.Config.Name, c.backupDirectory)
 
-	fp, _, err := mgr.RestoreSnapshotFromDirectory(ctx, bm.Config.Name, c.backupDirectory, ropts...)
-	fisk.FatalIfError(err, "restore failed")
-	if c.showProgress {
-		tracker.SetValue(int64(fp.ChunksSent() * uint32(fp.ChunkSize())))
-		time.Sleep(300 * time.Millisecond)
-		progbar.Stop()
+	var took time.Duration
+	if remote {
+		took, err = c.restoreRemote(mgr, bm.Config.Name, bmj, data, dataSize, ropts)
+		fisk.FatalIfError(err, "restore failed")
+	} else {
+		fp, _, err := mgr.RestoreSnapshotFromDirectory(ctx, bm.Config.Name, c.backupDirectory, ropts...)
+		fisk.FatalIfError(err, "restore failed")
+		if c.showProgress {
+			tracker.SetValue(int64(fp.ChunksSent() * uint32(fp.ChunkSize())))
+			time.Sleep(300 * time.Millisecond)
+			progbar.Stop()
+		}
+		took = fp.EndTime().Sub(fp.StartTime())
 	}
 
 	fmt.Println()
-	fmt.Printf("Restored stream %q in %v\n", bm.Config.Name, fp.EndTime().Sub(fp.StartTime()).Round(time.Second))
+	fmt.Printf("Restored stream %q in %v\n", bm.Config.Name, took.Round(time.Second))
 	fmt.Println()
 
 	stream, err := mgr.LoadStream(bm.Config.Name)
 	fisk.FatalIfError(err, "could not request Stream info")
+
+	if c.restoreTransformData {
+		err = c.transformRestoredData(stream, transforms)
+		fisk.FatalIfError(err, "could not transform restored data")
+		fmt.Println()
+	}
+
 	err = c.showStream(stream)
 	fisk.FatalIfError(err, "could not show stream")
 
 	return nil
 }
 
+// restoreRemote restores the stream while data downloads, progress is tracked by the bytes read as chunks are only known for local files
+func (c *streamCmd) restoreRemote(mgr *jsm.Manager, stream string, bmj []byte, data io.ReadCloser, size int64, ropts []jsm.SnapshotOption) (time.Duration, error) {
+	var progbar progress.Writer
+	var tracker *progress.Tracker
+
+	data = &dataReader{data}
+
+	if c.showProgress {
+		total := size
+		if total < 0 {
+			total = 0
+		}
+
+		progbar, tracker, _ = iu.NewProgress(opts(), &progress.Tracker{
+			Total: total,
+			Units: progress.UnitsBytes,
+		})
+
+		data = &countingReader{ReadCloser: data, cb: func(n int64) { tracker.SetValue(n) }}
+	}
+
+	start := time.Now()
+	_, err := mgr.RestoreSnapshotFromBuffer(ctx, stream, data, io.NopCloser(bytes.NewReader(bmj)), ropts...)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.showProgress {
+		tracker.MarkAsDone()
+		time.Sleep(300 * time.Millisecond)
+		progbar.Stop()
+	}
+
+	return time.Since(start), nil
+}
+
 func backupStream(stream *jsm.Stream, showProgress bool, consumers bool, check bool, target string, chunkSize, wndSize int) error {
 	first := true
 	pmu := sync.Mutex{}
@@ -1468,11 +3665,6 @@ func (c *streamCmd) backupAction(_ *fisk.ParseContext) error {
 	c.nc, c.mgr, err = prepareHelper("", natsOpts()...)
 	fisk.FatalIfError(err, "setup failed")
 
-	stream, err := c.loadStream(c.stream)
-	if err != nil {
-		return err
-	}
-
 	// Default is set in strBackup flags.
 	var chunkSize, wndSize int64
 	if c.chunkSize != "" {
@@ -1486,6 +3678,19 @@ func (c *streamCmd) backupAction(_ *fisk.ParseContext) error {
 		}
 	}
 
+	if c.backupSchedule != "" {
+		return c.scheduledBackups(int(chunkSize), int(wndSize))
+	}
+
+	if c.backupKeep > 0 || c.backupRuns > 0 || c.backupEventSubject != "" {
+		return fmt.Errorf("--keep, --runs and --event-subject require --schedule")
+	}
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
 	err = backupStream(stream, c.showProgress, c.snapShotConsumers, c.healthCheck, c.backupDirectory, int(chunkSize), int(wndSize))
 	fisk.FatalIfError(err, "snapshot failed")
 
@@ -1509,6 +3714,16 @@ func (c *streamCmd) reportAction(_ *fisk.ParseContext) error {
 		filter = &jsm.StreamNamesFilter{Subject: c.filterSubject}
 	}
 
+	dflt := streamReportDefaultColumns
+	if c.reportConsumerStats {
+		dflt = streamReportConsumerColumns
+	}
+
+	columns, err := c.streamListColumns(dflt)
+	if err != nil {
+		return err
+	}
+
 	dg := dot.NewGraph(dot.Directed)
 	dg.Label("Stream Replication Structure")
 
@@ -1530,34 +3745,11 @@ func (c *streamCmd) reportAction(_ *fisk.ParseContext) error {
 			}
 		}
 
-		deleted := info.State.NumDeleted
-		// backward compat with servers that predate the num_deleted response
-		if len(info.State.Deleted) > 0 {
-			deleted = len(info.State.Deleted)
-		}
-
-		apiLevel := info.Config.Metadata[api.JsMetaRequiredServerLevel]
-		if apiLevel == "" {
-			apiLevel = "0"
-		}
-
-		s := streamStat{
-			Name:      info.Config.Name,
-			Consumers: info.State.Consumers,
-			Msgs:      int64(info.State.Msgs),
-			Bytes:     info.State.Bytes,
-			Storage:   info.Config.Storage.String(),
-			Cluster:   info.Cluster,
-			Deleted:   deleted,
-			Mirror:    info.Mirror,
-			Sources:   info.Sources,
-			Placement: info.Config.Placement,
-			APILevel:  apiLevel,
-		}
+		s := newStreamStat(info)
 
-		if info.State.Lost != nil {
-			s.LostBytes = info.State.Lost.Bytes
-			s.LostMsgs = len(info.State.Lost.Msgs)
+		if streamColumnsNeedConsumers(append(columns, c.listSort)) {
+			err = s.addConsumerStats(stream)
+			fisk.FatalIfError(err, "could not get consumer info for %s", stream.Name())
 		}
 
 		if len(info.Config.Sources) > 0 {
@@ -1604,26 +3796,36 @@ func (c *streamCmd) reportAction(_ *fisk.ParseContext) error {
 		return err
 	}
 
-	if len(stats) == 0 && len(missing) == 0 && len(offline) == 0 {
-		if !c.json {
-			fmt.Println("No Streams defined")
+	if c.listSort == "" {
+		switch {
+		case c.reportSortConsumers:
+			c.listSort = "consumers"
+		case c.reportSortMsgs:
+			c.listSort = "messages"
+		case c.reportSortName:
+			c.listSort = "name"
+		case c.reportSortStorage:
+			c.listSort = "storage"
+		default:
+			c.listSort = "bytes"
 		}
-		return nil
 	}
 
-	if c.reportSortConsumers {
-		sort.Slice(stats, func(i, j int) bool { return stats[i].Consumers < stats[j].Consumers })
-	} else if c.reportSortMsgs {
-		sort.Slice(stats, func(i, j int) bool { return stats[i].Msgs < stats[j].Msgs })
-	} else if c.reportSortName {
-		sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
-	} else if c.reportSortStorage {
-		sort.Slice(stats, func(i, j int) bool { return stats[i].Storage < stats[j].Storage })
-	} else {
-		sort.Slice(stats, func(i, j int) bool { return stats[i].Bytes < stats[j].Bytes })
+	err = sortStreamStats(stats, c.listSort, c.reportSortReverse)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return iu.PrintJSON(streamStatsColumnsJSON(stats, columns))
+	}
+
+	if len(stats) == 0 && len(missing) == 0 && len(offline) == 0 {
+		fmt.Println("No Streams defined")
+		return nil
 	}
 
-	c.renderStreams(stats)
+	c.renderStreams(stats, columns)
 
 	if showReplication {
 		c.renderReplication(stats)
@@ -1701,36 +3903,35 @@ func (c *streamCmd) renderReplication(stats []streamStat) {
 	fmt.Println(table.Render())
 }
 
-func (c *streamCmd) renderStreams(stats []streamStat) {
+func (c *streamCmd) renderStreams(stats []streamStat, columns []string) {
 	table := iu.NewTableWriterf(opts(), "Stream Report")
-	table.AddHeaders("Stream", "Storage", "Placement", "Consumers", "Messages", "Bytes", "Lost", "Deleted", "API Level", "Replicas")
+	renderStreamStatsColumns(table, stats, columns, c.reportRaw)
+
+	fmt.Println(table.Render())
+}
 
-	for _, s := range stats {
-		lost := "0"
-		placement := ""
-		if s.Placement != nil {
-			if s.Placement.Cluster != "" {
-				placement = fmt.Sprintf("cluster: %s ", s.Placement.Cluster)
-			}
-			if len(s.Placement.Tags) > 0 {
-				placement = fmt.Sprintf("%stags: %s", placement, f(s.Placement.Tags))
+// streamListColumns parses the --columns flag, accepting both repeated flags and comma separated lists
+func (c *streamCmd) streamListColumns(dflt []string) ([]string, error) {
+	var columns []string
+	for _, col := range c.listColumns {
+		for _, p := range strings.Split(col, ",") {
+			p = strings.ToLower(strings.TrimSpace(p))
+			if p != "" {
+				columns = append(columns, p)
 			}
 		}
+	}
 
-		if c.reportRaw {
-			if s.LostMsgs > 0 {
-				lost = fmt.Sprintf("%d (%d)", s.LostMsgs, s.LostBytes)
-			}
-			table.AddRow(s.Name, s.Storage, placement, s.Consumers, s.Msgs, s.Bytes, lost, s.Deleted, renderCluster(s.Cluster))
-		} else {
-			if s.LostMsgs > 0 {
-				lost = fmt.Sprintf("%s (%s)", f(s.LostMsgs), humanize.IBytes(s.LostBytes))
-			}
-			table.AddRow(s.Name, s.Storage, placement, f(s.Consumers), f(s.Msgs), humanize.IBytes(s.Bytes), lost, f(s.Deleted), s.APILevel, renderCluster(s.Cluster))
-		}
+	if len(columns) == 0 {
+		return dflt, nil
 	}
 
-	fmt.Println(table.Render())
+	err := validateStreamColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return columns, nil
 }
 
 func (c *streamCmd) loadConfigFile(file string) (*api.StreamConfig, error) {
@@ -2040,7 +4241,249 @@ func (c *streamCmd) interactiveEdit(cfg api.StreamConfig) (api.StreamConfig, err
 	return ncfg, nil
 }
 
+// editedStreamConfig applies the edit flags to the configuration of stream and returns the new configuration and a diff against the current one
+func (c *streamCmd) editedStreamConfig(stream *jsm.Stream, pc *fisk.ParseContext) (api.StreamConfig, string, error) {
+	// lazy deep copy
+	input := stream.Configuration()
+	input.Metadata = iu.RemoveReservedMetadata(input.Metadata)
+
+	ij, err := json.Marshal(input)
+	if err != nil {
+		return api.StreamConfig{}, "", err
+	}
+	var cfg api.StreamConfig
+	err = json.Unmarshal(ij, &cfg)
+	if err != nil {
+		return api.StreamConfig{}, "", err
+	}
+
+	if c.interactive {
+		cfg, err = c.interactiveEdit(cfg)
+	} else {
+		cfg, err = c.copyAndEditStream(cfg, pc)
+	}
+	if err != nil {
+		return api.StreamConfig{}, "", fmt.Errorf("could not create new configuration for Stream %s: %w", stream.Name(), err)
+	}
+
+	if cfg.FirstSeq != input.FirstSeq {
+		return api.StreamConfig{}, "", fmt.Errorf("the first sequence can not be changed once the stream is created")
+	}
+
+	// sorts strings to subject lists that only differ in ordering is considered equal
+	sorter := cmp.Transformer("Sort", func(in []string) []string {
+		out := append([]string(nil), in...)
+		sort.Strings(out)
+		return out
+	})
+
+	return cfg, cmp.Diff(input, cfg, sorter), nil
+}
+
 func (c *streamCmd) editAction(pc *fisk.ParseContext) error {
+	if c.editSelect != "" || c.editSelectExpr != "" {
+		return c.bulkEditAction(pc)
+	}
+
+	c.connectAndAskStream()
+
+	sourceStream, err := c.loadStream(c.stream)
+	fisk.FatalIfError(err, "could not request Stream %s configuration", c.stream)
+
+	cfg, diff, err := c.editedStreamConfig(sourceStream, pc)
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		if !c.dryRun {
+			fmt.Println("No difference in configuration")
+		}
+
+		return nil
+	}
+
+	fmt.Printf("Differences (-old +new):\n%s", diff)
+	if c.dryRun {
+		os.Exit(1)
+	}
+
+	if jsm.IsKVBucketStream(c.stream) {
+		err := c.kvAbstractionWarn(c.stream, "Really operate on the KV stream?")
+		if err != nil {
+			return err
+		}
+	}
+
+	if !c.force {
+		ok, err := askConfirmation(fmt.Sprintf("Really edit Stream %s", c.stream), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	err = c.checkCompatibility(c.mgr, &cfg)
+	if err != nil {
+		return err
+	}
+
+	err = sourceStream.UpdateConfiguration(cfg)
+	fisk.FatalIfError(err, "could not edit Stream %s", c.stream)
+
+	if !c.json {
+		fmt.Printf("Stream %s was updated\n\n", c.stream)
+	}
+
+	return c.showStream(sourceStream)
+}
+
+func (c *streamCmd) bulkEditAction(pc *fisk.ParseContext) error {
+	if c.stream != "" {
+		return fmt.Errorf("a stream name cannot be combined with --select or --select-expr")
+	}
+	if c.interactive || c.inputFile != "" {
+		return fmt.Errorf("--interactive and --config cannot be used when editing multiple streams")
+	}
+
+	if c.editSelect != "" {
+		_, err := filepath.Match(c.editSelect, "")
+		if err != nil {
+			return fmt.Errorf("invalid --select glob: %w", err)
+		}
+	}
+
+	var err error
+	c.nc, c.mgr, err = prepareHelper("", natsOpts()...)
+	fisk.FatalIfError(err, "setup failed")
+
+	var streams []*jsm.Stream
+	_, _, err = c.mgr.EachStream(nil, func(s *jsm.Stream) {
+		if !c.showAll && s.IsInternal() {
+			return
+		}
+
+		if c.editSelect != "" {
+			ok, _ := filepath.Match(c.editSelect, s.Name())
+			if !ok {
+				return
+			}
+		}
+
+		streams = append(streams, s)
+	})
+	if err != nil {
+		return fmt.Errorf("could not list streams: %w", err)
+	}
+
+	if c.editSelectExpr != "" {
+		expression, err := expandExpressionLiterals(c.editSelectExpr)
+		if err != nil {
+			return fmt.Errorf("invalid expression: %w", err)
+		}
+
+		matched, err := c.mgr.QueryStreams(jsm.StreamQueryExpression(expression))
+		if err != nil {
+			return err
+		}
+
+		streams = slices.DeleteFunc(streams, func(s *jsm.Stream) bool {
+			return !slices.ContainsFunc(matched, func(m *jsm.Stream) bool { return m.Name() == s.Name() })
+		})
+	}
+
+	if len(streams) == 0 {
+		return fmt.Errorf("no streams matched the selection")
+	}
+
+	sort.Slice(streams, func(i, j int) bool { return streams[i].Name() < streams[j].Name() })
+
+	type edit struct {
+		stream *jsm.Stream
+		cfg    api.StreamConfig
+	}
+
+	var edits []edit
+	var kvStreams bool
+
+	for _, stream := range streams {
+		cfg, diff, err := c.editedStreamConfig(stream, pc)
+		if err != nil {
+			return err
+		}
+
+		if diff == "" {
+			continue
+		}
+
+		fmt.Printf("Stream %s differences (-old +new):\n%s\n", stream.Name(), diff)
+
+		if jsm.IsKVBucketStream(stream.Name()) {
+			kvStreams = true
+		}
+
+		edits = append(edits, edit{stream: stream, cfg: cfg})
+	}
+
+	if len(edits) == 0 {
+		if !c.dryRun {
+			fmt.Printf("No difference in configuration for %s matching Streams\n", f(len(streams)))
+		}
+
+		return nil
+	}
+
+	if c.dryRun {
+		os.Exit(1)
+	}
+
+	if kvStreams {
+		err := c.kvAbstractionWarn("", "Really operate on KV streams?")
+		if err != nil {
+			return err
+		}
+	}
+
+	if !c.force {
+		ok, err := askConfirmation(fmt.Sprintf("Really edit %s Streams", f(len(edits))), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	var failed int
+	for _, e := range edits {
+		err = c.checkCompatibility(c.mgr, &e.cfg)
+		if err == nil {
+			err = e.stream.UpdateConfiguration(e.cfg)
+		}
+
+		if err != nil {
+			failed++
+			fmt.Printf("Could not edit Stream %s: %v\n", e.stream.Name(), err)
+			continue
+		}
+
+		fmt.Printf("Stream %s was updated\n", e.stream.Name())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%s of %s Streams could not be edited", f(failed), f(len(edits)))
+	}
+
+	fmt.Printf("\nUpdated %s Streams\n", f(len(edits)))
+
+	return nil
+}
+
+func (c *streamCmd) cpAction(pc *fisk.ParseContext) error {
+	if c.stream == c.destination && c.copyTargetContext == "" {
+		fisk.Fatalf("source and destination Stream names cannot be the same")
+	}
+
 	c.connectAndAskStream()
 
 	sourceStream, err := c.loadStream(c.stream)
@@ -2048,117 +4491,262 @@ func (c *streamCmd) editAction(pc *fisk.ParseContext) error {
 
 	// lazy deep copy
 	input := sourceStream.Configuration()
-	input.Metadata = iu.RemoveReservedMetadata(input.Metadata)
-
 	ij, err := json.Marshal(input)
 	if err != nil {
 		return err
 	}
-	var cfg api.StreamConfig
-	err = json.Unmarshal(ij, &cfg)
+	var cfg api.StreamConfig
+	err = json.Unmarshal(ij, &cfg)
+	if err != nil {
+		return err
+	}
+
+	cfg, err = c.copyAndEditStream(cfg, pc)
+	fisk.FatalIfError(err, "could not copy Stream %s", c.stream)
+
+	cfg.Name = c.destination
+
+	transforms, err := parseSubjectTransforms(c.copyTransforms)
+	fisk.FatalIfError(err, "invalid transform")
+
+	if len(transforms) > 0 {
+		err = applyRestoreTransforms(&cfg, transforms)
+		fisk.FatalIfError(err, "could not transform the destination subjects")
+	}
+
+	// checked before creating the destination so a copy that cannot succeed leaves nothing behind
+	if c.copyMessages && c.copyTargetContext == "" {
+		err = checkCopySubjects(input.Subjects, transforms)
+		fisk.FatalIfError(err, "cannot copy messages")
+	}
+
+	targetMgr := c.mgr
+	var targetJS jetstream.JetStream
+	if c.copyTargetContext != "" {
+		var targetNc *nats.Conn
+		targetNc, targetMgr, targetJS, err = connectToContext(c.copyTargetContext)
+		fisk.FatalIfError(err, "could not connect to context %s", c.copyTargetContext)
+		defer targetNc.Close()
+	} else if c.copyMessages {
+		_, targetJS, err = prepareJSHelper()
+		fisk.FatalIfError(err, "setup failed")
+	}
+
+	var newStream *jsm.Stream
+	known := false
+	if c.copyMessages {
+		known, err = targetMgr.IsKnownStream(cfg.Name)
+		fisk.FatalIfError(err, "could not check if the destination stream exists")
+	}
+
+	if known {
+		newStream, err = targetMgr.LoadStream(cfg.Name)
+		fisk.FatalIfError(err, "could not load Stream %s", cfg.Name)
+	} else {
+		newStream, err = targetMgr.NewStreamFromDefault(cfg.Name, cfg)
+		fisk.FatalIfError(err, "could not create Stream")
+
+		if !c.json {
+			fmt.Printf("Stream %s was created\n\n", cfg.Name)
+		}
+	}
+
+	if c.copyMessages {
+		copied, err := c.copyStreamMessages(sourceStream, targetJS, newStream, transforms)
+		fisk.FatalIfError(err, "could not copy messages")
+
+		if !c.json {
+			fmt.Printf("Copied %s messages from %s to %s\n\n", f(copied), c.stream, cfg.Name)
+		}
+
+		newStream, err = targetMgr.LoadStream(cfg.Name)
+		fisk.FatalIfError(err, "could not load Stream %s", cfg.Name)
+	}
+
+	c.showStream(newStream)
+
+	return nil
+}
+
+// checkCopySubjects ensures messages copied within the same account are not captured by the source stream
+func checkCopySubjects(sourceSubjects []string, transforms []subjectTransformSpec) error {
+	if len(transforms) == 0 {
+		return fmt.Errorf("copying messages within the same context requires --target-context or --transform, the source stream would capture the copied messages")
+	}
+
+	for _, subj := range sourceSubjects {
+		mapped, _ := transformSubject(transforms, subj)
+		for _, src := range sourceSubjects {
+			if server.SubjectsCollide(mapped, src) {
+				return fmt.Errorf("transformed subject %s overlaps with source subject %s", mapped, src)
+			}
+		}
+	}
+
+	return nil
+}
+
+// streamCopyProgress is the state stored in the --progress-file of stream copy
+type streamCopyProgress struct {
+	Source  string `json:"source"`
+	LastSeq uint64 `json:"last_seq"`
+}
+
+// republishHeader determines if header k of a stored message should be kept when publishing it again, JetStream
+// control headers would otherwise be acted on again
+func republishHeader(k string, keepMsgID bool, keepTTL bool) bool {
+	switch {
+	case k == api.JSRollup, strings.HasPrefix(k, "Nats-Expected-"):
+		return false
+	case k == api.JSMsgId:
+		return keepMsgID
+	case k == api.JSMessageTTL:
+		return keepTTL
+	default:
+		return true
+	}
+}
+
+// copyStreamMessages publishes all messages up to the current last sequence of the source into the target stream in batches, recording progress after every batch
+func (c *streamCmd) copyStreamMessages(source *jsm.Stream, tjs jetstream.JetStream, target *jsm.Stream, transforms []subjectTransformSpec) (int, error) {
+	var state streamCopyProgress
+	if c.copyProgressFile != "" {
+		pj, err := os.ReadFile(c.copyProgressFile)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return 0, err
+		default:
+			err = json.Unmarshal(pj, &state)
+			if err != nil {
+				return 0, fmt.Errorf("invalid progress file %s: %w", c.copyProgressFile, err)
+			}
+			if state.Source != source.Name() {
+				return 0, fmt.Errorf("progress file %s records a copy of stream %s", c.copyProgressFile, state.Source)
+			}
+		}
+	}
+
+	nfo, err := source.LatestInformation()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if c.interactive {
-		cfg, err = c.interactiveEdit(cfg)
-		fisk.FatalIfError(err, "could not create new configuration for Stream %s", c.stream)
-	} else {
-		cfg, err = c.copyAndEditStream(cfg, pc)
-		fisk.FatalIfError(err, "could not create new configuration for Stream %s", c.stream)
+	start := max(state.LastSeq+1, nfo.State.FirstSeq)
+	if nfo.State.Msgs == 0 || start > nfo.State.LastSeq {
+		return 0, nil
 	}
 
-	// sorts strings to subject lists that only differ in ordering is considered equal
-	sorter := cmp.Transformer("Sort", func(in []string) []string {
-		out := append([]string(nil), in...)
-		sort.Strings(out)
-		return out
-	})
-
-	diff := cmp.Diff(input, cfg, sorter)
-	if diff == "" {
-		if !c.dryRun {
-			fmt.Println("No difference in configuration")
-		}
-
-		return nil
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return 0, err
 	}
 
-	fmt.Printf("Differences (-old +new):\n%s", diff)
-	if c.dryRun {
-		os.Exit(1)
+	cons, err := js.OrderedConsumer(ctx, source.Name(), jetstream.OrderedConsumerConfig{
+		DeliverPolicy: jetstream.DeliverByStartSequencePolicy,
+		OptStartSeq:   start,
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	if jsm.IsKVBucketStream(c.stream) {
-		err := c.kvAbstractionWarn(c.stream, "Really operate on the KV stream?")
+	var progbar progress.Writer
+	var tracker *progress.Tracker
+	if c.showProgress && !c.json {
+		progbar, tracker, err = iu.NewProgress(opts(), &progress.Tracker{Total: int64(nfo.State.LastSeq - start + 1)})
 		if err != nil {
-			return err
+			return 0, err
 		}
+		defer progbar.Stop()
 	}
 
-	if !c.force {
-		ok, err := askConfirmation(fmt.Sprintf("Really edit Stream %s", c.stream), false)
-		fisk.FatalIfError(err, "could not obtain confirmation")
+	iter, err := cons.Messages()
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Stop()
+
+	const batchSize = 256
+	var futures []jetstream.PubAckFuture
+	var copied int
+
+	flush := func(seq uint64) error {
+		for _, fut := range futures {
+			select {
+			case <-fut.Ok():
+			case err := <-fut.Err():
+				return fmt.Errorf("publishing %s failed: %w", fut.Msg().Subject, err)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		copied += len(futures)
+		futures = futures[:0]
 
-		if !ok {
+		if tracker != nil {
+			tracker.SetValue(int64(seq - start + 1))
+		}
+
+		if c.copyProgressFile == "" {
 			return nil
 		}
-	}
 
-	if cfg.AllowAtomicPublish || cfg.AllowMsgCounter {
-		err := c.checkCompatibility(c.mgr, &cfg)
+		state.Source = source.Name()
+		state.LastSeq = seq
+		pj, err := json.Marshal(state)
 		if err != nil {
 			return err
 		}
-	}
-
-	err = sourceStream.UpdateConfiguration(cfg)
-	fisk.FatalIfError(err, "could not edit Stream %s", c.stream)
 
-	if !c.json {
-		fmt.Printf("Stream %s was updated\n\n", c.stream)
+		return writeFileAtomic(c.copyProgressFile, pj)
 	}
 
-	return c.showStream(sourceStream)
-}
-
-func (c *streamCmd) cpAction(pc *fisk.ParseContext) error {
-	if c.stream == c.destination {
-		fisk.Fatalf("source and destination Stream names cannot be the same")
-	}
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			return copied, err
+		}
 
-	c.connectAndAskStream()
+		meta, err := msg.Metadata()
+		if err != nil {
+			return copied, err
+		}
 
-	sourceStream, err := c.loadStream(c.stream)
-	fisk.FatalIfError(err, "could not request Stream %s configuration", c.stream)
+		subject, _ := transformSubject(transforms, msg.Subject())
+		out := nats.NewMsg(subject)
+		out.Data = msg.Data()
+		for k, v := range msg.Headers() {
+			if !republishHeader(k, c.copyMsgID, true) {
+				continue
+			}
+			out.Header[k] = v
+		}
 
-	// lazy deep copy
-	input := sourceStream.Configuration()
-	ij, err := json.Marshal(input)
-	if err != nil {
-		return err
-	}
-	var cfg api.StreamConfig
-	err = json.Unmarshal(ij, &cfg)
-	if err != nil {
-		return err
-	}
+		fut, err := tjs.PublishMsgAsync(out, jetstream.WithExpectStream(target.Name()))
+		if err != nil {
+			return copied, err
+		}
+		futures = append(futures, fut)
 
-	cfg, err = c.copyAndEditStream(cfg, pc)
-	fisk.FatalIfError(err, "could not copy Stream %s", c.stream)
+		// messages added after we started are not copied, pending covers deleted messages at the end of the stream
+		done := meta.Sequence.Stream >= nfo.State.LastSeq || meta.NumPending == 0
 
-	cfg.Name = c.destination
+		if len(futures) >= batchSize || done {
+			err = flush(meta.Sequence.Stream)
+			if err != nil {
+				return copied, err
+			}
+		}
 
-	newStream, err := c.mgr.NewStreamFromDefault(cfg.Name, cfg)
-	fisk.FatalIfError(err, "could not create Stream")
+		if done {
+			if tracker != nil {
+				tracker.MarkAsDone()
+				time.Sleep(300 * time.Millisecond)
+			}
 
-	if !c.json {
-		fmt.Printf("Stream %s was created\n\n", cfg.Name)
+			return copied, nil
+		}
 	}
-
-	c.showStream(newStream)
-
-	return nil
 }
 
 func (c *streamCmd) showStreamConfig(cols *columns.Writer, cfg api.StreamConfig) {
@@ -2514,6 +5102,14 @@ func (c *streamCmd) showStreamInfo(info *api.StreamInfo) {
 }
 
 func (c *streamCmd) stateAction(pc *fisk.ParseContext) error {
+	if c.stateBySubject {
+		return c.subjectStateAction()
+	}
+
+	if len(c.subjectsFilters) > 0 || c.subjectsMinMsgs > 0 || c.csv {
+		return fmt.Errorf("--filter, --min-msgs and --csv require --by-subject")
+	}
+
 	c.showStateOnly = true
 	return c.infoAction(pc)
 }
@@ -3230,6 +5826,17 @@ func (c *streamCmd) addAction(pc *fisk.ParseContext) (err error) {
 
 	requireSize, _ := mgr.IsStreamMaxBytesRequired()
 
+	if c.profile != "" {
+		if c.inputFile != "" {
+			return fmt.Errorf("--profile and --config cannot be combined")
+		}
+
+		err = c.applyStreamProfile(c.profile)
+		if err != nil {
+			return err
+		}
+	}
+
 	cfg := c.prepareConfig(pc, requireSize)
 
 	switch {
@@ -3259,10 +5866,24 @@ func (c *streamCmd) addAction(pc *fisk.ParseContext) (err error) {
 		return os.WriteFile(c.outFile, j, 0600)
 	}
 
-	if cfg.AllowAtomicPublish || cfg.AllowMsgCounter {
-		err := c.checkCompatibility(mgr, &cfg)
-		if err != nil {
+	err = c.checkCompatibility(mgr, &cfg)
+	if err != nil {
+		return err
+	}
+
+	interactive := iu.IsTerminal() && !c.acceptDefaults && c.inputFile == ""
+	if c.shouldPreviewPlacement(mgr.NatsConn(), cfg, interactive) {
+		ok, err := c.showPlacementPreview(mgr.NatsConn(), cfg)
+		switch {
+		case err != nil:
 			return err
+		case !ok && interactive:
+			create, err := askConfirmation("Placement can likely not be satisfied, create the Stream anyway", false)
+			fisk.FatalIfError(err, "could not obtain confirmation")
+
+			if !create {
+				return nil
+			}
 		}
 	}
 
@@ -3276,19 +5897,87 @@ func (c *streamCmd) addAction(pc *fisk.ParseContext) (err error) {
 	return nil
 }
 
-func (c *streamCmd) checkCompatibility(mgr *jsm.Manager, cfg *api.StreamConfig) error {
-	if cfg.AllowAtomicPublish {
-		err := iu.RequireAPILevel(mgr, 2, "Atomic Batch Publishing requires NATS Server 2.12")
-		if err != nil {
-			return err
+// streamFeature is a stream setting that requires a minimum server version and API level
+type streamFeature struct {
+	field   string
+	name    string
+	version [3]int
+	level   int
+	used    func(cfg *api.StreamConfig) bool
+}
+
+var streamFeatures = []streamFeature{
+	{field: "max_msgs_per_subject", name: "Per Subject Limits", version: [3]int{2, 3, 0}, used: func(cfg *api.StreamConfig) bool { return cfg.MaxMsgsPer > 0 }},
+	{field: "allow_rollup_hdrs", name: "Message Roll-ups", version: [3]int{2, 6, 2}, used: func(cfg *api.StreamConfig) bool { return cfg.RollupAllowed }},
+	{field: "deny_delete", name: "Denying Message Deletes", version: [3]int{2, 6, 2}, used: func(cfg *api.StreamConfig) bool { return cfg.DenyDelete }},
+	{field: "deny_purge", name: "Denying Purges", version: [3]int{2, 6, 2}, used: func(cfg *api.StreamConfig) bool { return cfg.DenyPurge }},
+	{field: "republish", name: "Republishing", version: [3]int{2, 9, 0}, used: func(cfg *api.StreamConfig) bool { return cfg.RePublish != nil }},
+	{field: "mirror_direct", name: "Direct Get on Mirrors", version: [3]int{2, 9, 0}, used: func(cfg *api.StreamConfig) bool { return cfg.MirrorDirect }},
+	{field: "discard_new_per_subject", name: "Per Subject Discard New", version: [3]int{2, 9, 0}, used: func(cfg *api.StreamConfig) bool { return cfg.DiscardNewPer }},
+	{field: "subject_transform", name: "Subject Transforms", version: [3]int{2, 10, 0}, used: func(cfg *api.StreamConfig) bool { return cfg.SubjectTransform != nil }},
+	{field: "sources", name: "Source and Mirror Subject Transforms", version: [3]int{2, 10, 0}, used: func(cfg *api.StreamConfig) bool {
+		if cfg.Mirror != nil && len(cfg.Mirror.SubjectTransforms) > 0 {
+			return true
+		}
+		for _, source := range cfg.Sources {
+			if len(source.SubjectTransforms) > 0 {
+				return true
+			}
 		}
-	}
+		return false
+	}},
+	{field: "compression", name: "Compression", version: [3]int{2, 10, 0}, used: func(cfg *api.StreamConfig) bool { return cfg.Compression == api.S2Compression }},
+	{field: "first_seq", name: "Setting the First Sequence", version: [3]int{2, 10, 0}, used: func(cfg *api.StreamConfig) bool { return cfg.FirstSeq > 0 }},
+	{field: "metadata", name: "Metadata", version: [3]int{2, 10, 0}, used: func(cfg *api.StreamConfig) bool { return len(iu.RemoveReservedMetadata(cfg.Metadata)) > 0 }},
+	{field: "consumer_limits", name: "Consumer Limits", version: [3]int{2, 10, 0}, used: func(cfg *api.StreamConfig) bool {
+		return cfg.ConsumerLimits.InactiveThreshold > 0 || cfg.ConsumerLimits.MaxAckPending > 0
+	}},
+	{field: "allow_msg_ttl", name: "Per Message TTLs", version: [3]int{2, 11, 0}, level: 1, used: func(cfg *api.StreamConfig) bool { return cfg.AllowMsgTTL }},
+	{field: "subject_delete_marker_ttl", name: "Subject Delete Markers", version: [3]int{2, 11, 0}, level: 1, used: func(cfg *api.StreamConfig) bool { return cfg.SubjectDeleteMarkerTTL > 0 }},
+	{field: "allow_atomic", name: "Atomic Batch Publishing", version: [3]int{2, 12, 0}, level: 2, used: func(cfg *api.StreamConfig) bool { return cfg.AllowAtomicPublish }},
+	{field: "allow_msg_counter", name: "Distributed Counters", version: [3]int{2, 12, 0}, level: 2, used: func(cfg *api.StreamConfig) bool { return cfg.AllowMsgCounter }},
+	{field: "allow_msg_schedules", name: "Message Schedules", version: [3]int{2, 12, 0}, level: 2, used: func(cfg *api.StreamConfig) bool { return cfg.AllowMsgSchedules }},
+	{field: "persist_mode", name: "Async Persistence", version: [3]int{2, 12, 0}, level: 2, used: func(cfg *api.StreamConfig) bool { return cfg.PersistMode == api.AsyncPersistMode }},
+	{field: "allow_batched", name: "Fast Batch Publishing", version: [3]int{2, 14, 0}, level: 4, used: func(cfg *api.StreamConfig) bool { return cfg.AllowBatchPublish }},
+}
 
-	if cfg.AllowMsgCounter {
-		err := iu.RequireAPILevel(mgr, 2, "Distributed Counters requires NATS Server 2.12")
-		if err != nil {
-			return err
+// unsupportedStreamFeatures lists the settings in cfg that a server with the JetStream API level does not support,
+// servers older than 2.11 do not report an API level so the server version is used for older settings
+func unsupportedStreamFeatures(cfg *api.StreamConfig, level int, version string) []string {
+	var problems []string
+
+	for _, feature := range streamFeatures {
+		if !feature.used(cfg) {
+			continue
+		}
+
+		switch {
+		case feature.level > 0 && level >= feature.level:
+			continue
+		case feature.level == 0 && (level > 0 || iu.VersionAtLeast(version, feature.version[0], feature.version[1], feature.version[2])):
+			continue
 		}
+
+		problems = append(problems, fmt.Sprintf("%s: %s requires NATS Server %d.%d.%d", feature.field, feature.name, feature.version[0], feature.version[1], feature.version[2]))
+	}
+
+	return problems
+}
+
+// checkCompatibility ensures the JetStream API supports all the settings in cfg
+func (c *streamCmd) checkCompatibility(mgr *jsm.Manager, cfg *api.StreamConfig) error {
+	if c.skipCompatCheck {
+		return nil
+	}
+
+	level, err := mgr.MetaApiLevel(false)
+	if err != nil {
+		return err
+	}
+
+	problems := unsupportedStreamFeatures(cfg, level, mgr.NatsConn().ConnectedServerVersion())
+	if len(problems) > 0 {
+		return fmt.Errorf("the JetStream API level %d does not support these settings, use --skip-compat-check to attempt it anyway:\n\t%s", level, strings.Join(problems, "\n\t"))
 	}
 
 	return nil
@@ -3334,6 +6023,53 @@ func (c *streamCmd) rmAction(_ *fisk.ParseContext) (err error) {
 func (c *streamCmd) purgeAction(_ *fisk.ParseContext) (err error) {
 	c.connectAndAskStream()
 
+	if c.purgeKeep > 0 && (c.purgeSequence > 0 || c.purgeOlderThan != "") {
+		return fmt.Errorf("sequence and keep cannot be combined when purging")
+	}
+
+	stream, err := c.loadStream(c.stream)
+	fisk.FatalIfError(err, "could not purge Stream")
+
+	if c.purgeOlderThan != "" {
+		age, err := fisk.ParseDuration(c.purgeOlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than duration: %w", err)
+		}
+
+		seq, err := c.purgeSequenceForTime(stream, time.Now().Add(-age))
+		if err != nil {
+			return fmt.Errorf("could not determine purge sequence: %w", err)
+		}
+
+		// when both are given purge up to whichever comes first
+		if c.purgeSequence == 0 || seq < c.purgeSequence {
+			c.purgeSequence = seq
+		}
+
+		state, err := stream.State()
+		if err != nil {
+			return err
+		}
+
+		if c.purgeSequence <= state.FirstSeq {
+			fmt.Printf("No messages older than %v in Stream %s\n", age, stream.Name())
+			return nil
+		}
+	}
+
+	var req *api.JSApiStreamPurgeRequest
+	if c.purgeKeep > 0 || c.purgeSubject != "" || c.purgeSequence > 0 {
+		req = &api.JSApiStreamPurgeRequest{
+			Sequence: c.purgeSequence,
+			Subject:  c.purgeSubject,
+			Keep:     c.purgeKeep,
+		}
+	}
+
+	if c.dryRun {
+		return c.purgePreview(stream, req)
+	}
+
 	if !c.force {
 		ok, err := askConfirmation(fmt.Sprintf("Really purge Stream %s", c.stream), false)
 		fisk.FatalIfError(err, "could not obtain confirmation")
@@ -3350,31 +6086,163 @@ func (c *streamCmd) purgeAction(_ *fisk.ParseContext) (err error) {
 		}
 	}
 
-	stream, err := c.loadStream(c.stream)
+	resp, err := stream.PurgeExt(req)
 	fisk.FatalIfError(err, "could not purge Stream")
 
-	var req *api.JSApiStreamPurgeRequest
-	if c.purgeKeep > 0 || c.purgeSubject != "" || c.purgeSequence > 0 {
-		if c.purgeSequence > 0 && c.purgeKeep > 0 {
-			return fmt.Errorf("sequence and keep cannot be combined when purging")
+	fmt.Printf("Purged %d messages from %s\n\n", resp.Purged, stream.Name())
+
+	stream.Reset()
+
+	c.showStateOnly = true
+	return c.showStream(stream)
+}
+
+// purgeSequenceForTime finds the first message stored at or after ts, purging up to that sequence removes all older messages
+func (c *streamCmd) purgeSequenceForTime(stream *jsm.Stream, ts time.Time) (uint64, error) {
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return 0, err
+	}
+
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), jetstream.OrderedConsumerConfig{
+		DeliverPolicy: jetstream.DeliverByStartTimePolicy,
+		OptStartTime:  &ts,
+		HeadersOnly:   true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	nfo, err := cons.Info(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// every message is older than ts
+	if nfo.NumPending == 0 && nfo.Delivered.Consumer == 0 {
+		state, err := stream.State()
+		if err != nil {
+			return 0, err
 		}
 
-		req = &api.JSApiStreamPurgeRequest{
-			Sequence: c.purgeSequence,
-			Subject:  c.purgeSubject,
-			Keep:     c.purgeKeep,
+		return state.LastSeq + 1, nil
+	}
+
+	msg, err := cons.Next(jetstream.FetchMaxWait(opts().Timeout))
+	if err != nil {
+		return 0, err
+	}
+
+	meta, err := msg.Metadata()
+	if err != nil {
+		return 0, err
+	}
+
+	return meta.Sequence.Stream, nil
+}
+
+// purgePreview walks the messages a purge request would remove and reports the count per subject
+func (c *streamCmd) purgePreview(stream *jsm.Stream, req *api.JSApiStreamPurgeRequest) error {
+	if req == nil {
+		req = &api.JSApiStreamPurgeRequest{}
+	}
+
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return err
+	}
+
+	ocfg := jetstream.OrderedConsumerConfig{HeadersOnly: true}
+	if req.Subject != "" {
+		ocfg.FilterSubjects = []string{req.Subject}
+	}
+
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), ocfg)
+	if err != nil {
+		return err
+	}
+
+	nfo, err := cons.Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	matched := nfo.NumPending + nfo.Delivered.Consumer
+	limit := matched
+	if req.Keep > 0 {
+		limit = 0
+		if matched > req.Keep {
+			limit = matched - req.Keep
+		}
+	}
+
+	subjects := map[string]uint64{}
+	var total uint64
+
+	if limit > 0 {
+		iter, err := cons.Messages()
+		if err != nil {
+			return err
+		}
+		defer iter.Stop()
+
+		for total < limit {
+			msg, err := iter.Next()
+			if err != nil {
+				return err
+			}
+
+			meta, err := msg.Metadata()
+			if err != nil {
+				return err
+			}
+
+			if req.Sequence > 0 && meta.Sequence.Stream >= req.Sequence {
+				break
+			}
+
+			subjects[msg.Subject()]++
+			total++
+
+			if meta.NumPending == 0 {
+				break
+			}
 		}
 	}
 
-	resp, err := stream.PurgeExt(req)
-	fisk.FatalIfError(err, "could not purge Stream")
+	if c.json {
+		return iu.PrintJSON(map[string]any{
+			"stream":   stream.Name(),
+			"subjects": subjects,
+			"total":    total,
+		})
+	}
+
+	if total == 0 {
+		fmt.Printf("No messages would be purged from Stream %s\n", stream.Name())
+		return nil
+	}
 
-	fmt.Printf("Purged %d messages from %s\n\n", resp.Purged, stream.Name())
+	var names []string
+	for subj := range subjects {
+		names = append(names, subj)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if subjects[names[i]] == subjects[names[j]] {
+			return names[i] < names[j]
+		}
+		return subjects[names[i]] > subjects[names[j]]
+	})
 
-	stream.Reset()
+	table := iu.NewTableWriterf(opts(), "Messages that would be purged from %s", stream.Name())
+	table.AddHeaders("Subject", "Messages")
+	for _, subj := range names {
+		table.AddRow(subj, f(subjects[subj]))
+	}
+	table.AddFooter("Total", f(total))
+	fmt.Println(table.Render())
 
-	c.showStateOnly = true
-	return c.showStream(stream)
+	return nil
 }
 
 func (c *streamCmd) lsNames(mgr *jsm.Manager, filter *jsm.StreamNamesFilter) error {
@@ -3428,7 +6296,27 @@ func (c *streamCmd) lsAction(_ *fisk.ParseContext) error {
 	}
 
 	if c.json {
-		err = iu.PrintJSON(names)
+		stats, err := c.sortedStreamStats(streams)
+		if err != nil {
+			return err
+		}
+
+		if len(c.listColumns) == 0 {
+			names = []string{}
+			for _, s := range stats {
+				names = append(names, s.Name)
+			}
+			err = iu.PrintJSON(names)
+			fisk.FatalIfError(err, "could not display Streams")
+			return nil
+		}
+
+		columns, err := c.streamListColumns(streamLsDefaultColumns)
+		if err != nil {
+			return err
+		}
+
+		err = iu.PrintJSON(streamStatsColumnsJSON(stats, columns))
 		fisk.FatalIfError(err, "could not display Streams")
 		return nil
 	}
@@ -3463,13 +6351,54 @@ func (c *streamCmd) renderStreamsAsList(streams []*jsm.Stream, missing []string)
 	return strings.Join(names, "\n")
 }
 
+func (c *streamCmd) sortedStreamStats(streams []*jsm.Stream) ([]streamStat, error) {
+	columns, err := c.streamListColumns(nil)
+	if err != nil {
+		return nil, err
+	}
+	needConsumers := streamColumnsNeedConsumers(append(columns, c.listSort))
+
+	stats := []streamStat{}
+	for _, s := range streams {
+		nfo, err := s.LatestInformation()
+		if err != nil {
+			return nil, err
+		}
+
+		stat := newStreamStat(nfo)
+		if needConsumers {
+			err = stat.addConsumerStats(s)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		stats = append(stats, stat)
+	}
+
+	sortCol := c.listSort
+	if sortCol == "" {
+		sortCol = "bytes"
+	}
+
+	err = sortStreamStats(stats, sortCol, c.reportSortReverse)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
 func (c *streamCmd) renderStreamsAsTable(streams []*jsm.Stream, missing []string, offline map[string]string) (string, error) {
-	sort.Slice(streams, func(i, j int) bool {
-		info, _ := streams[i].LatestInformation()
-		jnfo, _ := streams[j].LatestInformation()
+	columns, err := c.streamListColumns(streamLsDefaultColumns)
+	if err != nil {
+		return "", err
+	}
 
-		return info.State.Bytes < jnfo.State.Bytes
-	})
+	stats, err := c.sortedStreamStats(streams)
+	if err != nil {
+		return "", err
+	}
 
 	var out bytes.Buffer
 	var table *iu.Table
@@ -3479,11 +6408,7 @@ func (c *streamCmd) renderStreamsAsTable(streams []*jsm.Stream, missing []string
 		table = iu.NewTableWriterf(opts(), "Streams matching %s", c.filterSubject)
 	}
 
-	table.AddHeaders("Name", "Description", "Created", "Messages", "Size", "Last Message")
-	for _, s := range streams {
-		nfo, _ := s.LatestInformation()
-		table.AddRow(s.Name(), s.Description(), f(nfo.Created.Local()), f(nfo.State.Msgs), humanize.IBytes(nfo.State.Bytes), f(sinceRefOrNow(nfo.TimeStamp, nfo.State.LastTime)))
-	}
+	renderStreamStatsColumns(table, stats, columns, false)
 
 	fmt.Fprintln(&out, table.Render())
 
@@ -3530,6 +6455,14 @@ func (c *streamCmd) renderMissing(out io.Writer, missing []string, offline map[s
 func (c *streamCmd) rmMsgAction(_ *fisk.ParseContext) (err error) {
 	c.connectAndAskStream()
 
+	if c.rmmSubject != "" || c.rmmOlderThan != "" {
+		if c.msgID != -1 {
+			return fmt.Errorf("a message sequence cannot be combined with --subject or --older-than")
+		}
+
+		return c.rmMsgBulk()
+	}
+
 	if c.msgID == -1 {
 		id := ""
 		err = iu.AskOne(&survey.Input{
@@ -3565,12 +6498,161 @@ func (c *streamCmd) rmMsgAction(_ *fisk.ParseContext) (err error) {
 		}
 	}
 
-	return stream.DeleteMessageRequest(api.JSApiMsgDeleteRequest{Seq: uint64(c.msgID)})
+	return stream.DeleteMessageRequest(api.JSApiMsgDeleteRequest{Seq: uint64(c.msgID), NoErase: !c.rmmErase})
+}
+
+// rmMsgSequences finds the sequences of all messages matching subject that were stored before cutoff,
+// a zero cutoff matches all messages
+func (c *streamCmd) rmMsgSequences(stream *jsm.Stream, subject string, cutoff time.Time) ([]uint64, error) {
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return nil, err
+	}
+
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{subject},
+		DeliverPolicy:  jetstream.DeliverAllPolicy,
+		HeadersOnly:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nfo, err := cons.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var seqs []uint64
+	if nfo.NumPending == 0 {
+		return seqs, nil
+	}
+
+	iter, err := cons.Messages()
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Stop()
+
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return nil, err
+		}
+
+		// messages are stored in time order so everything after this is newer too
+		if !cutoff.IsZero() && !meta.Timestamp.Before(cutoff) {
+			return seqs, nil
+		}
+
+		seqs = append(seqs, meta.Sequence.Stream)
+
+		if meta.NumPending == 0 {
+			return seqs, nil
+		}
+	}
+}
+
+func (c *streamCmd) rmMsgBulk() error {
+	stream, err := c.loadStream(c.stream)
+	fisk.FatalIfError(err, "could not load Stream %s", c.stream)
+
+	if jsm.IsKVBucketStream(c.stream) {
+		err := c.kvAbstractionWarn(c.stream, "Really operate on the KV stream?")
+		if err != nil {
+			return err
+		}
+	}
+
+	subject := c.rmmSubject
+	if subject == "" {
+		subject = ">"
+	}
+
+	var cutoff time.Time
+	if c.rmmOlderThan != "" {
+		age, err := fisk.ParseDuration(c.rmmOlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than duration: %w", err)
+		}
+		cutoff = time.Now().Add(-age)
+	}
+
+	seqs, err := c.rmMsgSequences(stream, subject, cutoff)
+	if err != nil {
+		return fmt.Errorf("could not find matching messages: %w", err)
+	}
+
+	if len(seqs) == 0 {
+		fmt.Printf("No matching messages in Stream %s\n", stream.Name())
+		return nil
+	}
+
+	if c.dryRun {
+		fmt.Printf("Would remove %s messages from Stream %s\n", f(len(seqs)), stream.Name())
+		return nil
+	}
+
+	if !c.force {
+		ok, err := askConfirmation(fmt.Sprintf("Really remove %s messages from Stream %s", f(len(seqs)), stream.Name()), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	var progbar progress.Writer
+	var tracker *progress.Tracker
+	if c.showProgress {
+		progbar, tracker, err = iu.NewProgress(opts(), &progress.Tracker{Total: int64(len(seqs))})
+		if err != nil {
+			return err
+		}
+	}
+
+	var removed int
+	for _, seq := range seqs {
+		err = stream.DeleteMessageRequest(api.JSApiMsgDeleteRequest{Seq: seq, NoErase: !c.rmmErase})
+		// the message might have been removed by limits or another client in the meantime
+		if err != nil && !jsm.IsNatsError(err, 10037) {
+			if progbar != nil {
+				progbar.Stop()
+			}
+			return fmt.Errorf("could not remove message %d after removing %d messages: %w", seq, removed, err)
+		}
+		if err == nil {
+			removed++
+		}
+
+		if tracker != nil {
+			tracker.Increment(1)
+		}
+	}
+
+	if progbar != nil {
+		tracker.MarkAsDone()
+		time.Sleep(300 * time.Millisecond)
+		progbar.Stop()
+	}
+
+	fmt.Printf("Removed %s messages from Stream %s\n", f(removed), stream.Name())
+
+	return nil
 }
 
 func (c *streamCmd) getAction(_ *fisk.ParseContext) (err error) {
 	c.connectAndAskStream()
 
+	if c.getFrom > 0 || c.getTo > 0 || c.getLast > 0 {
+		return c.getRangeAction()
+	}
+
 	if c.msgID == -1 && c.filterSubject == "" {
 		id := ""
 		err = iu.AskOne(&survey.Input{
@@ -3610,6 +6692,12 @@ func (c *streamCmd) getAction(_ *fisk.ParseContext) (err error) {
 		return nil
 	}
 
+	c.showStoredMsg(item)
+
+	return nil
+}
+
+func (c *streamCmd) showStoredMsg(item *api.StoredMsg) {
 	fmt.Printf("Item: %s#%d received %v (%s) on Subject %s\n\n", c.stream, item.Sequence, item.Time, f(time.Since(item.Time)), item.Subject)
 
 	if len(item.Header) > 0 {
@@ -3625,9 +6713,179 @@ func (c *streamCmd) getAction(_ *fisk.ParseContext) (err error) {
 		fmt.Println()
 	}
 	outPutMSGBody(item.Data, c.vwTranslate, item.Subject, c.stream)
+}
+
+func (c *streamCmd) getRangeAction() error {
+	if c.getLast > 0 && (c.getFrom > 0 || c.getTo > 0) {
+		return fmt.Errorf("--last cannot be combined with --from or --to")
+	}
+	if c.getTo > 0 && c.getFrom > c.getTo {
+		return fmt.Errorf("--from cannot be larger than --to")
+	}
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	state, err := stream.State()
+	if err != nil {
+		return err
+	}
+
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return err
+	}
+
+	from, to := c.getFrom, c.getTo
+	if to == 0 || to > state.LastSeq {
+		to = state.LastSeq
+	}
+
+	// --last counts messages, deleted messages inside the range are not counted
+	var skip uint64
+	if c.getLast > 0 {
+		from, skip, err = streamTailStart(js, stream, "", int(c.getLast))
+		if err != nil {
+			return err
+		}
+	}
+	if from < state.FirstSeq {
+		from = state.FirstSeq
+	}
+
+	var msgs []*api.StoredMsg
+	if state.Msgs > 0 && from <= to {
+		if stream.DirectAllowed() {
+			msgs, err = c.directGetRange(js, stream, from, to)
+		} else {
+			msgs, err = c.readRange(stream, from, to)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if skip > 0 {
+		msgs = msgs[min(skip, uint64(len(msgs))):]
+	}
+
+	if c.json {
+		if msgs == nil {
+			msgs = []*api.StoredMsg{}
+		}
+		return iu.PrintJSON(msgs)
+	}
+
+	if len(msgs) == 0 {
+		fmt.Printf("No messages found in %s between sequences %d and %d\n", c.stream, from, to)
+		return nil
+	}
+
+	for i, msg := range msgs {
+		if i > 0 {
+			fmt.Println()
+		}
+		c.showStoredMsg(msg)
+	}
+
 	return nil
 }
 
+// directGetRange retrieves messages between from and to using batched direct gets
+func (c *streamCmd) directGetRange(js jetstream.JetStream, stream *jsm.Stream, from uint64, to uint64) ([]*api.StoredMsg, error) {
+	const batchSize = 256
+	var msgs []*api.StoredMsg
+
+	seq := from
+	for seq <= to {
+		size := int(min(batchSize, to-seq+1))
+
+		batch, err := jetstreamext.GetBatch(ctx, js, stream.Name(), size, jetstreamext.GetBatchSeq(seq))
+		if err != nil {
+			return nil, err
+		}
+
+		var received int
+		done := false
+		for m, err := range batch {
+			// the remainder of the range holds only deleted messages
+			if errors.Is(err, jetstreamext.ErrNoMessages) {
+				done = true
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			received++
+			if m.Sequence > to {
+				done = true
+				break
+			}
+
+			msgs = append(msgs, storedMsgFromDirect(m))
+			seq = m.Sequence + 1
+		}
+
+		// a short batch means the end of the stream was reached
+		if done || received < size {
+			break
+		}
+	}
+
+	return msgs, nil
+}
+
+// readRange retrieves messages between from and to one at a time for streams without direct get
+func (c *streamCmd) readRange(stream *jsm.Stream, from uint64, to uint64) ([]*api.StoredMsg, error) {
+	var msgs []*api.StoredMsg
+
+	for seq := from; seq <= to; seq++ {
+		msg, err := stream.ReadMessage(seq)
+		if jsm.IsNatsError(err, 10037) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// storedMsgFromDirect converts a direct get response into the same form as a message get
+func storedMsgFromDirect(m *jetstream.RawStreamMsg) *api.StoredMsg {
+	msg := &api.StoredMsg{
+		Subject:  m.Subject,
+		Sequence: m.Sequence,
+		Data:     m.Data,
+		Time:     m.Time,
+	}
+
+	var hdr bytes.Buffer
+	for k, vals := range m.Header {
+		switch k {
+		case jetstream.StreamHeader, jetstream.SubjectHeader, jetstream.SequenceHeader, jetstream.TimeStampHeaer, "Nats-Num-Pending", "Nats-Last-Sequence", "Nats-UpTo-Sequence":
+			continue
+		}
+
+		for _, v := range vals {
+			fmt.Fprintf(&hdr, "%s: %s\r\n", k, v)
+		}
+	}
+
+	if hdr.Len() > 0 {
+		msg.Header = append([]byte("NATS/1.0\r\n"), hdr.Bytes()...)
+		msg.Header = append(msg.Header, '\r', '\n')
+	}
+
+	return msg
+}
+
 func (c *streamCmd) connectAndAskStream() bool {
 	var err error
 
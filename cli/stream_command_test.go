@@ -0,0 +1,103 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jsm.go/api"
+)
+
+func TestUnsupportedStreamFeatures(t *testing.T) {
+	cfg := &api.StreamConfig{
+		Name:             "TEST",
+		MaxMsgsPer:       10,
+		Compression:      api.S2Compression,
+		SubjectTransform: &api.SubjectTransformConfig{Source: "in.>", Destination: "out.>"},
+		AllowMsgTTL:      true,
+		AllowMsgCounter:  true,
+		Metadata:         map[string]string{api.JsMetaRequiredServerLevel: "2"},
+	}
+
+	// servers before 2.11 report no API level so the version is used
+	problems := unsupportedStreamFeatures(cfg, 0, "2.9.15")
+	if len(problems) != 4 {
+		t.Fatalf("expected 4 problems got %v", problems)
+	}
+	for i, field := range []string{"subject_transform", "compression", "allow_msg_ttl", "allow_msg_counter"} {
+		if !strings.HasPrefix(problems[i], field+": ") {
+			t.Fatalf("expected problem %d to be for %s got %q", i, field, problems[i])
+		}
+	}
+
+	problems = unsupportedStreamFeatures(cfg, 1, "2.11.4")
+	if len(problems) != 1 || problems[0] != "allow_msg_counter: Distributed Counters requires NATS Server 2.12.0" {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+
+	// the connected server is new enough but the JetStream API level is lower, for example during upgrades
+	problems = unsupportedStreamFeatures(cfg, 1, "2.12.1")
+	if len(problems) != 1 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+
+	// the connected server is older but the JetStream API level supports all settings
+	problems = unsupportedStreamFeatures(cfg, 2, "2.10.0")
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+
+	problems = unsupportedStreamFeatures(&api.StreamConfig{Name: "TEST", MaxAge: time.Hour}, 0, "2.2.0")
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+}
+
+func TestPreviewStreamPlacement(t *testing.T) {
+	candidates := func() []placementCandidate {
+		return []placementCandidate{
+			{Name: "s1", Cluster: "east", Tags: []string{"ssd"}, Used: 100, Max: 1000},
+			{Name: "s2", Cluster: "east", Tags: []string{"SSD", "gpu"}, Used: 100, Max: 2000},
+			{Name: "s3", Cluster: "east", Used: 0, Max: 1000},
+			{Name: "s4", Cluster: "west", Tags: []string{"ssd"}, Used: 0, Max: 5000},
+			{Name: "s5", Cluster: "east", Problem: "JetStream disabled"},
+		}
+	}
+
+	peers := previewStreamPlacement(api.StreamConfig{Replicas: 3}, "east", candidates())
+	if strings.Join(peers, ",") != "s2,s3,s1" {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+
+	cands := candidates()
+	peers = previewStreamPlacement(api.StreamConfig{Replicas: 3, Placement: &api.Placement{Tags: []string{"ssd"}}}, "east", cands)
+	if strings.Join(peers, ",") != "s2,s1" {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+	if cands[2].Problem != "missing tag ssd" || cands[3].Problem != "not in cluster east" || cands[4].Problem != "JetStream disabled" {
+		t.Fatalf("unexpected problems %+v", cands)
+	}
+
+	peers = previewStreamPlacement(api.StreamConfig{Replicas: 1, MaxBytes: 1500, Placement: &api.Placement{Cluster: "west"}}, "east", candidates())
+	if strings.Join(peers, ",") != "s4" {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+
+	peers = previewStreamPlacement(api.StreamConfig{Replicas: 1, MaxBytes: 5000}, "east", candidates())
+	if len(peers) != 0 {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+}
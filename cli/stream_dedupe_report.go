@@ -0,0 +1,277 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/nats.go/jetstream"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// streamDedupeWindowMultiple is how many duplicate windows of the stream are scanned by default, messages with the
+// same ID are only stored when published further apart than the duplicate window so a single window finds few
+const streamDedupeWindowMultiple = 10
+
+// streamDedupeGroup holds duplicate statistics for a subject or producer
+type streamDedupeGroup struct {
+	Name              string        `json:"name"`
+	Messages          int           `json:"messages"`
+	WithoutID         int           `json:"without_id"`
+	DuplicateIDs      int           `json:"duplicate_ids"`
+	DuplicateMessages int           `json:"duplicate_messages"`
+	LargestGap        time.Duration `json:"largest_gap"`
+}
+
+// streamDedupeID is a message id that was stored more than once
+type streamDedupeID struct {
+	ID       string    `json:"id"`
+	Count    int       `json:"count"`
+	Subjects []string  `json:"subjects"`
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+}
+
+type streamDedupeReport struct {
+	Stream          string               `json:"stream"`
+	Window          time.Duration        `json:"window"`
+	DuplicateWindow time.Duration        `json:"duplicate_window"`
+	Messages        int                  `json:"messages"`
+	Groups          []*streamDedupeGroup `json:"groups"`
+	Duplicates      []*streamDedupeID    `json:"duplicates"`
+}
+
+type streamDedupeMsg struct {
+	subject  string
+	group    string
+	id       string
+	received time.Time
+}
+
+// streamDedupeSeen is a message id that was stored once so far
+type streamDedupeSeen struct {
+	subject  string
+	received time.Time
+}
+
+// streamDedupeAnalysis groups messages by group and id as they are received, messages must be added in stream order
+type streamDedupeAnalysis struct {
+	messages int
+	groups   map[string]*streamDedupeGroup
+	seen     map[string]streamDedupeSeen
+	dupes    map[string]*streamDedupeID
+}
+
+func newStreamDedupeAnalysis() *streamDedupeAnalysis {
+	return &streamDedupeAnalysis{
+		groups: make(map[string]*streamDedupeGroup),
+		seen:   make(map[string]streamDedupeSeen),
+		dupes:  make(map[string]*streamDedupeID),
+	}
+}
+
+func (a *streamDedupeAnalysis) add(msg streamDedupeMsg) {
+	a.messages++
+
+	grp, ok := a.groups[msg.group]
+	if !ok {
+		grp = &streamDedupeGroup{Name: msg.group}
+		a.groups[msg.group] = grp
+	}
+	grp.Messages++
+
+	if msg.id == "" {
+		grp.WithoutID++
+		return
+	}
+
+	dupe, ok := a.dupes[msg.id]
+	if !ok {
+		first, ok := a.seen[msg.id]
+		if !ok {
+			a.seen[msg.id] = streamDedupeSeen{subject: msg.subject, received: msg.received}
+			return
+		}
+
+		delete(a.seen, msg.id)
+		dupe = &streamDedupeID{ID: msg.id, Count: 1, Subjects: []string{first.subject}, First: first.received, Last: first.received}
+		a.dupes[msg.id] = dupe
+		grp.DuplicateIDs++
+	}
+
+	dupe.Count++
+	grp.DuplicateMessages++
+	if gap := msg.received.Sub(dupe.Last); gap > grp.LargestGap {
+		grp.LargestGap = gap
+	}
+	dupe.Last = msg.received
+	if !slices.Contains(dupe.Subjects, msg.subject) {
+		dupe.Subjects = append(dupe.Subjects, msg.subject)
+	}
+}
+
+// results returns the sorted groups and up to top of the most duplicated ids
+func (a *streamDedupeAnalysis) results(top int) ([]*streamDedupeGroup, []*streamDedupeID) {
+	var gres []*streamDedupeGroup
+	for _, grp := range a.groups {
+		gres = append(gres, grp)
+	}
+	slices.SortFunc(gres, func(a, b *streamDedupeGroup) int {
+		return cmp.Or(cmp.Compare(b.DuplicateMessages, a.DuplicateMessages), strings.Compare(a.Name, b.Name))
+	})
+
+	dres := []*streamDedupeID{}
+	for _, dupe := range a.dupes {
+		dres = append(dres, dupe)
+	}
+	slices.SortFunc(dres, func(a, b *streamDedupeID) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.ID, b.ID))
+	})
+	if top > 0 && len(dres) > top {
+		dres = dres[:top]
+	}
+
+	return gres, dres
+}
+
+func (c *streamCmd) dedupeReportAction(_ *fisk.ParseContext) error {
+	if c.dedupeWindow < 0 {
+		return fmt.Errorf("--window must be greater than 0")
+	}
+
+	c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	if c.dedupeWindow == 0 {
+		c.dedupeWindow = streamDedupeWindowMultiple * stream.DuplicateWindow()
+	}
+
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now().Add(-c.dedupeWindow)
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), jetstream.OrderedConsumerConfig{
+		DeliverPolicy: jetstream.DeliverByStartTimePolicy,
+		OptStartTime:  &start,
+		HeadersOnly:   true,
+	})
+	if err != nil {
+		return err
+	}
+
+	nfo, err := cons.Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	analysis := newStreamDedupeAnalysis()
+	if nfo.NumPending > 0 {
+		iter, err := cons.Messages()
+		if err != nil {
+			return err
+		}
+		defer iter.Stop()
+
+		for {
+			msg, err := iter.Next()
+			if err != nil {
+				return err
+			}
+
+			meta, err := msg.Metadata()
+			if err != nil {
+				return err
+			}
+
+			dmsg := streamDedupeMsg{
+				subject:  msg.Subject(),
+				group:    msg.Subject(),
+				id:       msg.Headers().Get(api.JSMsgId),
+				received: meta.Timestamp,
+			}
+			if c.dedupeProducerHeader != "" {
+				dmsg.group = msg.Headers().Get(c.dedupeProducerHeader)
+				if dmsg.group == "" {
+					dmsg.group = "unknown"
+				}
+			}
+			analysis.add(dmsg)
+
+			if meta.NumPending == 0 {
+				break
+			}
+		}
+	}
+
+	report := streamDedupeReport{
+		Stream:          stream.Name(),
+		Window:          c.dedupeWindow,
+		DuplicateWindow: stream.DuplicateWindow(),
+		Messages:        analysis.messages,
+	}
+	report.Groups, report.Duplicates = analysis.results(c.dedupeTop)
+
+	if c.json {
+		return iu.PrintJSON(report)
+	}
+
+	if report.Messages == 0 {
+		fmt.Printf("No messages received by Stream %s in the last %s\n", stream.Name(), f(c.dedupeWindow))
+		return nil
+	}
+
+	groupHeader := "Subject"
+	if c.dedupeProducerHeader != "" {
+		groupHeader = c.dedupeProducerHeader
+	}
+
+	table := iu.NewTableWriterf(opts(), "Duplicate analysis of %s messages in Stream %s over the last %s", f(report.Messages), stream.Name(), f(c.dedupeWindow))
+	table.AddHeaders(groupHeader, "Messages", "Without ID", "Duplicate IDs", "Duplicate Messages", "Largest Gap")
+	for _, grp := range report.Groups {
+		gap := ""
+		if grp.LargestGap > 0 {
+			gap = f(grp.LargestGap)
+		}
+		table.AddRow(grp.Name, f(grp.Messages), f(grp.WithoutID), f(grp.DuplicateIDs), f(grp.DuplicateMessages), gap)
+	}
+	fmt.Println(table.Render())
+
+	if len(report.Duplicates) == 0 {
+		fmt.Println("No duplicate message IDs were stored")
+		return nil
+	}
+
+	table = iu.NewTableWriter(opts(), "Most Duplicated Message IDs")
+	table.AddHeaders("ID", "Count", "Subjects", "First", "Last")
+	for _, dupe := range report.Duplicates {
+		table.AddRow(dupe.ID, f(dupe.Count), strings.Join(dupe.Subjects, ", "), f(dupe.First.Local()), f(dupe.Last.Local()))
+	}
+	fmt.Println(table.Render())
+
+	fmt.Printf("Publishers retried messages with the same ID outside the %s duplicate window of Stream %s\n", f(stream.DuplicateWindow()), stream.Name())
+
+	return nil
+}
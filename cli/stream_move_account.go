@@ -0,0 +1,356 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/choria-io/fisk"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// stages of a stream move, each stage is recorded in the checkpoint once completed
+const (
+	streamMoveCreated  = "created"
+	streamMoveSynced   = "synced"
+	streamMoveDetached = "detached"
+	streamMoveDone     = "done"
+)
+
+// streamMoveState is the checkpoint kept while moving a stream to another account
+type streamMoveState struct {
+	Stream        string `json:"stream"`
+	Target        string `json:"target"`
+	TargetContext string `json:"target_context"`
+	Stage         string `json:"stage,omitempty"`
+}
+
+func (c *streamCmd) loadStreamMoveState() (*streamMoveState, error) {
+	state := &streamMoveState{Stream: c.stream, Target: c.moveTargetName, TargetContext: c.moveTargetContext}
+	if c.moveCheckpoint == "" || !iu.FileExists(c.moveCheckpoint) {
+		return state, nil
+	}
+
+	cj, err := os.ReadFile(c.moveCheckpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved streamMoveState
+	err = json.Unmarshal(cj, &saved)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", c.moveCheckpoint, err)
+	}
+
+	if saved.Stream != state.Stream || saved.Target != state.Target || saved.TargetContext != state.TargetContext {
+		return nil, fmt.Errorf("checkpoint %s is for moving %s to %s in context %s", c.moveCheckpoint, saved.Stream, saved.Target, saved.TargetContext)
+	}
+
+	return &saved, nil
+}
+
+func (c *streamCmd) saveStreamMoveState(state *streamMoveState, stage string) error {
+	state.Stage = stage
+	if c.moveCheckpoint == "" {
+		return nil
+	}
+
+	sj, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(c.moveCheckpoint, sj)
+}
+
+// streamMoveStageReached determines if the move has completed stage
+func streamMoveStageReached(state *streamMoveState, stage string) bool {
+	order := []string{"", streamMoveCreated, streamMoveSynced, streamMoveDetached, streamMoveDone}
+
+	pos := func(s string) int {
+		for i, o := range order {
+			if o == s {
+				return i
+			}
+		}
+		return -1
+	}
+
+	return pos(state.Stage) >= pos(stage)
+}
+
+func (c *streamCmd) moveAccountAction(_ *fisk.ParseContext) error {
+	if c.moveTargetName == "" {
+		c.moveTargetName = c.stream
+	}
+
+	state, err := c.loadStreamMoveState()
+	if err != nil {
+		return err
+	}
+
+	if state.Stage == streamMoveDone {
+		fmt.Printf("Stream %s was already moved to %s\n", c.stream, c.moveTargetName)
+		return nil
+	}
+
+	targetNc, targetMgr, _, err := connectToContext(c.moveTargetContext)
+	if err != nil {
+		return fmt.Errorf("could not connect to context %s: %w", c.moveTargetContext, err)
+	}
+	defer targetNc.Close()
+
+	if c.moveAbort {
+		return c.abortStreamMove(targetMgr, state)
+	}
+
+	c.nc, c.mgr, err = prepareHelper("", natsOpts()...)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	if !c.force && state.Stage == "" {
+		ok, err := askConfirmation(fmt.Sprintf("Really move Stream %s to %s using context %s", c.stream, c.moveTargetName, c.moveTargetContext), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	if !streamMoveStageReached(state, streamMoveCreated) {
+		if c.moveAPIPrefix == "" {
+			return fmt.Errorf("--api-prefix is required to source from the original account")
+		}
+
+		cfg := stream.Configuration()
+		cfg.Name = c.moveTargetName
+		cfg.Mirror = nil
+		cfg.Sources = []*api.StreamSource{{
+			Name: c.stream,
+			External: &api.ExternalStream{
+				ApiPrefix:     c.moveAPIPrefix,
+				DeliverPrefix: c.moveDeliverPrefix,
+			},
+		}}
+
+		_, err = targetMgr.NewStreamFromDefault(cfg.Name, cfg)
+		if err != nil {
+			return fmt.Errorf("could not create Stream %s: %w", cfg.Name, err)
+		}
+
+		fmt.Printf("Created Stream %s sourcing from %s\n", cfg.Name, c.stream)
+
+		err = c.saveStreamMoveState(state, streamMoveCreated)
+		if err != nil {
+			return err
+		}
+	}
+
+	target, err := targetMgr.LoadStream(c.moveTargetName)
+	if err != nil {
+		return fmt.Errorf("could not load Stream %s: %w", c.moveTargetName, err)
+	}
+
+	if !streamMoveStageReached(state, streamMoveSynced) {
+		err = c.waitStreamMoveSynced(stream, target)
+		if err != nil {
+			return err
+		}
+
+		err = c.saveStreamMoveState(state, streamMoveSynced)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !streamMoveStageReached(state, streamMoveDetached) {
+		if !c.moveDetach {
+			fmt.Printf("Stream %s is sourcing from %s, rerun without --no-detach once publishers are moved\n", c.moveTargetName, c.stream)
+			return nil
+		}
+
+		if !c.force {
+			fmt.Println()
+			ok, err := askConfirmation(fmt.Sprintf("Stream %s has caught up, stop publishing to %s and detach it from the original", c.moveTargetName, c.stream), false)
+			fisk.FatalIfError(err, "could not obtain confirmation")
+
+			if !ok {
+				fmt.Println("Rerun the command once publishers are moved to continue the move")
+				return nil
+			}
+		}
+
+		// messages published before the publishers were moved must also be sourced
+		err = c.waitStreamMoveSynced(stream, target)
+		if err != nil {
+			return err
+		}
+
+		cfg := target.Configuration()
+		cfg.Sources = nil
+		err = target.UpdateConfiguration(cfg)
+		if err != nil {
+			return fmt.Errorf("could not detach Stream %s: %w", c.moveTargetName, err)
+		}
+
+		fmt.Printf("Detached Stream %s from %s\n", c.moveTargetName, c.stream)
+
+		err = c.saveStreamMoveState(state, streamMoveDetached)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch c.moveOriginal {
+	case "purge":
+		err = stream.Purge()
+		if err != nil {
+			return fmt.Errorf("could not purge Stream %s: %w", c.stream, err)
+		}
+		fmt.Printf("Purged Stream %s\n", c.stream)
+
+	case "remove":
+		err = stream.Delete()
+		if err != nil {
+			return fmt.Errorf("could not remove Stream %s: %w", c.stream, err)
+		}
+		fmt.Printf("Removed Stream %s\n", c.stream)
+	}
+
+	err = c.saveStreamMoveState(state, streamMoveDone)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Moved Stream %s to %s\n", c.stream, c.moveTargetName)
+
+	return nil
+}
+
+// waitStreamMoveSynced waits for target to source all the messages in the original stream
+func (c *streamCmd) waitStreamMoveSynced(original *jsm.Stream, target *jsm.Stream) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if c.moveWait > 0 {
+		var tcancel context.CancelFunc
+		ctx, tcancel = context.WithTimeout(ctx, c.moveWait)
+		defer tcancel()
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	var lastLag uint64 = 1<<64 - 1
+
+	for {
+		nfo, err := target.Information()
+		if err != nil {
+			return err
+		}
+
+		if len(nfo.Sources) == 1 {
+			src := nfo.Sources[0]
+			if src.Error != nil {
+				return fmt.Errorf("sourcing from %s failed: %s", original.Name(), src.Error.Description)
+			}
+
+			ostate, err := original.State()
+			if err != nil {
+				return err
+			}
+
+			if src.Active >= 0 && src.Lag == 0 && nfo.State.Msgs >= ostate.Msgs {
+				fmt.Printf("Stream %s has caught up with %s messages\n", target.Name(), f(nfo.State.Msgs))
+				return nil
+			}
+
+			if src.Lag != lastLag {
+				fmt.Printf("Waiting for Stream %s to catch up, %s of %s messages sourced with lag %s\n", target.Name(), f(nfo.State.Msgs), f(ostate.Msgs), f(src.Lag))
+				lastLag = src.Lag
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("stream %s did not catch up within %v, rerun the command to resume", target.Name(), c.moveWait)
+			}
+			return fmt.Errorf("interrupted while waiting for Stream %s to catch up, rerun the command to resume", target.Name())
+		}
+	}
+}
+
+// abortStreamMove removes the target stream of a move that has not yet been detached from the original
+func (c *streamCmd) abortStreamMove(targetMgr *jsm.Manager, state *streamMoveState) error {
+	if streamMoveStageReached(state, streamMoveDetached) {
+		return fmt.Errorf("cannot abort the move of Stream %s, %s was already detached from it", state.Stream, state.Target)
+	}
+
+	target, err := targetMgr.LoadStream(state.Target)
+	if err != nil {
+		return fmt.Errorf("could not load Stream %s: %w", state.Target, err)
+	}
+
+	sourcing := false
+	for _, src := range target.Sources() {
+		if src.Name == state.Stream && src.External != nil {
+			sourcing = true
+		}
+	}
+	if !sourcing {
+		return fmt.Errorf("stream %s is not sourcing from %s, refusing to remove it", state.Target, state.Stream)
+	}
+
+	if !c.force {
+		ok, err := askConfirmation(fmt.Sprintf("Really abort the move by removing Stream %s", state.Target), false)
+		fisk.FatalIfError(err, "could not obtain confirmation")
+
+		if !ok {
+			return nil
+		}
+	}
+
+	err = target.Delete()
+	if err != nil {
+		return fmt.Errorf("could not remove Stream %s: %w", state.Target, err)
+	}
+
+	if c.moveCheckpoint != "" {
+		err = os.Remove(c.moveCheckpoint)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	fmt.Printf("Aborted the move of Stream %s, removed Stream %s\n", state.Stream, state.Target)
+
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/jsm.go/serverdata"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// placementCandidate is a server that could host a replica of a new stream
+type placementCandidate struct {
+	Name    string
+	Cluster string
+	Tags    []string
+	Used    uint64
+	Max     int64
+	Streams int
+	Problem string
+}
+
+func (p placementCandidate) available() uint64 {
+	if p.Max <= 0 || uint64(p.Max) < p.Used {
+		return 0
+	}
+
+	return uint64(p.Max) - p.Used
+}
+
+// previewStreamPlacement marks candidates that can not host cfg and returns the likely peers, preferring the servers
+// with the most available storage like the server does when selecting peers
+func previewStreamPlacement(cfg api.StreamConfig, defaultCluster string, candidates []placementCandidate) []string {
+	cluster := defaultCluster
+	var tags []string
+	if cfg.Placement != nil {
+		if cfg.Placement.Cluster != "" {
+			cluster = cfg.Placement.Cluster
+		}
+		tags = cfg.Placement.Tags
+	}
+
+	var eligible []placementCandidate
+	for i := range candidates {
+		cand := &candidates[i]
+
+		switch {
+		case cand.Problem != "":
+		case cluster != "" && cand.Cluster != cluster:
+			cand.Problem = fmt.Sprintf("not in cluster %s", cluster)
+		case cfg.MaxBytes > 0 && cand.available() < uint64(cfg.MaxBytes):
+			cand.Problem = "insufficient storage"
+		default:
+			for _, tag := range tags {
+				if !slices.ContainsFunc(cand.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+					cand.Problem = fmt.Sprintf("missing tag %s", tag)
+					break
+				}
+			}
+		}
+
+		if cand.Problem == "" {
+			eligible = append(eligible, *cand)
+		}
+	}
+
+	slices.SortFunc(eligible, func(a, b placementCandidate) int {
+		res := cmp.Compare(b.available(), a.available())
+		if res == 0 {
+			res = strings.Compare(a.Name, b.Name)
+		}
+		return res
+	})
+
+	var peers []string
+	for i := 0; i < len(eligible) && i < max(cfg.Replicas, 1); i++ {
+		peers = append(peers, eligible[i].Name)
+	}
+
+	return peers
+}
+
+// placementCandidates queries all servers for their JetStream state, requires system account access
+func (c *streamCmd) placementCandidates(nc *nats.Conn, storage api.StorageType) ([]placementCandidate, error) {
+	if c.systemContext != "" {
+		var err error
+		nc, _, _, err = connectToContext(c.systemContext)
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+	}
+
+	expected, err := serverdata.CurrentActiveServers(ctx, nc, opts().Timeout, traceLogger())
+	if err != nil {
+		return nil, fmt.Errorf("could not determine active servers: %w", err)
+	}
+
+	reqFn := func(req any, subj string, waitFor int, nc *nats.Conn) ([][]byte, error) {
+		return serverdata.DoReq(ctx, req, subj, waitFor, nc, opts().Timeout, traceLogger())
+	}
+
+	ds, err := serverdata.NewLive(nc, reqFn, expected)
+	if err != nil {
+		return nil, err
+	}
+	defer ds.Close()
+
+	responses, err := ds.Jsz(server.JszEventOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []placementCandidate
+	for _, resp := range responses {
+		if resp.Server == nil {
+			continue
+		}
+
+		cand := placementCandidate{
+			Name:    resp.Server.Name,
+			Cluster: resp.Server.Cluster,
+			Tags:    resp.Server.Tags,
+		}
+
+		switch {
+		case resp.Data == nil || resp.Data.Disabled:
+			cand.Problem = "JetStream disabled"
+		case storage == api.MemoryStorage:
+			cand.Used, cand.Max, cand.Streams = resp.Data.Memory, resp.Data.Config.MaxMemory, resp.Data.Streams
+		default:
+			cand.Used, cand.Max, cand.Streams = resp.Data.Store, resp.Data.Config.MaxStore, resp.Data.Streams
+		}
+
+		candidates = append(candidates, cand)
+	}
+
+	slices.SortFunc(candidates, func(a, b placementCandidate) int {
+		return cmp.Or(strings.Compare(a.Cluster, b.Cluster), strings.Compare(a.Name, b.Name))
+	})
+
+	return candidates, nil
+}
+
+// shouldPreviewPlacement determines if the placement preview is shown, unless requested using flags it is only shown for
+// interactive adds of replicated streams when the connection has system account access
+func (c *streamCmd) shouldPreviewPlacement(nc *nats.Conn, cfg api.StreamConfig, interactive bool) bool {
+	switch {
+	case c.previewPlacementSet:
+		return c.previewPlacement
+	case c.systemContext != "":
+		return true
+	case !interactive || cfg.Replicas <= 1:
+		return false
+	}
+
+	// only the system account can reach the servers, others get no responders
+	_, err := nc.Request("$SYS.REQ.SERVER.PING", nil, opts().Timeout)
+
+	return err == nil
+}
+
+// showPlacementPreview renders the likely placement of cfg and reports if the placement can not be satisfied
+func (c *streamCmd) showPlacementPreview(nc *nats.Conn, cfg api.StreamConfig) (bool, error) {
+	candidates, err := c.placementCandidates(nc, cfg.Storage)
+	if err != nil {
+		return false, fmt.Errorf("placement preview requires system account access: %w", err)
+	}
+
+	peers := previewStreamPlacement(cfg, nc.ConnectedClusterName(), candidates)
+
+	table := iu.NewTableWriter(opts(), "Placement Preview")
+	table.AddHeaders("Server", "Cluster", "Tags", "Streams", "Storage Used", "Storage Available", "Placement")
+	for _, cand := range candidates {
+		placement := cand.Problem
+		if slices.Contains(peers, cand.Name) {
+			placement = "likely"
+		}
+
+		table.AddRow(cand.Name, cand.Cluster, strings.Join(cand.Tags, ", "), f(cand.Streams), humanize.IBytes(cand.Used), humanize.IBytes(cand.available()), placement)
+	}
+	fmt.Println(table.Render())
+
+	if len(peers) < cfg.Replicas {
+		fmt.Printf("WARNING: only %d servers match the placement of Stream %s which requires %d replicas\n\n", len(peers), cfg.Name, cfg.Replicas)
+		return false, nil
+	}
+
+	fmt.Printf("Stream %s will likely be placed on %s\n\n", cfg.Name, strings.Join(peers, ", "))
+
+	return true, nil
+}
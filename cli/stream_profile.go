@@ -0,0 +1,258 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nats-io/jsm.go/api"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// streamProfilePath is the location of a named stream profile in the configuration directory
+func streamProfilePath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid profile name %q", name)
+	}
+
+	parent, err := iu.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine configuration directory: %w", err)
+	}
+
+	return filepath.Join(parent, "profiles", "stream", name+".json"), nil
+}
+
+// applyStreamProfile uses the settings present in a profile as defaults for any options not set on the command line,
+// settings that are not in the profile are left to flags and prompts as usual
+func (c *streamCmd) applyStreamProfile(name string) error {
+	path, err := streamProfilePath(name)
+	if err != nil {
+		return err
+	}
+
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unknown stream profile %q, expected it in %s", name, path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// zero values are valid settings so we track which keys the profile actually holds
+	var keys map[string]json.RawMessage
+	err = json.Unmarshal(body, &keys)
+	if err != nil {
+		return fmt.Errorf("invalid stream profile %s: %w", path, err)
+	}
+
+	var p api.StreamConfig
+	err = json.Unmarshal(body, &p)
+	if err != nil {
+		return fmt.Errorf("invalid stream profile %s: %w", path, err)
+	}
+
+	has := func(key string) bool {
+		_, ok := keys[key]
+		return ok
+	}
+
+	// limits of 0 are unlimited in the server but cause prompts here
+	unlimited := func(v int64) int64 {
+		if v == 0 {
+			return -1
+		}
+		return v
+	}
+
+	if has("description") && c.description == "" {
+		c.description = p.Description
+	}
+
+	if has("subjects") && len(c.subjects) == 0 && c.mirror == "" && len(c.sources) == 0 {
+		c.subjects = p.Subjects
+	}
+
+	if has("storage") && c.storage == "" {
+		c.storage = "file"
+		if p.Storage == api.MemoryStorage {
+			c.storage = "memory"
+		}
+	}
+
+	if has("compression") && !c.compressionSet {
+		c.compression = "none"
+		if p.Compression == api.S2Compression {
+			c.compression = "s2"
+		}
+	}
+
+	if has("num_replicas") && c.replicas == 0 {
+		c.replicas = int64(p.Replicas)
+	}
+
+	if has("retention") && c.retentionPolicyS == "" {
+		switch p.Retention {
+		case api.InterestPolicy:
+			c.retentionPolicyS = "interest"
+		case api.WorkQueuePolicy:
+			c.retentionPolicyS = "work"
+		default:
+			c.retentionPolicyS = "limits"
+		}
+	}
+
+	if has("discard") && c.discardPolicy == "" {
+		c.discardPolicy = "old"
+		if p.Discard == api.DiscardNew {
+			c.discardPolicy = "new"
+		}
+	}
+
+	if has("discard_new_per_subject") && !c.discardPerSubjSet {
+		c.discardPerSubj = p.DiscardNewPer
+	}
+
+	if has("max_msgs") && c.maxMsgLimit == 0 {
+		c.maxMsgLimit = unlimited(p.MaxMsgs)
+	}
+
+	if has("max_msgs_per_subject") && c.maxMsgPerSubjectLimit == 0 {
+		c.maxMsgPerSubjectLimit = unlimited(p.MaxMsgsPer)
+	}
+
+	// the string forms are set too so that --defaults does not override these
+	if has("max_bytes") && c.maxBytesLimitString == "" {
+		c.maxBytesLimit = unlimited(p.MaxBytes)
+		c.maxBytesLimitString = strconv.FormatInt(c.maxBytesLimit, 10)
+	}
+
+	if has("max_msg_size") && c.maxMsgSizeString == "" {
+		c.maxMsgSize = unlimited(int64(p.MaxMsgSize))
+		c.maxMsgSizeString = strconv.FormatInt(c.maxMsgSize, 10)
+	}
+
+	if has("max_age") && c.maxAgeLimit == "" {
+		c.maxAgeLimit = "-1"
+		if p.MaxAge > 0 {
+			c.maxAgeLimit = p.MaxAge.String()
+		}
+	}
+
+	if has("duplicate_window") && c.dupeWindow == "" && p.Duplicates > 0 {
+		c.dupeWindow = p.Duplicates.String()
+	}
+
+	if has("max_consumers") && c.maxConsumers == -1 {
+		c.maxConsumers = int(unlimited(int64(p.MaxConsumers)))
+	}
+
+	if has("no_ack") && !c.ackSet {
+		c.ack = !p.NoAck
+	}
+
+	if p.Placement != nil {
+		if !c.placementClusterSet {
+			c.placementCluster = p.Placement.Cluster
+		}
+		if !c.placementTagsSet {
+			c.placementTags = p.Placement.Tags
+		}
+	}
+
+	if has("allow_rollup_hdrs") && !c.allowRollupSet {
+		c.allowRollup, c.allowRollupSet = p.RollupAllowed, true
+	}
+
+	if has("deny_delete") && !c.denyDeleteSet {
+		c.denyDelete, c.denyDeleteSet = p.DenyDelete, true
+	}
+
+	if has("deny_purge") && !c.denyPurgeSet {
+		c.denyPurge, c.denyPurgeSet = p.DenyPurge, true
+	}
+
+	if has("allow_direct") && !c.allowDirectSet {
+		c.allowDirect = p.AllowDirect
+	}
+
+	if has("mirror_direct") && !c.allowMirrorDirectSet {
+		c.allowMirrorDirect = p.MirrorDirect
+	}
+
+	if has("allow_msg_ttl") && !c.allowMsgTTlSet {
+		c.allowMsgTTL = p.AllowMsgTTL
+	}
+
+	if has("subject_delete_marker_ttl") && !c.subjectDeleteMarkerTTLSet {
+		c.subjectDeleteMarkerTTL = p.SubjectDeleteMarkerTTL
+	}
+
+	if has("allow_atomic") && !c.allowAtomicBatchIsSet {
+		c.allowAtomicBatch = p.AllowAtomicPublish
+	}
+
+	if has("allow_batched") && !c.allowFastBatchIsSet {
+		c.allowFastBatch = p.AllowBatchPublish
+	}
+
+	if has("allow_msg_counter") && !c.allowCounterIsSet {
+		c.allowCounter = p.AllowMsgCounter
+	}
+
+	if has("allow_msg_schedules") && !c.allowSchedulesSet {
+		c.allowSchedules = p.AllowMsgSchedules
+	}
+
+	if c.limitInactiveThreshold == 0 {
+		c.limitInactiveThreshold = p.ConsumerLimits.InactiveThreshold
+	}
+
+	if c.limitMaxAckPending == 0 {
+		c.limitMaxAckPending = p.ConsumerLimits.MaxAckPending
+	}
+
+	if p.SubjectTransform != nil && c.subjectTransformSource == "" && c.subjectTransformDest == "" {
+		c.subjectTransformSource = p.SubjectTransform.Source
+		c.subjectTransformDest = p.SubjectTransform.Destination
+	}
+
+	if p.RePublish != nil && c.repubSource == "" && c.repubDest == "" {
+		c.repubSource = p.RePublish.Source
+		if c.repubSource == "" {
+			c.repubSource = ">"
+		}
+		c.repubDest = p.RePublish.Destination
+		c.repubHeadersOnly = p.RePublish.HeadersOnly
+	}
+
+	if c.persistMode == "" && p.PersistMode == api.AsyncPersistMode {
+		c.persistMode = "async"
+	}
+
+	for k, v := range iu.RemoveReservedMetadata(p.Metadata) {
+		_, ok := c.metadata[k]
+		if !ok {
+			c.metadata[k] = v
+		}
+	}
+
+	return nil
+}
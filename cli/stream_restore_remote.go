@@ -0,0 +1,419 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// remoteBackup is a stream backup stored on a HTTP server or in S3, either as a directory holding backup.json and
+// stream.tar.s2 or as a tar archive holding both files with backup.json first
+type remoteBackup struct {
+	url     *url.URL
+	archive bool
+	retries int
+	client  *http.Client
+}
+
+// isRemoteBackup determines if the restore source is a URL rather than a local directory
+func isRemoteBackup(source string) bool {
+	for _, scheme := range []string{"http://", "https://", "s3://"} {
+		if strings.HasPrefix(source, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newRemoteBackup(source string, retries int) (*remoteBackup, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "s3" && (u.Host == "" || strings.Trim(u.Path, "/") == "") {
+		return nil, fmt.Errorf("s3 URLs must be in the form s3://bucket/key")
+	}
+
+	rb := &remoteBackup{
+		url:     u,
+		retries: retries,
+		client:  &http.Client{},
+	}
+
+	for _, ext := range []string{".tar", ".tgz", ".tar.gz"} {
+		if strings.HasSuffix(u.Path, ext) {
+			rb.archive = true
+		}
+	}
+
+	return rb, nil
+}
+
+// open fetches the backup, returning the metadata and a reader for the stream data along with its size, -1 when unknown
+func (r *remoteBackup) open(ctx context.Context) ([]byte, io.ReadCloser, int64, error) {
+	if r.archive {
+		return r.openArchive(ctx)
+	}
+
+	meta, err := r.fetch(ctx, "backup.json")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer meta.Close()
+
+	mj, err := io.ReadAll(meta)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	data, err := r.fetch(ctx, "stream.tar.s2")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return mj, data, data.size, nil
+}
+
+// openArchive streams the archive, the metadata has to precede the data so the data can be restored while downloading
+func (r *remoteBackup) openArchive(ctx context.Context) ([]byte, io.ReadCloser, int64, error) {
+	body, err := r.fetch(ctx, "")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var rdr io.Reader = body
+	if !strings.HasSuffix(r.url.Path, ".tar") {
+		rdr, err = gzip.NewReader(body)
+		if err != nil {
+			body.Close()
+			return nil, nil, 0, fmt.Errorf("invalid archive: %w", err)
+		}
+	}
+
+	var mj []byte
+	tr := tar.NewReader(rdr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			body.Close()
+			return nil, nil, 0, fmt.Errorf("archive does not contain stream.tar.s2")
+		}
+		if err != nil {
+			body.Close()
+			return nil, nil, 0, fmt.Errorf("invalid archive: %w", err)
+		}
+
+		switch path.Base(hdr.Name) {
+		case "backup.json":
+			mj, err = io.ReadAll(tr)
+			if err != nil {
+				body.Close()
+				return nil, nil, 0, err
+			}
+
+		case "stream.tar.s2":
+			if mj == nil {
+				body.Close()
+				return nil, nil, 0, fmt.Errorf("backup.json must precede stream.tar.s2 in the archive")
+			}
+
+			return mj, struct {
+				io.Reader
+				io.Closer
+			}{tr, body}, hdr.Size, nil
+		}
+	}
+}
+
+// fetch starts downloading file relative to the backup URL, an empty file fetches the URL itself
+func (r *remoteBackup) fetch(ctx context.Context, file string) (*resumableReader, error) {
+	u := *r.url
+	if file != "" {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + file
+		u.RawPath = ""
+	}
+
+	rr := &resumableReader{ctx: ctx, backup: r, url: &u, size: -1, retries: r.retries}
+	err := rr.resume()
+	if err != nil {
+		return nil, err
+	}
+
+	return rr, nil
+}
+
+// request creates a GET request for u, s3:// URLs are translated to the S3 endpoint and signed when credentials are set
+func (r *remoteBackup) request(ctx context.Context, u *url.URL) (*http.Request, error) {
+	if u.Scheme != "s3" {
+		return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_S3")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+
+	var target *url.URL
+	var err error
+	if endpoint != "" {
+		// custom endpoints like minio generally only support path style access
+		target, err = url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + u.Host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+		}
+		target.Path = target.Path + "/" + key
+	} else {
+		target = &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", u.Host, region), Path: "/" + key}
+	}
+	target.RawPath = s3EncodePath(target.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return req, nil
+	}
+
+	s3Sign(req, region, accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), time.Now().UTC())
+
+	return req, nil
+}
+
+// s3EncodePath encodes every path segment as required by AWS signature version 4
+func s3EncodePath(p string) string {
+	var sb strings.Builder
+	for _, b := range []byte(p) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '.', b == '_', b == '~', b == '/':
+			sb.WriteByte(b)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+
+	return sb.String()
+}
+
+// s3Sign signs a GET request using AWS signature version 4 without signing the payload
+func s3Sign(req *http.Request, region string, accessKey string, secretKey string, token string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", "UNSIGNED-PAYLOAD")
+	if token != "" {
+		req.Header.Set("x-amz-security-token", token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for _, h := range []string{"x-amz-date", "x-amz-content-sha256", "x-amz-security-token"} {
+		if v := req.Header.Get(h); v != "" {
+			headers[h] = v
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for h := range headers {
+		names = append(names, h)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, h := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", h, strings.TrimSpace(headers[h]))
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(crHash[:])}, "\n")
+
+	hmacSHA256 := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+// resumableReader reads a HTTP response body resuming the download using range requests when the connection fails
+type resumableReader struct {
+	ctx     context.Context
+	backup  *remoteBackup
+	url     *url.URL
+	body    io.ReadCloser
+	offset  int64
+	size    int64
+	etag    string
+	retries int
+}
+
+func (r *resumableReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+
+		if err == nil || errors.Is(err, io.EOF) || r.ctx.Err() != nil {
+			return n, err
+		}
+
+		if r.retries <= 0 {
+			return n, fmt.Errorf("download failed at %d bytes: %w", r.offset, err)
+		}
+		r.retries--
+
+		if opts().Trace {
+			fmt.Printf(">>> Resuming download of %s at %d bytes after error: %v\n", r.url.Redacted(), r.offset, err)
+		}
+
+		r.body.Close()
+
+		select {
+		case <-time.After(time.Second):
+		case <-r.ctx.Done():
+			return n, r.ctx.Err()
+		}
+
+		rerr := r.resume()
+		if rerr != nil {
+			return n, fmt.Errorf("could not resume download at %d bytes: %w", r.offset, rerr)
+		}
+
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *resumableReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+
+	return r.body.Close()
+}
+
+// resume starts the download at the current offset, the ETag of the first response ensures the file did not change
+func (r *resumableReader) resume() error {
+	req, err := r.backup.request(r.ctx, r.url)
+	if err != nil {
+		return err
+	}
+
+	if r.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+		if r.etag != "" {
+			req.Header.Set("If-Range", r.etag)
+		}
+	}
+
+	resp, err := r.backup.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case r.offset == 0 && resp.StatusCode == http.StatusOK:
+		r.etag = resp.Header.Get("ETag")
+		r.size = resp.ContentLength
+	case r.offset > 0 && resp.StatusCode == http.StatusPartialContent:
+	case r.offset > 0 && resp.StatusCode == http.StatusOK:
+		resp.Body.Close()
+		return fmt.Errorf("%s changed during the download or does not support range requests", r.url.Redacted())
+	default:
+		resp.Body.Close()
+		return fmt.Errorf("%s: %s", r.url.Redacted(), resp.Status)
+	}
+
+	r.body = resp.Body
+
+	return nil
+}
+
+// dataReader holds back io.EOF returned along with data as the restore discards data read with io.EOF
+type dataReader struct {
+	io.ReadCloser
+}
+
+func (d *dataReader) Read(p []byte) (int, error) {
+	n, err := d.ReadCloser.Read(p)
+	if n > 0 && errors.Is(err, io.EOF) {
+		return n, nil
+	}
+
+	return n, err
+}
+
+// countingReader reports the total bytes read to cb
+type countingReader struct {
+	io.ReadCloser
+	read int64
+	cb   func(int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.read += int64(n)
+	c.cb(c.read)
+
+	return n, err
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/progress"
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+type subjectTransformSpec struct {
+	source      string
+	destination string
+	transform   server.SubjectTransformer
+}
+
+func parseSubjectTransforms(specs []string) ([]subjectTransformSpec, error) {
+	var transforms []subjectTransformSpec
+
+	for _, spec := range specs {
+		src, dst, ok := strings.Cut(spec, ":")
+		if !ok || src == "" || dst == "" {
+			return nil, fmt.Errorf("invalid transform %q, expected SOURCE:DESTINATION", spec)
+		}
+
+		tr, err := server.NewSubjectTransform(src, dst)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform %q: %w", spec, err)
+		}
+
+		transforms = append(transforms, subjectTransformSpec{source: src, destination: dst, transform: tr})
+	}
+
+	return transforms, nil
+}
+
+// transformSubject maps subject using the first matching transform, subject may hold wildcards
+func transformSubject(transforms []subjectTransformSpec, subject string) (string, bool) {
+	for _, tr := range transforms {
+		if jsm.SubjectIsSubsetMatch(subject, tr.source) {
+			return tr.transform.TransformSubject(subject), true
+		}
+	}
+
+	return subject, false
+}
+
+// applyRestoreTransforms rewrites the subjects of cfg, every subject has to be matched by a transform
+func applyRestoreTransforms(cfg *api.StreamConfig, transforms []subjectTransformSpec) error {
+	if len(cfg.Subjects) == 0 {
+		return fmt.Errorf("stream %s has no subjects to transform", cfg.Name)
+	}
+
+	subjects := make([]string, len(cfg.Subjects))
+	for i, subj := range cfg.Subjects {
+		mapped, ok := transformSubject(transforms, subj)
+		if !ok {
+			return fmt.Errorf("subject %s is not matched by any transform", subj)
+		}
+		subjects[i] = mapped
+	}
+
+	cfg.Subjects = subjects
+
+	return nil
+}
+
+// transformRestoredData republishes every message in the restored stream through the transforms and then
+// purges the original messages
+func (c *streamCmd) transformRestoredData(stream *jsm.Stream, transforms []subjectTransformSpec) error {
+	nfo, err := stream.LatestInformation()
+	if err != nil {
+		return err
+	}
+
+	if nfo.State.Msgs == 0 {
+		return nil
+	}
+
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return err
+	}
+
+	cutoff := nfo.State.LastSeq
+
+	cons, err := js.OrderedConsumer(ctx, stream.Name(), jetstream.OrderedConsumerConfig{DeliverPolicy: jetstream.DeliverAllPolicy})
+	if err != nil {
+		return err
+	}
+
+	iter, err := cons.Messages()
+	if err != nil {
+		return err
+	}
+	defer iter.Stop()
+
+	var progbar progress.Writer
+	var tracker *progress.Tracker
+	if c.showProgress {
+		progbar, tracker, err = iu.NewProgress(opts(), &progress.Tracker{Total: int64(nfo.State.Msgs)})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Transforming %s messages in Stream %s\n\n", f(nfo.State.Msgs), stream.Name())
+
+	var count uint64
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			return err
+		}
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return err
+		}
+
+		if meta.Sequence.Stream > cutoff {
+			break
+		}
+
+		subj, _ := transformSubject(transforms, msg.Subject())
+		out := nats.NewMsg(subj)
+		out.Data = msg.Data()
+		for k, v := range msg.Headers() {
+			if !republishHeader(k, false, c.restoreKeepTTL) {
+				continue
+			}
+			out.Header[k] = v
+		}
+
+		_, err = js.PublishMsg(ctx, out, jetstream.WithExpectStream(stream.Name()))
+		if err != nil {
+			return fmt.Errorf("could not republish message %d on %s: %w", meta.Sequence.Stream, subj, err)
+		}
+
+		count++
+		if tracker != nil {
+			tracker.SetValue(int64(count))
+		}
+
+		if meta.Sequence.Stream == cutoff {
+			break
+		}
+	}
+
+	if tracker != nil {
+		tracker.MarkAsDone()
+		time.Sleep(300 * time.Millisecond)
+		progbar.Stop()
+		fmt.Println()
+	}
+
+	err = stream.Purge(&api.JSApiStreamPurgeRequest{Sequence: cutoff + 1})
+	if err != nil {
+		return fmt.Errorf("could not purge the original messages: %w", err)
+	}
+
+	fmt.Printf("Republished %s messages through subject transforms\n", f(count))
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/nats.go/jetstream"
+	iu "github.com/nats-io/natscli/internal/util"
+)
+
+// streamSubjectState is the state of a single subject in a stream
+type streamSubjectState struct {
+	Subject      string    `json:"subject"`
+	Messages     uint64    `json:"messages"`
+	FirstSeq     uint64    `json:"first_seq"`
+	LastSeq      uint64    `json:"last_seq"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+func (c *streamCmd) subjectStateAction() error {
+	if c.json && c.csv {
+		return fmt.Errorf("--json and --csv are mutually exclusive")
+	}
+
+	asked := c.connectAndAskStream()
+
+	stream, err := c.loadStream(c.stream)
+	if err != nil {
+		return err
+	}
+
+	states, err := c.subjectStates(stream, c.streamSubjectFilters())
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c.json:
+		return iu.PrintJSON(states)
+
+	case c.csv:
+		w := csv.NewWriter(os.Stdout)
+		err = w.Write([]string{"subject", "messages", "first_seq", "last_seq", "last_activity"})
+		if err != nil {
+			return err
+		}
+		for _, s := range states {
+			err = w.Write([]string{s.Subject, strconv.FormatUint(s.Messages, 10), strconv.FormatUint(s.FirstSeq, 10), strconv.FormatUint(s.LastSeq, 10), s.LastActivity.Format(time.RFC3339Nano)})
+			if err != nil {
+				return err
+			}
+		}
+		w.Flush()
+
+		return w.Error()
+	}
+
+	if asked {
+		fmt.Println()
+	}
+
+	if len(states) == 0 {
+		fmt.Printf("No subjects found matching %s\n", strings.Join(c.streamSubjectFilters(), ", "))
+		return nil
+	}
+
+	table := iu.NewTableWriterf(opts(), "State of %d Subjects in Stream %s", len(states), stream.Name())
+	table.AddHeaders("Subject", "Messages", "First Sequence", "Last Sequence", "Last Activity")
+	for _, s := range states {
+		table.AddRow(s.Subject, f(s.Messages), s.FirstSeq, s.LastSeq, f(time.Since(s.LastActivity)))
+	}
+	fmt.Println(table.Render())
+
+	return nil
+}
+
+// subjectStates walks all subjects matching filters and looks up their first and last messages
+func (c *streamCmd) subjectStates(stream *jsm.Stream, filters []string) ([]streamSubjectState, error) {
+	_, js, err := prepareJSHelper()
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := js.Stream(ctx, stream.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	states := []streamSubjectState{}
+	var serr error
+
+	err = c.eachStreamSubject(stream, filters, streamSubjectsToken{}, func(subject string, count uint64, _ streamSubjectsToken) bool {
+		state := streamSubjectState{Subject: subject, Messages: count}
+
+		first, err := str.GetMsg(ctx, 1, jetstream.WithGetMsgSubject(subject))
+		if err != nil {
+			serr = fmt.Errorf("could not load first message for %s: %w", subject, err)
+			return false
+		}
+		state.FirstSeq = first.Sequence
+
+		last, err := str.GetLastMsgForSubject(ctx, subject)
+		if err != nil {
+			serr = fmt.Errorf("could not load last message for %s: %w", subject, err)
+			return false
+		}
+		state.LastSeq = last.Sequence
+		state.LastActivity = last.Time
+
+		states = append(states, state)
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return states, serr
+}
@@ -593,6 +593,67 @@ func loadContext(softFail bool) error {
 	return err
 }
 
+// connectToContext connects using a context other than the selected one, name is a context name or the path to a context JSON file
+func connectToContext(name string) (*nats.Conn, *jsm.Manager, jetstream.JetStream, error) {
+	registryOpts := []natscontext.RegistryOption{
+		natscontext.WithDefaultResolvers(),
+		natscontext.WithLocalSelector(),
+	}
+
+	registry := natscontext.NewRegistry(natscontext.NewDefaultFileBackend(), registryOpts...)
+	if exist, _ := iu.IsFileAccessible(name); exist && strings.HasSuffix(name, ".json") {
+		backend := natscontext.NewSingleFileBackend(name)
+		registry = natscontext.NewRegistry(backend, registryOpts...)
+		name = backend.Name()
+	}
+
+	nctx, err := registry.Load(ctx, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	copts, err := nctx.NATSOptions()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	nc, err := nats.Connect(nctx.ServerURL(), append(copts, nats.Name("NATS CLI Version "+Version))...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	jsopts, err := nctx.JSMOptions()
+	if err != nil {
+		nc.Close()
+		return nil, nil, nil, err
+	}
+	if opts().Timeout != 0 {
+		jsopts = append(jsopts, jsm.WithTimeout(opts().Timeout))
+	}
+
+	mgr, err := jsm.New(nc, jsopts...)
+	if err != nil {
+		nc.Close()
+		return nil, nil, nil, err
+	}
+
+	var js jetstream.JetStream
+	switch {
+	case nctx.JSDomain() != "":
+		js, err = jetstream.NewWithDomain(nc, nctx.JSDomain(), jetstreamOpts()...)
+	case nctx.JSAPIPrefix() != "":
+		js, err = jetstream.NewWithAPIPrefix(nc, nctx.JSAPIPrefix(), jetstreamOpts()...)
+	default:
+		js, err = jetstream.New(nc, jetstreamOpts()...)
+	}
+	if err != nil {
+		nc.Close()
+		return nil, nil, nil, err
+	}
+
+	return nc, mgr, js, nil
+}
+
 func renderCluster(cluster *api.ClusterInfo) string {
 	if cluster == nil {
 		return ""
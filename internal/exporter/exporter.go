@@ -109,61 +109,95 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	// here is the right, if discouraged, thing to do here.
 }
 
+type checkFunc func(servers string, natsOpts []nats.Option, jsmOpts []jsm.Option, check *Check, result *monitor.Result)
+
 // Collect implements prometheus.Collector
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
-	callCheck := func(check *Check, f func(servers string, natsOpts []nats.Option, jsmOpts []jsm.Option, check *Check, result *monitor.Result)) {
-		result := &monitor.Result{Name: check.Name, Check: check.Kind, NameSpace: e.ns, RenderFormat: monitor.NagiosFormat}
-		defer result.Collect(ch)
-
-		nctx, err := e.natsContext(check)
-		if result.CriticalIfErrf(err, "could not load context: %v", err) {
-			return
-		}
-
-		opts, err := nctx.NATSOptions()
-		if result.CriticalIfErrf(err, "could not load context: %v", err) {
-			return
-		}
-
-		jsmopts, err := nctx.JSMOptions()
-		if result.CriticalIfErrf(err, "could not load jetstream options: %v", err) {
-			return
+	for _, check := range e.config.Checks {
+		f := e.checkFunc(check.Kind)
+		if f == nil {
+			log.Printf("Unknown check kind %s", check.Kind)
+			continue
 		}
 
-		f(nctx.ServerURL(), opts, jsmopts, check, result)
+		result := e.callCheck(check, f)
+		result.Collect(ch)
 		log.Print(result)
 	}
+}
 
-	for _, check := range e.config.Checks {
-		var f func(servers string, natsOpts []nats.Option, jsmOpts []jsm.Option, check *Check, result *monitor.Result)
-
-		switch check.Kind {
-		case "connection":
-			f = e.checkConnection
-		case "stream":
-			f = e.checkStream
-		case "consumer":
-			f = e.checkConsumer
-		case "message":
-			f = e.checkMessage
-		case "meta":
-			f = e.checkMeta
-		case "jetstream":
-			f = e.checkJetStream
-		case "server":
-			f = e.checkServer
-		case "kv":
-			f = e.checkKv
-		case "credential":
-			f = e.checkCredential
-		case "request":
-			f = e.checkRequest
-		default:
-			log.Printf("Unknown check kind %s", check.Kind)
+// RunChecks performs all configured checks concurrently and returns their results in configuration order
+func (e *Exporter) RunChecks() []*monitor.Result {
+	results := make([]*monitor.Result, len(e.config.Checks))
+	wg := sync.WaitGroup{}
+
+	for i, check := range e.config.Checks {
+		f := e.checkFunc(check.Kind)
+		if f == nil {
+			results[i] = &monitor.Result{Name: check.Name, Check: check.Kind, NameSpace: e.ns, RenderFormat: monitor.NagiosFormat}
+			results[i].Criticalf("unknown check kind %s", check.Kind)
 			continue
 		}
 
-		callCheck(check, f)
+		wg.Add(1)
+		go func(i int, check *Check) {
+			defer wg.Done()
+			results[i] = e.callCheck(check, f)
+		}(i, check)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func (e *Exporter) callCheck(check *Check, f checkFunc) *monitor.Result {
+	result := &monitor.Result{Name: check.Name, Check: check.Kind, NameSpace: e.ns, RenderFormat: monitor.NagiosFormat}
+
+	nctx, err := e.natsContext(check)
+	if result.CriticalIfErrf(err, "could not load context: %v", err) {
+		return result
+	}
+
+	opts, err := nctx.NATSOptions()
+	if result.CriticalIfErrf(err, "could not load context: %v", err) {
+		return result
+	}
+
+	jsmopts, err := nctx.JSMOptions()
+	if result.CriticalIfErrf(err, "could not load jetstream options: %v", err) {
+		return result
+	}
+
+	f(nctx.ServerURL(), opts, jsmopts, check, result)
+
+	return result
+}
+
+func (e *Exporter) checkFunc(kind string) checkFunc {
+	switch kind {
+	case "connection":
+		return e.checkConnection
+	case "stream":
+		return e.checkStream
+	case "consumer":
+		return e.checkConsumer
+	case "message":
+		return e.checkMessage
+	case "meta":
+		return e.checkMeta
+	case "jetstream":
+		return e.checkJetStream
+	case "server":
+		return e.checkServer
+	case "kv":
+		return e.checkKv
+	case "credential":
+		return e.checkCredential
+	case "request":
+		return e.checkRequest
+	default:
+		return nil
 	}
 }
 
@@ -0,0 +1,163 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRunChecks(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "checks.yaml")
+	err := os.WriteFile(cfg, []byte(`checks:
+  - name: unknown
+    kind: bogus
+  - name: credential
+    kind: credential
+    properties:
+      file: /nonexisting
+`), 0600)
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	exp, err := NewExporter("", cfg)
+	if err != nil {
+		t.Fatalf("exporter failed: %v", err)
+	}
+
+	results := exp.RunChecks()
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results got %d", len(results))
+	}
+
+	if results[0].Name != "unknown" || len(results[0].Criticals) != 1 || !strings.Contains(results[0].Criticals[0], "unknown check kind bogus") {
+		t.Fatalf("unexpected result for unknown kind: %+v", results[0])
+	}
+
+	if results[1].Name != "credential" || len(results[1].Criticals) == 0 {
+		t.Fatalf("expected critical result for credential check: %+v", results[1])
+	}
+}
+
+func TestCollect(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	akp, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatalf("account key failed: %v", err)
+	}
+	ukp, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatalf("user key failed: %v", err)
+	}
+	upk, err := ukp.PublicKey()
+	if err != nil {
+		t.Fatalf("user public key failed: %v", err)
+	}
+	useed, err := ukp.Seed()
+	if err != nil {
+		t.Fatalf("user seed failed: %v", err)
+	}
+
+	uc := jwt.NewUserClaims(upk)
+	uc.Expires = time.Now().Add(time.Hour).Unix()
+	ujwt, err := uc.Encode(akp)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	creds, err := jwt.FormatUserConfig(ujwt, useed)
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+
+	credFile := filepath.Join(dir, "user.creds")
+	err = os.WriteFile(credFile, creds, 0600)
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	cfg := filepath.Join(dir, "checks.yaml")
+	err = os.WriteFile(cfg, []byte(fmt.Sprintf(`checks:
+  - name: valid
+    kind: credential
+    properties:
+      file: %s
+  - name: missing
+    kind: credential
+    properties:
+      file: /nonexisting
+`, credFile)), 0600)
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	exp, err := NewExporter("test", cfg)
+	if err != nil {
+		t.Fatalf("exporter failed: %v", err)
+	}
+
+	reg := prometheus.NewRegistry()
+	err = reg.Register(exp)
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather failed: %v", err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%s", l.GetName(), l.GetValue()))
+			}
+			values[fmt.Sprintf("%s{%s}", family.GetName(), strings.Join(labels, ","))] = m.GetGauge().GetValue()
+		}
+	}
+
+	for name, expected := range map[string]float64{
+		"test_credential_status_code{item=valid,status=OK}":         0,
+		"test_credential_status_code{item=missing,status=CRITICAL}": 2,
+	} {
+		value, ok := values[name]
+		if !ok {
+			t.Fatalf("metric %s not found in %v", name, values)
+		}
+		if value != expected {
+			t.Fatalf("expected %s to be %v got %v", name, expected, value)
+		}
+	}
+
+	expiry, ok := values["test_credential_expiry{item=valid}"]
+	if !ok {
+		t.Fatalf("expiry metric not found in %v", values)
+	}
+	if expiry < 3500 || expiry > 3600 {
+		t.Fatalf("unexpected expiry %v", expiry)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+type diffLine struct {
+	kind byte
+	text string
+	// the number of old and new lines before this one
+	old int
+	new int
+}
+
+// UnifiedDiff compares two texts line by line and renders the differences as a unified diff with context lines around each change, returns an empty string when the texts are equal
+func UnifiedDiff(oldName string, newName string, a string, b string, context int) string {
+	if a == b {
+		return ""
+	}
+
+	lines := diffLines(strings.Split(strings.TrimSuffix(a, "\n"), "\n"), strings.Split(strings.TrimSuffix(b, "\n"), "\n"))
+
+	var changes []int
+	for i, l := range lines {
+		if l.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--- %s\n", oldName)
+	fmt.Fprintf(out, "+++ %s\n", newName)
+
+	for i := 0; i < len(changes); {
+		start := max(changes[i]-context, 0)
+		end := min(changes[i]+context+1, len(lines))
+
+		// merge changes whose context overlaps into one hunk
+		i++
+		for i < len(changes) && changes[i]-context <= end {
+			end = min(changes[i]+context+1, len(lines))
+			i++
+		}
+
+		var oldCount, newCount int
+		for _, l := range lines[start:end] {
+			if l.kind != '+' {
+				oldCount++
+			}
+			if l.kind != '-' {
+				newCount++
+			}
+		}
+
+		oldStart := lines[start].old
+		if oldCount > 0 {
+			oldStart++
+		}
+		newStart := lines[start].new
+		if newCount > 0 {
+			newStart++
+		}
+
+		fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, l := range lines[start:end] {
+			fmt.Fprintf(out, "%c%s\n", l.kind, l.text)
+		}
+	}
+
+	return out.String()
+}
+
+// diffLines finds the shortest edit between a and b using their longest common subsequence
+func diffLines(a []string, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var res []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			res = append(res, diffLine{kind: ' ', text: a[i], old: i, new: j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			res = append(res, diffLine{kind: '+', text: b[j], old: i, new: j})
+			j++
+		default:
+			res = append(res, diffLine{kind: '-', text: a[i], old: i, new: j})
+			i++
+		}
+	}
+
+	return res
+}
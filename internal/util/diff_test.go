@@ -0,0 +1,82 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	if d := UnifiedDiff("old", "new", "a\nb\n", "a\nb\n", 3); d != "" {
+		t.Fatalf("expected no diff for equal input: %q", d)
+	}
+
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n"
+
+	expect := `--- old
++++ new
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -10,1 +10,2 @@
+ 10
++11
+`
+	if d := UnifiedDiff("old", "new", a, b, 1); d != expect {
+		t.Fatalf("unexpected diff:\n%s", d)
+	}
+
+	expect = `--- old
++++ new
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -8,3 +8,4 @@
+ 8
+ 9
+ 10
++11
+`
+	if d := UnifiedDiff("old", "new", a, b, 3); d != expect {
+		t.Fatalf("unexpected diff:\n%s", d)
+	}
+
+	expect = `--- old
++++ new
+@@ -1,10 +1,11 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+ 7
+ 8
+ 9
+ 10
++11
+`
+	if d := UnifiedDiff("old", "new", a, b, 4); d != expect {
+		t.Fatalf("unexpected merged diff:\n%s", d)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// a wednesday
+	start := time.Date(2025, 1, 15, 10, 30, 20, 0, time.UTC)
+
+	for _, tc := range []struct {
+		spec   string
+		expect time.Time
+	}{
+		{"0 2 * * *", time.Date(2025, 1, 16, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"30 4 1 * *", time.Date(2025, 2, 1, 4, 30, 0, 0, time.UTC)},
+		{"0 0 * * sun", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jun *", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 20 * 1", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", start.Add(90 * time.Second)},
+		{"@every 1d", start.Add(24 * time.Hour)},
+		{"every 1h", start.Add(time.Hour)},
+		{"daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+	} {
+		s, err := ParseSchedule(tc.spec)
+		if err != nil {
+			t.Fatalf("parsing %q failed: %v", tc.spec, err)
+		}
+
+		if next := s.Next(start); !next.Equal(tc.expect) {
+			t.Fatalf("expected %q to fire at %v got %v", tc.spec, tc.expect, next)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "5-1 * * * *", "*/0 * * * *", "@every 1ms", "@every x", "weekdays"} {
+		_, err := ParseSchedule(spec)
+		if err == nil {
+			t.Fatalf("expected %q to fail", spec)
+		}
+	}
+
+	s, _ := ParseSchedule("0 0 30 2 *")
+	if !s.Next(start).IsZero() {
+		t.Fatalf("expected impossible schedule to never fire")
+	}
+}
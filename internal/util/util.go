@@ -90,7 +90,12 @@ func versionComponents(version string) (major, minor, patch int, err error) {
 
 // ServerMinVersion checks if the connected server meets certain version constraints
 func ServerMinVersion(nc *nats.Conn, major, minor, patch int) bool {
-	smajor, sminor, spatch, _ := versionComponents(nc.ConnectedServerVersion())
+	return VersionAtLeast(nc.ConnectedServerVersion(), major, minor, patch)
+}
+
+// VersionAtLeast checks if version is at least major.minor.patch
+func VersionAtLeast(version string, major, minor, patch int) bool {
+	smajor, sminor, spatch, _ := versionComponents(version)
 	if smajor < major || (smajor == major && sminor < minor) || (smajor == major && sminor == minor && spatch < patch) {
 		return false
 	}
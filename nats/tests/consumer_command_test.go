@@ -14,14 +14,20 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/jsm.go/api"
 	"github.com/nats-io/nats-server/v2/server"
 	"github.com/nats-io/nats.go"
 )
@@ -113,12 +119,56 @@ func TestConsumerEdit(t *testing.T) {
 		}
 
 		output := runNatsCli(t, fmt.Sprintf("--server='%s' consumer edit %s %s --max-pending=10 -f", srv.ClientURL(), defaultStreamName, name))
-		if !strings.Contains(string(output), "MaxAckPending: 1000,") {
-			t.Errorf("expected old MaxAckPending line not found")
+		if !strings.Contains(string(output), `-  "max_ack_pending": 1000,`) {
+			t.Errorf("expected old max_ack_pending line not found")
 		}
-		if !strings.Contains(string(output), "MaxAckPending: 10,") {
-			t.Errorf("expected new MaxAckPending line not found")
+		if !strings.Contains(string(output), `+  "max_ack_pending": 10,`) {
+			t.Errorf("expected new max_ack_pending line not found")
 		}
+		expectMatchLine(t, string(output), "Updated in place: max_ack_pending$")
+		return nil
+	})
+}
+
+func TestConsumerEditRecreate(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+
+		cfg := cons.Configuration()
+		cfg.AckPolicy = api.AckAll
+		cfg.Description = "recreated"
+		cj, err := json.Marshal(cfg)
+		checkErr(t, err, "marshal failed")
+
+		file := filepath.Join(t.TempDir(), "consumer.json")
+		checkErr(t, os.WriteFile(file, cj, 0600), "write failed")
+
+		output, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' consumer edit %s %s --config %s", srv.ClientURL(), defaultStreamName, name, file))
+		if err == nil {
+			t.Fatalf("expected edit requiring recreate to fail without --force: %s", output)
+		}
+		expectMatchLine(t, string(output), "Updated in place: description$")
+		expectMatchLine(t, string(output), "Requires recreating the consumer: ack_policy$")
+		expectMatchLine(t, string(output), "use --force to recreate it")
+
+		checkErr(t, cons.Reset(), "reset failed")
+		if cons.AckPolicy() != api.AckExplicit {
+			t.Fatalf("consumer was changed without --force")
+		}
+
+		output = runNatsCli(t, fmt.Sprintf("--server='%s' consumer edit %s %s --config %s --force", srv.ClientURL(), defaultStreamName, name, file))
+		expectMatchLine(t, string(output), "Recreated Consumer TEST_STREAM >", name)
+
+		cons, err = mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+		if cons.AckPolicy() != api.AckAll || cons.Description() != "recreated" {
+			t.Fatalf("consumer was not recreated: %+v", cons.Configuration())
+		}
+
 		return nil
 	})
 }
@@ -284,6 +334,175 @@ func TestConsumerCopy(t *testing.T) {
 	})
 }
 
+func TestConsumerCloneStartNew(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runNatsCli(t, fmt.Sprintf("--server='%s' consumer clone %s %s CLONE_1 --start-new", srv.ClientURL(), defaultStreamName, name))
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, "CLONE_1")
+		if err != nil {
+			t.Fatalf("unable to load clone: %v", err)
+		}
+
+		if cons.DeliverPolicy() != api.DeliverNew {
+			t.Errorf("expected deliver policy new, got %v", cons.DeliverPolicy())
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerCloneTarget(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		_, err = mgr.NewStream("DR", jsm.Subjects("DR.*"))
+		checkErr(t, err, "stream create failed")
+
+		t.Run("stream", func(t *testing.T) {
+			runNatsCli(t, fmt.Sprintf("--server='%s' consumer clone %s %s DR_1 --target-stream DR --transform 'TEST_STREAM.*:DR.{{wildcard(1)}}'", srv.ClientURL(), defaultStreamName, name))
+
+			cons, err := mgr.LoadConsumer("DR", "DR_1")
+			checkErr(t, err, "unable to load clone")
+			if cons.FilterSubject() != "DR.new" || cons.AckPolicy() != api.AckExplicit {
+				t.Fatalf("unexpected configuration: %+v", cons.Configuration())
+			}
+		})
+
+		t.Run("context", func(t *testing.T) {
+			ctxFile := filepath.Join(t.TempDir(), "dr.json")
+			err := os.WriteFile(ctxFile, []byte(fmt.Sprintf(`{"url":%q}`, srv.ClientURL())), 0600)
+			checkErr(t, err, "context write failed")
+
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' consumer clone %s %s DR_2 --target-stream DR --target-context %s --transform 'TEST_STREAM.>:DR.>'", srv.ClientURL(), defaultStreamName, name, ctxFile))
+			expectMatchLine(t, string(out), "Information for Consumer DR > DR_2 created")
+
+			cons, err := mgr.LoadConsumer("DR", "DR_2")
+			checkErr(t, err, "unable to load clone")
+			if cons.FilterSubject() != "DR.new" {
+				t.Fatalf("unexpected filter %q", cons.FilterSubject())
+			}
+		})
+
+		return nil
+	})
+}
+
+func TestConsumerResetRecreate(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr, jsm.MaxDeliveryAttempts(7))
+		checkErr(t, err, "consumer create failed")
+
+		for i := 0; i < 5; i++ {
+			_, err = nc.Request(defaultSubject, []byte("x"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+		runNatsCli(t, fmt.Sprintf("--server='%s' consumer next %s %s --count 5", srv.ClientURL(), defaultStreamName, name))
+
+		checkState := func(t *testing.T, policy api.DeliverPolicy, pending uint64) {
+			t.Helper()
+
+			cons, err := mgr.LoadConsumer(defaultStreamName, name)
+			checkErr(t, err, "load failed")
+			state, err := cons.LatestState()
+			checkErr(t, err, "state failed")
+
+			if cons.DeliverPolicy() != policy || cons.MaxDeliver() != 7 || state.NumPending != pending {
+				t.Fatalf("unexpected consumer policy %v max deliver %d pending %d", cons.DeliverPolicy(), cons.MaxDeliver(), state.NumPending)
+			}
+		}
+
+		checkState(t, api.DeliverAll, 0)
+
+		t.Run("deliver-all", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' consumer reset %s %s --deliver-all --wait-clients 500ms -f", srv.ClientURL(), defaultStreamName, name))
+			expectMatchLine(t, string(out), "Recreated Consumer TEST_STREAM > "+name+" with deliver policy All")
+			expectMatchLine(t, string(out), "No clients reconnected within 500ms")
+			checkState(t, api.DeliverAll, 5)
+		})
+
+		t.Run("since", func(t *testing.T) {
+			runNatsCli(t, fmt.Sprintf("--server='%s' consumer reset %s %s --since 1h -f", srv.ClientURL(), defaultStreamName, name))
+			checkState(t, api.DeliverByStartTime, 5)
+		})
+
+		t.Run("sequence", func(t *testing.T) {
+			runNatsCli(t, fmt.Sprintf("--server='%s' consumer reset %s %s --sequence 4 --recreate -f", srv.ClientURL(), defaultStreamName, name))
+			checkState(t, api.DeliverByStartSequence, 2)
+		})
+
+		t.Run("clients reconnect", func(t *testing.T) {
+			inbox := nats.NewInbox()
+			sub, err := nc.SubscribeSync(inbox)
+			checkErr(t, err, "subscribe failed")
+			defer sub.Unsubscribe()
+
+			// a pull client that keeps requesting messages, as a client would after its consumer was recreated
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				ticker := time.NewTicker(100 * time.Millisecond)
+				defer ticker.Stop()
+
+				for {
+					nc.PublishRequest(fmt.Sprintf("$JS.API.CONSUMER.MSG.NEXT.%s.%s", defaultStreamName, name), inbox, []byte(`{"batch":1,"expires":2000000000}`))
+
+					select {
+					case <-ticker.C:
+					case <-done:
+						return
+					}
+				}
+			}()
+
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' consumer reset %s %s --sequence 5 --recreate --wait-clients 10s -f", srv.ClientURL(), defaultStreamName, name))
+			expectMatchLine(t, string(out), "Clients reconnected")
+		})
+
+		t.Run("exclusive", func(t *testing.T) {
+			err := runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer reset %s %s --since 1h --deliver-all -f", srv.ClientURL(), defaultStreamName, name))
+			if err == nil {
+				t.Fatalf("expected mutually exclusive flags to fail")
+			}
+		})
+
+		return nil
+	})
+}
+
+func TestConsumerGet(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 1; i <= 3; i++ {
+			_, err = nc.Request(defaultSubject, []byte(fmt.Sprintf("message %d", i)), time.Second)
+			if err != nil {
+				t.Fatalf("failed to publish message: %v", err)
+			}
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer get %s %s 2 --raw", srv.ClientURL(), defaultStreamName, name)))
+		if strings.TrimSpace(output) != "message 2" {
+			t.Errorf("unexpected message: %q", output)
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer get %s %s 10", srv.ClientURL(), defaultStreamName, name))
+		if err == nil {
+			t.Errorf("expected an error for a missing sequence")
+		}
+
+		return nil
+	})
+}
+
 func TestConsumerNext(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		name, err := setupConsumerTest(t, 1, mgr)
@@ -392,6 +611,64 @@ func TestConsumerUnpin(t *testing.T) {
 	})
 }
 
+func TestConsumerGroup(t *testing.T) {
+	t.Run("pinned", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			name, err := setupConsumerTest(t, 1, mgr, jsm.PinnedClientPriorityGroups(time.Minute, "ONE", "TWO"))
+			checkErr(t, err, "consumer create failed")
+
+			_, err = nc.Request(defaultSubject, []byte("test"), time.Second)
+			checkErr(t, err, "publish failed")
+
+			_, err = nc.Request(fmt.Sprintf("$JS.API.CONSUMER.MSG.NEXT.%s.%s", defaultStreamName, name), []byte(`{"batch":1,"expires":1000000000,"group":"ONE"}`), 2*time.Second)
+			checkErr(t, err, "pull failed")
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer group ls %s %s", srv.ClientURL(), defaultStreamName, name)))
+			expectMatchLine(t, output, "Priority Groups on Consumer TEST_STREAM >", name)
+			expectMatchLine(t, output, "ONE", "ago")
+			expectMatchLine(t, output, "TWO", "No client")
+
+			output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer group unpin %s %s ONE -f", srv.ClientURL(), defaultStreamName, name)))
+			expectMatchLine(t, output, "Unpinned client .+ from Priority Group TEST_STREAM >", name, "> ONE")
+
+			output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer group ls %s %s", srv.ClientURL(), defaultStreamName, name)))
+			expectMatchLine(t, output, "ONE", "No client")
+
+			err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer group overflow %s %s", srv.ClientURL(), defaultStreamName, name))
+			if err == nil {
+				t.Fatalf("expected overflow status of a pinned consumer to fail")
+			}
+
+			return nil
+		})
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			name, err := setupConsumerTest(t, 1, mgr, jsm.OverflowPriorityGroups("ONE"))
+			checkErr(t, err, "consumer create failed")
+
+			for i := 0; i < 5; i++ {
+				_, err = nc.Request(defaultSubject, []byte("test"), time.Second)
+				checkErr(t, err, "publish failed")
+			}
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer group overflow %s %s --min-pending 10", srv.ClientURL(), defaultStreamName, name)))
+			expectMatchLine(t, output, "Unprocessed Messages: 5")
+			expectMatchLine(t, output, "Minimum Pending: 10")
+			expectMatchLine(t, output, "Overflowing: false")
+
+			output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer group overflow %s %s --min-pending 5", srv.ClientURL(), defaultStreamName, name)))
+			expectMatchLine(t, output, "Overflowing: true")
+
+			output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer group ls %s %s", srv.ClientURL(), defaultStreamName, name)))
+			expectMatchLine(t, output, "ONE", "overflow")
+
+			return nil
+		})
+	})
+}
+
 func TestConsumerResume(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		name, err := setupConsumerTest(t, 1, mgr)
@@ -426,6 +703,101 @@ func TestConsumerReport(t *testing.T) {
 	})
 }
 
+func TestConsumerWatch(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		_, err = mgr.NewConsumer(defaultStreamName, jsm.DurableName("IDLE"), jsm.FilterStreamBySubject("TEST_STREAM.other"))
+		checkErr(t, err, "consumer create failed")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			ticker := time.NewTicker(200 * time.Millisecond)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					nc.Publish(defaultSubject, []byte("x"))
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer watch %s --interval 1s --samples 3 --count 3", srv.ClientURL(), defaultStreamName)))
+		cancel()
+
+		if strings.Count(output, "Consumers on Stream TEST_STREAM") != 3 {
+			t.Fatalf("expected 3 refreshes: %s", output)
+		}
+		expectMatchLine(t, output, name, "growing")
+		expectMatchLine(t, output, "Lag grew over the last 3 samples for: "+name+"$")
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer watch %s IDLE --interval 1s --count 1", srv.ClientURL(), defaultStreamName)))
+		if strings.Contains(output, name) || !strings.Contains(output, "IDLE") {
+			t.Fatalf("unexpected output: %s", output)
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerReportSortFilter(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		createDefaultTestStream(t, mgr, 1)
+
+		for _, name := range []string{"A", "B", "C"} {
+			_, err := mgr.NewConsumer(defaultStreamName, jsm.DurableName(name), jsm.FilterStreamBySubject("TEST_STREAM."+strings.ToLower(name)))
+			checkErr(t, err, "consumer create failed")
+		}
+
+		for _, subj := range []string{"a", "b", "a", "a"} {
+			_, err := nc.Request("TEST_STREAM."+subj, []byte("x"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		t.Run("json", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' consumer report %s --sort unprocessed --top 2 --json", srv.ClientURL(), defaultStreamName))
+
+			var states []api.ConsumerInfo
+			checkErr(t, json.Unmarshal(out, &states), "invalid json: %s", out)
+			if len(states) != 2 || states[0].Name != "A" || states[0].NumPending != 3 || states[1].Name != "B" {
+				t.Fatalf("unexpected report: %s", out)
+			}
+		})
+
+		t.Run("reverse", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' consumer report %s --sort unprocessed --reverse --top 1 --json", srv.ClientURL(), defaultStreamName))
+
+			var states []api.ConsumerInfo
+			checkErr(t, json.Unmarshal(out, &states), "invalid json: %s", out)
+			if len(states) != 1 || states[0].Name != "C" {
+				t.Fatalf("unexpected report: %s", out)
+			}
+		})
+
+		t.Run("csv", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' consumer report %s --csv --filter-expr 'info.numpending > 0'", srv.ClientURL(), defaultStreamName))
+
+			lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+			if len(lines) != 3 || !strings.HasPrefix(lines[0], "consumer,mode,ack_policy") || !strings.HasPrefix(lines[1], "A,Pull,Explicit,30s,0,0,3,") || !strings.HasPrefix(lines[2], "B,") {
+				t.Fatalf("unexpected csv: %s", out)
+			}
+		})
+
+		t.Run("table", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' consumer report %s --filter-expr 'info.numpending > 0 && duration(config.ack_wait) >= 30s' --top 1", srv.ClientURL(), defaultStreamName))
+			expectMatchLine(t, string(out), "Consumer report for TEST_STREAM with 1 consumers")
+		})
+
+		return nil
+	})
+}
+
 func TestConsumerClusterDown(t *testing.T) {
 	withJSCluster(t, func(t *testing.T, servers []*server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		name, err := setupConsumerTest(t, 3, mgr)
@@ -471,3 +843,694 @@ func TestConsumerClusterBalance(t *testing.T) {
 		return nil
 	})
 }
+
+func TestConsumerSample(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		createDefaultTestStream(t, mgr, 1)
+
+		cons, err := mgr.NewConsumer(defaultStreamName, jsm.DurableName("SAMPLED"), jsm.AcknowledgeExplicit())
+		checkErr(t, err, "consumer create failed")
+
+		go func() {
+			// wait for the sample command to enable sampling and subscribe
+			for !cons.IsSampled() {
+				time.Sleep(100 * time.Millisecond)
+				cons.Reset()
+			}
+			time.Sleep(500 * time.Millisecond)
+
+			for i := 0; i < 10; i++ {
+				subj := "TEST_STREAM.a"
+				if i%2 == 0 {
+					subj = "TEST_STREAM.b"
+				}
+				nc.Publish(subj, []byte("x"))
+			}
+
+			for i := 0; i < 10; i++ {
+				msg, err := cons.NextMsg()
+				if err != nil {
+					return
+				}
+				msg.Respond(nil)
+			}
+		}()
+
+		output := runNatsCli(t, fmt.Sprintf("--server='%s' consumer sample %s SAMPLED --duration 5s --json", srv.ClientURL(), defaultStreamName))
+
+		var report map[string]any
+		err = json.Unmarshal(output, &report)
+		checkErr(t, err, "invalid json: %s", output)
+
+		if report["samples"].(float64) != 10 {
+			t.Fatalf("expected 10 samples: %s", output)
+		}
+		subjects := report["subjects"].([]any)
+		if len(subjects) != 2 || subjects[0].(map[string]any)["samples"].(float64) != 5 {
+			t.Fatalf("expected 2 subjects with 5 samples each: %s", output)
+		}
+
+		err = cons.Reset()
+		checkErr(t, err, "consumer reset failed")
+		if cons.IsSampled() {
+			t.Fatalf("sampling was not disabled")
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer sample %s SAMPLED --duration 1s --no-enable", srv.ClientURL(), defaultStreamName))
+		if err == nil {
+			t.Fatalf("expected an error for unsampled consumer")
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerSampleWorkQueue(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		_, err := mgr.NewStream("WQ", jsm.Subjects("wq.>"), jsm.WorkQueueRetention())
+		checkErr(t, err, "stream create failed")
+
+		cons, err := mgr.NewConsumer("WQ", jsm.DurableName("SAMPLED"), jsm.AcknowledgeExplicit())
+		checkErr(t, err, "consumer create failed")
+
+		go func() {
+			for !cons.IsSampled() {
+				time.Sleep(100 * time.Millisecond)
+				cons.Reset()
+			}
+			time.Sleep(500 * time.Millisecond)
+
+			for i := 0; i < 5; i++ {
+				nc.Publish("wq.a", []byte("x"))
+			}
+
+			for i := 0; i < 5; i++ {
+				msg, err := cons.NextMsg()
+				if err != nil {
+					return
+				}
+				msg.Respond(nil)
+			}
+		}()
+
+		output := runNatsCli(t, fmt.Sprintf("--server='%s' consumer sample WQ SAMPLED --duration 3s --json", srv.ClientURL()))
+
+		var report map[string]any
+		err = json.Unmarshal(output, &report)
+		checkErr(t, err, "invalid json: %s", output)
+
+		// acknowledged messages are removed from work queue streams
+		subjects := report["subjects"].([]any)
+		if len(subjects) != 1 || subjects[0].(map[string]any)["subject"] != "(removed from stream)" || subjects[0].(map[string]any)["samples"].(float64) != 5 {
+			t.Fatalf("expected 5 samples of removed messages: %s", output)
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerGraphRecord(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer graph %s %s", srv.ClientURL(), defaultStreamName, name))
+		if err == nil {
+			t.Fatalf("expected graph without a terminal to fail")
+		}
+
+		for i := 0; i < 5; i++ {
+			_, err = nc.Request(defaultSubject, []byte("hello"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		record := filepath.Join(t.TempDir(), "graph.csv")
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer graph %s %s --record %s --duration 2500ms", srv.ClientURL(), defaultStreamName, name, record)))
+		if !expectMatchLine(t, output, "Recorded 2 samples to") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		data, err := os.ReadFile(record)
+		checkErr(t, err, "read failed")
+
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		checkErr(t, err, "invalid csv")
+
+		if len(rows) != 3 {
+			t.Fatalf("expected a header and 2 rows: %s", data)
+		}
+		if strings.Join(rows[0], ",") != "time,delivered_rate,acked_rate,ack_pending,num_pending,redelivered" {
+			t.Errorf("unexpected header: %v", rows[0])
+		}
+		if rows[1][3] != "0" || rows[1][4] != "5" {
+			t.Errorf("unexpected row: %v", rows[1])
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerNextBatchOutputDir(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		for i := 0; i < 7; i++ {
+			_, err = nc.Request(defaultSubject, []byte(fmt.Sprintf("msg %d", i+1)), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		dir := t.TempDir()
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer next %s %s --count 10 --batch 3 --output-dir %s", srv.ClientURL(), defaultStreamName, name, dir)))
+		expectMatchLine(t, output, "Received 7 messages from Consumer TEST_STREAM >", name)
+
+		data, err := os.ReadFile(filepath.Join(dir, "7.data"))
+		checkErr(t, err, "read failed")
+		if string(data) != "msg 7" {
+			t.Fatalf("unexpected payload: %s", data)
+		}
+
+		mj, err := os.ReadFile(filepath.Join(dir, "7.json"))
+		checkErr(t, err, "read failed")
+		var meta map[string]any
+		err = json.Unmarshal(mj, &meta)
+		checkErr(t, err, "invalid json")
+		if meta["subject"] != defaultSubject || meta["stream_seq"].(float64) != 7 || meta["acknowledgment"] != "ack" {
+			t.Fatalf("unexpected metadata: %s", mj)
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err = nc.Request(defaultSubject, []byte("unacked"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		runNatsCli(t, fmt.Sprintf("--server='%s' consumer next %s %s --count 2 --batch 2 --no-ack --output-dir %s", srv.ClientURL(), defaultStreamName, name, dir))
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+		state, err := cons.LatestState()
+		checkErr(t, err, "state failed")
+		if state.NumAckPending != 2 || state.AckFloor.Stream != 7 {
+			t.Fatalf("unexpected consumer state: %+v", state)
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerCheckStalled(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		createDefaultTestStream(t, mgr, 1)
+
+		full, err := mgr.NewConsumer(defaultStreamName, jsm.DurableName("FULL"), jsm.FilterStreamBySubject("TEST_STREAM.full"), jsm.MaxAckPending(2))
+		checkErr(t, err, "consumer create failed")
+		_, err = mgr.NewConsumer(defaultStreamName, jsm.DurableName("BACKLOG"), jsm.FilterStreamBySubject("TEST_STREAM.backlog"))
+		checkErr(t, err, "consumer create failed")
+		_, err = mgr.NewConsumer(defaultStreamName, jsm.DurableName("IDLE"), jsm.FilterStreamBySubject("TEST_STREAM.idle"))
+		checkErr(t, err, "consumer create failed")
+
+		for i := 0; i < 3; i++ {
+			_, err = nc.Request("TEST_STREAM.full", []byte("x"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+		for i := 0; i < 2; i++ {
+			_, err = full.NextMsg()
+			checkErr(t, err, "next failed")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			ticker := time.NewTicker(200 * time.Millisecond)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					nc.Publish("TEST_STREAM.backlog", []byte("x"))
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		output, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' consumer check %s --interval 1s", srv.ClientURL(), defaultStreamName))
+		cancel()
+		if err == nil {
+			t.Fatalf("expected stalled consumers to fail the check: %s", output)
+		}
+		expectMatchLine(t, string(output), "FULL", "critical", "ack pending 2 reached max ack pending 2")
+		expectMatchLine(t, string(output), "BACKLOG", "warning", "no pull requests waiting while unprocessed messages grew")
+		if strings.Contains(string(output), "IDLE") {
+			t.Fatalf("idle consumer should not be reported: %s", output)
+		}
+
+		output = runNatsCli(t, fmt.Sprintf("--server='%s' consumer check %s IDLE --interval 1s --format nagios", srv.ClientURL(), defaultStreamName))
+		expectMatchLine(t, string(output), "OK", "1 consumers")
+
+		output, err = runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' consumer check %s FULL --interval 1s --format json", srv.ClientURL(), defaultStreamName))
+		if err == nil {
+			t.Fatalf("expected critical check to fail: %s", output)
+		}
+		expectMatchLine(t, string(output), `"status": "CRITICAL"`)
+		expectMatchLine(t, string(output), "FULL: ack pending 2 reached max ack pending 2")
+
+		output = runNatsCli(t, fmt.Sprintf("--server='%s' consumer check %s IDLE --interval 1s --format zabbix", srv.ClientURL(), defaultStreamName))
+		expectMatchLine(t, string(output), "stalled", "0")
+
+		return nil
+	})
+}
+
+func TestConsumerCheckRedeliveryStorm(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		createDefaultTestStream(t, mgr, 1)
+
+		cons, err := mgr.NewConsumer(defaultStreamName, jsm.DurableName("STORM"), jsm.FilterStreamBySubject("TEST_STREAM.storm"), jsm.AckWait(100*time.Millisecond))
+		checkErr(t, err, "consumer create failed")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// new messages arrive while a client keeps receiving them without ever acknowledging any
+		go func() {
+			ticker := time.NewTicker(200 * time.Millisecond)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					nc.Publish("TEST_STREAM.storm", []byte("x"))
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		go func() {
+			for ctx.Err() == nil {
+				cons.NextMsg()
+			}
+		}()
+
+		output, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' consumer check %s STORM --interval 2s", srv.ClientURL(), defaultStreamName))
+		cancel()
+		if err == nil {
+			t.Fatalf("expected a redelivery storm to fail the check: %s", output)
+		}
+		expectMatchLine(t, string(output), "STORM", "critical", "redelivering [\\d,]+ messages while the ack floor stays at 0")
+
+		return nil
+	})
+}
+
+func TestConsumerAddProfile(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		createDefaultTestStream(t, mgr, 1)
+
+		cfgDir := t.TempDir()
+		profileDir := filepath.Join(cfgDir, "nats", "cli", "profiles", "consumer")
+		checkErr(t, os.MkdirAll(profileDir, 0700), "mkdir failed")
+
+		profile := `{"max_deliver":5,"backoff":[1000000000,5000000000,10000000000],"max_ack_pending":100,"metadata":{"team":"ops"}}`
+		checkErr(t, os.WriteFile(filepath.Join(profileDir, "batch-worker.json"), []byte(profile), 0600), "write failed")
+		checkErr(t, os.WriteFile(filepath.Join(profileDir, "slow.json"), []byte(`{"ack_wait":60000000000}`), 0600), "write failed")
+
+		env := map[string]string{"XDG_CONFIG_HOME": cfgDir}
+
+		_, err := runNatsCliCore(t, "", env, fmt.Sprintf("--server='%s' consumer add %s WORKER --profile batch-worker --pull --max-pending 50 --defaults", srv.ClientURL(), defaultStreamName))
+		checkErr(t, err, "add failed")
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, "WORKER")
+		checkErr(t, err, "load failed")
+
+		cfg := cons.Configuration()
+		if cfg.MaxDeliver != 5 {
+			t.Errorf("expected max deliver 5 from profile, got %d", cfg.MaxDeliver)
+		}
+		if len(cfg.BackOff) != 3 || cfg.BackOff[1] != 5*time.Second {
+			t.Errorf("expected backoff from profile, got %v", cfg.BackOff)
+		}
+		if cfg.MaxAckPending != 50 {
+			t.Errorf("expected flag to override profile max ack pending, got %d", cfg.MaxAckPending)
+		}
+		if cfg.Metadata["team"] != "ops" {
+			t.Errorf("expected metadata from profile, got %v", cfg.Metadata)
+		}
+
+		_, err = runNatsCliCore(t, "", env, fmt.Sprintf("--server='%s' consumer add %s SLOW --profile slow --pull --defaults", srv.ClientURL(), defaultStreamName))
+		checkErr(t, err, "add failed")
+
+		cons, err = mgr.LoadConsumer(defaultStreamName, "SLOW")
+		checkErr(t, err, "load failed")
+		if cons.AckWait() != time.Minute {
+			t.Errorf("expected 1m ack wait from profile, got %v", cons.AckWait())
+		}
+
+		_, err = runNatsCliCore(t, "", env, fmt.Sprintf("--server='%s' consumer add %s OTHER --profile missing --pull --defaults", srv.ClientURL(), defaultStreamName))
+		if err == nil {
+			t.Errorf("expected unknown profile to fail")
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerSubParallel(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		for i := 0; i < 20; i++ {
+			_, err = nc.Request(defaultSubject, []byte("x"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer sub %s %s --parallel 4 --count 20", srv.ClientURL(), defaultStreamName, name)))
+		expectMatchLine(t, output, "4 workers on Consumer TEST_STREAM >", name)
+		expectMatchLine(t, output, "Total", "20", "20", "0", "0")
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+		state, err := cons.LatestState()
+		checkErr(t, err, "state failed")
+		if state.AckFloor.Stream != 20 || state.NumAckPending != 0 {
+			t.Fatalf("expected all messages to be acknowledged: %+v", state)
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerSubParallelDeleted(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+
+		// deletes the consumer once the workers are waiting for messages
+		go func() {
+			for {
+				state, err := cons.State()
+				if err != nil {
+					return
+				}
+				if state.NumWaiting > 0 {
+					cons.Delete()
+					return
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+		}()
+
+		type result struct {
+			out []byte
+			err error
+		}
+		done := make(chan result, 1)
+		go func() {
+			out, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' consumer sub %s %s --parallel 2", srv.ClientURL(), defaultStreamName, name))
+			done <- result{out, err}
+		}()
+
+		select {
+		case res := <-done:
+			if res.err == nil {
+				t.Fatalf("expected subscribing to a deleted consumer to fail: %s", res.out)
+			}
+			expectMatchLine(t, string(res.out), "consumer deleted")
+		case <-time.After(time.Minute):
+			t.Fatalf("workers did not stop after the consumer was deleted")
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerSubOrdered(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		for i := 0; i < 5; i++ {
+			_, err = nc.Request(defaultSubject, []byte(fmt.Sprintf("msg %d", i)), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer sub %s %s --ordered --count 5", srv.ClientURL(), defaultStreamName, name)))
+		expectMatchLine(t, output, "using an ordered consumer")
+		expectMatchLine(t, output, "subj: TEST_STREAM.new", "str seq: 1", "pending: 4")
+		expectMatchLine(t, output, "subj: TEST_STREAM.new", "str seq: 5", "pending: 0")
+		expectMatchLine(t, output, "msg 4")
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+		state, err := cons.LatestState()
+		checkErr(t, err, "state failed")
+		if state.Delivered.Stream != 0 || state.NumPending != 5 {
+			t.Fatalf("expected the consumer state to be unchanged: %+v", state)
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerLatency(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr, jsm.MaxDeliveryAttempts(1))
+		checkErr(t, err, "consumer create failed")
+
+		_, err = nc.Request(defaultSubject, []byte("real message"), time.Second)
+		checkErr(t, err, "publish failed")
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer latency %s %s --duration 1s --rate 10", srv.ClientURL(), defaultStreamName, name)))
+		expectMatchLine(t, output, "Latency for Consumer TEST_STREAM >", name, "using probes on TEST_STREAM.new")
+		expectMatchLine(t, output, "Probes Received: 1[01]")
+		expectMatchLine(t, output, "p95:")
+		expectMatchLine(t, output, "p99:")
+		if strings.Contains(output, "Other Messages") {
+			t.Fatalf("messages published before the measurement were received: %s", output)
+		}
+
+		// the measured consumer keeps all its messages, including those limited to a single delivery
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+		state, err := cons.State()
+		checkErr(t, err, "state failed")
+		if state.Delivered.Stream != 0 || state.NumAckPending != 0 || state.NumPending < 11 {
+			t.Fatalf("measured consumer was used: %+v", state)
+		}
+
+		msg, err := cons.NextMsg()
+		checkErr(t, err, "next failed")
+		if string(msg.Data) != "real message" {
+			t.Fatalf("unexpected message %q", msg.Data)
+		}
+
+		names, err := mgr.ConsumerNames(defaultStreamName)
+		checkErr(t, err, "names failed")
+		if len(names) != 1 {
+			t.Fatalf("probe consumer was not removed: %v", names)
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer latency %s %s --duration 1s --subject OTHER.subject", srv.ClientURL(), defaultStreamName, name))
+		if err == nil {
+			t.Fatalf("expected a probe subject outside the filter to fail")
+		}
+
+		// probes are received through a pull consumer of our own so push consumers can be measured too
+		_, err = mgr.NewConsumer(defaultStreamName, jsm.DurableName("PUSH"), jsm.DeliverySubject("push.deliver"), jsm.FilterStreamBySubject(defaultSubject))
+		checkErr(t, err, "push consumer create failed")
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer latency %s PUSH --duration 1s --rate 10", srv.ClientURL(), defaultStreamName)))
+		expectMatchLine(t, output, "Probes Received: 1[01]")
+
+		return nil
+	})
+}
+
+func TestConsumerDrain(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		for i := 0; i < 5; i++ {
+			_, err = nc.Request(defaultSubject, []byte("x"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer drain %s %s --max-wait 1s --no-progress", srv.ClientURL(), defaultStreamName, name))
+		if err == nil {
+			t.Fatalf("expected drain to time out")
+		}
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			for i := 0; i < 5; i++ {
+				msg, err := cons.NextMsg()
+				if err != nil {
+					return
+				}
+				msg.Respond(nil)
+			}
+		}()
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer drain %s %s --max-wait 10s --no-progress --delete --force", srv.ClientURL(), defaultStreamName, name)))
+		expectMatchLine(t, output, "Consumer TEST_STREAM >", name, "drained up to stream sequence 5")
+		expectMatchLine(t, output, "Deleted Consumer TEST_STREAM >", name)
+
+		known, err := mgr.IsKnownConsumer(defaultStreamName, name)
+		checkErr(t, err, "known check failed")
+		if known {
+			t.Fatalf("expected the consumer to be deleted")
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerStateSnapshot(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		for i := 0; i < 5; i++ {
+			_, err = nc.Request(defaultSubject, []byte("x"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		output := runNatsCli(t, fmt.Sprintf("--server='%s' consumer state %s %s --output json", srv.ClientURL(), defaultStreamName, name))
+
+		var snap map[string]any
+		checkErr(t, json.Unmarshal(output, &snap), "invalid snapshot")
+		if snap["num_pending"] != float64(5) || snap["stream_last_seq"] != float64(5) {
+			t.Fatalf("unexpected snapshot: %s", output)
+		}
+
+		file := filepath.Join(t.TempDir(), "state.json")
+		checkErr(t, os.WriteFile(file, output, 0600), "write failed")
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+		for i := 0; i < 2; i++ {
+			msg, err := cons.NextMsg()
+			checkErr(t, err, "next failed")
+			checkErr(t, msg.Respond(nil), "ack failed")
+		}
+
+		out := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer state %s %s --compare %s", srv.ClientURL(), defaultStreamName, name, file)))
+		expectMatchLine(t, out, "State changes for Consumer TEST_STREAM >", name)
+		expectMatchLine(t, out, "Ack Floor Stream Sequence", "0", "2", `\+2`)
+		expectMatchLine(t, out, "Unprocessed Messages", "5", "3", "-2")
+
+		snap["ack_floor"] = map[string]any{"consumer_seq": 10, "stream_seq": 10}
+		output, err = json.Marshal(snap)
+		checkErr(t, err, "marshal failed")
+		checkErr(t, os.WriteFile(file, output, 0600), "write failed")
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' consumer state %s %s --compare %s", srv.ClientURL(), defaultStreamName, name, file))
+		if err == nil {
+			t.Fatalf("expected a consumer moving backwards to fail")
+		}
+
+		return nil
+	})
+}
+
+func TestConsumerBackupRestoreDefinitions(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		createDefaultTestStream(t, mgr, 1)
+
+		_, err := mgr.NewConsumer(defaultStreamName, jsm.DurableName("ONE"), jsm.FilterStreamBySubject("TEST_STREAM.one"), jsm.MaxAckPending(10))
+		checkErr(t, err, "consumer create failed")
+		_, err = mgr.NewConsumer(defaultStreamName, jsm.DurableName("TWO"), jsm.DeliverAllAvailable(), jsm.ConsumerDescription("second"))
+		checkErr(t, err, "consumer create failed")
+
+		dir := filepath.Join(t.TempDir(), "consumers")
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer backup %s %s", srv.ClientURL(), defaultStreamName, dir)))
+		expectMatchLine(t, output, "Saved 2 consumer configurations from Stream TEST_STREAM")
+
+		for _, name := range []string{"ONE", "TWO"} {
+			_, err = os.Stat(filepath.Join(dir, name+".json"))
+			checkErr(t, err, "backup file missing")
+
+			cons, err := mgr.LoadConsumer(defaultStreamName, name)
+			checkErr(t, err, "load failed")
+			checkErr(t, cons.Delete(), "delete failed")
+		}
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer restore %s %s", srv.ClientURL(), defaultStreamName, dir)))
+		expectMatchLine(t, output, "ONE", "created")
+		expectMatchLine(t, output, "Created 2 consumers on Stream TEST_STREAM")
+
+		one, err := mgr.LoadConsumer(defaultStreamName, "ONE")
+		checkErr(t, err, "load failed")
+		if one.FilterSubject() != "TEST_STREAM.one" || one.MaxAckPending() != 10 {
+			t.Fatalf("unexpected restored configuration: %+v", one.Configuration())
+		}
+		two, err := mgr.LoadConsumer(defaultStreamName, "TWO")
+		checkErr(t, err, "load failed")
+		if two.Description() != "second" {
+			t.Fatalf("unexpected restored configuration: %+v", two.Configuration())
+		}
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer restore %s %s", srv.ClientURL(), defaultStreamName, dir)))
+		expectMatchLine(t, output, "TWO", "exists, unchanged")
+		expectMatchLine(t, output, "Created 0 consumers on Stream TEST_STREAM")
+
+		return nil
+	})
+}
+
+func TestConsumerEvents(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name, err := setupConsumerTest(t, 1, mgr)
+		checkErr(t, err, "consumer create failed")
+
+		cons, err := mgr.LoadConsumer(defaultStreamName, name)
+		checkErr(t, err, "load failed")
+
+		_, err = nc.Request(defaultSubject, []byte("poison"), time.Second)
+		checkErr(t, err, "publish failed")
+		_, err = nc.Request(defaultSubject, []byte("bad"), time.Second)
+		checkErr(t, err, "publish failed")
+
+		go func() {
+			// wait for the events command to subscribe
+			for !srv.GlobalAccount().SubscriptionInterest(fmt.Sprintf("$JS.EVENT.ADVISORY.CONSUMER.MSG_NAKED.%s.%s", defaultStreamName, name)) {
+				time.Sleep(50 * time.Millisecond)
+			}
+
+			for _, ack := range []string{"-NAK", "-NAK", "+TERM"} {
+				msg, err := cons.NextMsg()
+				if err != nil {
+					return
+				}
+				// the naked message might be redelivered before the next one
+				if ack == "+TERM" && string(msg.Data) != "bad" {
+					msg.Respond([]byte("+ACK"))
+					msg, err = cons.NextMsg()
+					if err != nil {
+						return
+					}
+				}
+				msg.Respond([]byte(ack))
+				nc.Flush()
+			}
+		}()
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' consumer events %s %s --count 3 --peek", srv.ClientURL(), defaultStreamName, name)))
+		expectMatchLine(t, output, "NAK", "stream seq: 1 deliveries: 1 subject: TEST_STREAM.new$")
+		expectMatchLine(t, output, "NAK", "stream seq: 1 deliveries: 2 subject: TEST_STREAM.new", `\(advisory 2 for this message\)`)
+		expectMatchLine(t, output, "TERMINATED", "stream seq: 2 deliveries: 1")
+		expectMatchLine(t, output, "data: poison")
+		expectMatchLine(t, output, "1", "TEST_STREAM.new", "2", "0", "0", "2")
+
+		return nil
+	})
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -162,6 +164,44 @@ func TestCLIKVPut(t *testing.T) {
 	})
 }
 
+func TestCLIKVPutTTL(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		store := createTestJSBucket(t, nc, &jetstream.KeyValueConfig{Bucket: "T", TTL: time.Hour, LimitMarkerTTL: time.Minute})
+
+		out := runNatsCli(t, fmt.Sprintf("--server='%s' kv put T X VAL --ttl 10m", srv.ClientURL()))
+		if !expectMatchLine(t, string(out), "Expires at") {
+			t.Fatalf("expiry not shown: %s", string(out))
+		}
+
+		val, err := store.Get(context.Background(), "X")
+		if err != nil {
+			t.Fatalf("get failed: %s", err)
+		}
+		if !bytes.Equal(val.Value(), []byte("VAL")) {
+			t.Fatalf("invalid value saved: %s", val.Value())
+		}
+
+		msg, err := mgr.ReadLastMessageForSubject("KV_T", "$KV.T.X")
+		if err != nil {
+			t.Fatalf("read failed: %s", err)
+		}
+		hdrs, err := iu.DecodeHeadersMsg(msg.Header)
+		if err != nil {
+			t.Fatalf("header decode failed: %s", err)
+		}
+		if hdrs.Get("Nats-TTL") != "10m0s" {
+			t.Fatalf("invalid TTL header: %q", hdrs.Get("Nats-TTL"))
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' kv put T X VAL --ttl 2h", srv.ClientURL()))
+		if err == nil {
+			t.Fatalf("expected TTL exceeding the bucket TTL to fail")
+		}
+
+		return nil
+	})
+}
+
 func TestCLIKVUpdate(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		store := createTestJSBucket(t, nc, nil)
@@ -353,3 +393,179 @@ func TestCLIEdit(t *testing.T) {
 		return nil
 	})
 }
+
+func TestCLIKVExportImportMarkers(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		store := createTestJSBucket(t, nc, &jetstream.KeyValueConfig{Bucket: "TTL", History: 5, LimitMarkerTTL: time.Minute})
+		mustPut(t, store, "KEEP", "1")
+
+		// markers as placed by the server when values reach their TTL or are removed
+		for key, reason := range map[string]string{"EXPIRED": "MaxAge", "REMOVED": "Remove"} {
+			mustPut(t, store, key, "1")
+
+			msg := nats.NewMsg("$KV.TTL." + key)
+			msg.Header.Set("Nats-Marker-Reason", reason)
+			_, err := nc.RequestMsg(msg, time.Second)
+			if err != nil {
+				t.Fatalf("marker failed: %s", err)
+			}
+		}
+
+		file := filepath.Join(t.TempDir(), "TTL.jsonl")
+		runNatsCli(t, fmt.Sprintf("--server='%s' kv export TTL %s", srv.ClientURL(), file))
+
+		export, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read failed: %s", err)
+		}
+		if !expectMatchRegex(t, `"key":"EXPIRED","revision":\d+,"operation":"PURGE"`, string(export)) {
+			t.Fatalf("expiry marker was not exported as a purge: %s", export)
+		}
+		if !expectMatchRegex(t, `"key":"REMOVED","revision":\d+,"operation":"DELETE"`, string(export)) {
+			t.Fatalf("remove marker was not exported as a delete: %s", export)
+		}
+
+		target := createTestJSBucket(t, nc, &jetstream.KeyValueConfig{Bucket: "TTL2", History: 5, LimitMarkerTTL: time.Minute})
+		runNatsCli(t, fmt.Sprintf("--server='%s' kv import TTL2 %s", srv.ClientURL(), file))
+
+		for _, key := range []string{"EXPIRED", "REMOVED"} {
+			_, err = target.Get(context.Background(), key)
+			if !errors.Is(err, jetstream.ErrKeyNotFound) {
+				t.Fatalf("expected %s to be deleted: %v", key, err)
+			}
+		}
+
+		entry, err := target.Get(context.Background(), "KEEP")
+		if err != nil || string(entry.Value()) != "1" {
+			t.Fatalf("unexpected entry for KEEP: %v", err)
+		}
+
+		return nil
+	})
+}
+
+func TestCLIKVExportImportExpectations(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		store := createTestJSBucket(t, nc, &jetstream.KeyValueConfig{Bucket: "CAS", History: 5})
+		mustPut(t, store, "OTHER", "1")
+
+		// Create and Update publish with revision expectations that do not hold in another bucket
+		rev, err := store.Create(context.Background(), "X", []byte("1"))
+		if err != nil {
+			t.Fatalf("create failed: %s", err)
+		}
+		_, err = store.Update(context.Background(), "X", []byte("2"), rev)
+		if err != nil {
+			t.Fatalf("update failed: %s", err)
+		}
+
+		file := filepath.Join(t.TempDir(), "CAS.jsonl")
+		runNatsCli(t, fmt.Sprintf("--server='%s' kv export CAS %s", srv.ClientURL(), file))
+
+		target := createTestJSBucket(t, nc, &jetstream.KeyValueConfig{Bucket: "CAS2", History: 5})
+		out := runNatsCli(t, fmt.Sprintf("--server='%s' kv import CAS2 %s", srv.ClientURL(), file))
+		if !expectMatchRegex(t, "Imported 3 entries for 2 keys into bucket CAS2", string(out)) {
+			t.Fatalf("import failed: %s", out)
+		}
+
+		history, err := target.History(context.Background(), "X")
+		if err != nil {
+			t.Fatalf("history failed: %s", err)
+		}
+		if len(history) != 2 || string(history[0].Value()) != "1" || string(history[1].Value()) != "2" {
+			t.Fatalf("unexpected history for X: %v", history)
+		}
+
+		str, err := mgr.LoadStream("KV_CAS2")
+		if err != nil {
+			t.Fatalf("load failed: %s", err)
+		}
+		raw, err := str.ReadLastMessageForSubject("$KV.CAS2.X")
+		if err != nil {
+			t.Fatalf("read failed: %s", err)
+		}
+		if strings.Contains(string(raw.Header), "Nats-Expected-") {
+			t.Fatalf("expectation headers were imported: %s", raw.Header)
+		}
+
+		return nil
+	})
+}
+
+func TestCLIKVExportImport(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		store := createTestJSBucket(t, nc, &jetstream.KeyValueConfig{Bucket: "T", History: 5})
+		mustPut(t, store, "X", "1")
+		mustPut(t, store, "X", "2")
+		mustPut(t, store, "Y", "y")
+		if err := store.Delete(context.Background(), "Y"); err != nil {
+			t.Fatalf("delete failed: %s", err)
+		}
+
+		msg := nats.NewMsg("$KV.T.Z")
+		msg.Data = []byte{0, 1, 2}
+		msg.Header.Set("X-Meta", "meta")
+		if _, err := nc.RequestMsg(msg, time.Second); err != nil {
+			t.Fatalf("put failed: %s", err)
+		}
+
+		file := filepath.Join(t.TempDir(), "T.jsonl")
+		out := runNatsCli(t, fmt.Sprintf("--server='%s' kv export T %s", srv.ClientURL(), file))
+		if !expectMatchRegex(t, "Exported 5 entries for 3 keys from bucket T", string(out)) {
+			t.Fatalf("export failed: %s", out)
+		}
+
+		target := createTestJSBucket(t, nc, &jetstream.KeyValueConfig{Bucket: "T2", History: 5, Replicas: 1})
+		out = runNatsCli(t, fmt.Sprintf("--server='%s' kv import T2 %s", srv.ClientURL(), file))
+		if !expectMatchRegex(t, "Imported 5 entries for 3 keys into bucket T2", string(out)) {
+			t.Fatalf("import failed: %s", out)
+		}
+
+		history, err := target.History(context.Background(), "X")
+		if err != nil {
+			t.Fatalf("history failed: %s", err)
+		}
+		if len(history) != 2 || string(history[0].Value()) != "1" || string(history[1].Value()) != "2" {
+			t.Fatalf("unexpected history for X: %v", history)
+		}
+
+		_, err = target.Get(context.Background(), "Y")
+		if !errors.Is(err, jetstream.ErrKeyNotFound) {
+			t.Fatalf("expected Y to be deleted: %v", err)
+		}
+
+		str, err := mgr.LoadStream("KV_T2")
+		if err != nil {
+			t.Fatalf("load failed: %s", err)
+		}
+		raw, err := str.ReadLastMessageForSubject("$KV.T2.Z")
+		if err != nil {
+			t.Fatalf("read failed: %s", err)
+		}
+		if !bytes.Equal(raw.Data, []byte{0, 1, 2}) || !strings.Contains(string(raw.Header), "X-Meta: meta") {
+			t.Fatalf("unexpected entry for Z: %+v", raw)
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' kv import T2 %s", srv.ClientURL(), file))
+		if err == nil {
+			t.Fatalf("expected importing into a bucket with values to require confirmation")
+		}
+
+		return nil
+	})
+}
+
+func TestCLIKVWatchFilterValidation(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		createTestJSBucket(t, nc, nil)
+
+		for _, args := range []string{"--ignore-deletes --only-deletes", "--key-regex '['", "--template '{{ .Key'"} {
+			err := runNatsCliWithError(t, fmt.Sprintf("--server='%s' kv watch T %s", srv.ClientURL(), args))
+			if err == nil {
+				t.Fatalf("expected kv watch with %s to fail", args)
+			}
+		}
+
+		return nil
+	})
+}
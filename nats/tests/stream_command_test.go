@@ -14,10 +14,21 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -63,6 +74,49 @@ func TestStreamAdd(t *testing.T) {
 	})
 }
 
+func TestStreamAddProfile(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		cfgDir := t.TempDir()
+		profileDir := filepath.Join(cfgDir, "nats", "cli", "profiles", "stream")
+		checkErr(t, os.MkdirAll(profileDir, 0700), "mkdir failed")
+
+		profile := `{"storage":"memory","max_age":3600000000000,"max_msgs":1000,"deny_delete":true,"metadata":{"team":"ops"}}`
+		checkErr(t, os.WriteFile(filepath.Join(profileDir, "golden.json"), []byte(profile), 0600), "write failed")
+
+		env := map[string]string{"XDG_CONFIG_HOME": cfgDir}
+
+		_, err := runNatsCliCore(t, "", env, fmt.Sprintf("--server='%s' stream add PROFILED --profile golden --subjects profiled --max-msgs 5 --defaults", srv.ClientURL()))
+		checkErr(t, err, "add failed")
+
+		s, err := mgr.LoadStream("PROFILED")
+		checkErr(t, err, "load failed")
+
+		cfg := s.Configuration()
+		if cfg.Storage != api.MemoryStorage {
+			t.Errorf("expected memory storage from profile, got %v", cfg.Storage)
+		}
+		if cfg.MaxAge != time.Hour {
+			t.Errorf("expected 1h max age from profile, got %v", cfg.MaxAge)
+		}
+		if cfg.MaxMsgs != 5 {
+			t.Errorf("expected flag to override profile max msgs, got %d", cfg.MaxMsgs)
+		}
+		if !cfg.DenyDelete {
+			t.Errorf("expected deny delete from profile")
+		}
+		if cfg.Metadata["team"] != "ops" {
+			t.Errorf("expected metadata from profile, got %v", cfg.Metadata)
+		}
+
+		_, err = runNatsCliCore(t, "", env, fmt.Sprintf("--server='%s' stream add OTHER --profile missing --subjects other --defaults", srv.ClientURL()))
+		if err == nil {
+			t.Errorf("expected unknown profile to fail")
+		}
+
+		return nil
+	})
+}
+
 func TestStreamAddDefaultMirror(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		name := setupStreamTest(t, mgr)
@@ -97,6 +151,68 @@ func TestStreamLS(t *testing.T) {
 		}
 		return nil
 	})
+
+	t.Run("--columns and --sort", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			for i, name := range []string{"ONE", "TWO", "THREE"} {
+				_, err := mgr.NewStream(name, jsm.Subjects(strings.ToLower(name)), jsm.MemoryStorage())
+				if err != nil {
+					t.Fatalf("unable to create stream: %s", err)
+				}
+
+				for j := 0; j < i+1; j++ {
+					_, err = nc.Request(strings.ToLower(name), []byte("hello"), time.Second)
+					if err != nil {
+						t.Fatalf("publish failed: %s", err)
+					}
+				}
+			}
+
+			var stats []map[string]any
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream ls --json --columns name,messages --sort messages --reverse", srv.ClientURL()))
+			err := json.Unmarshal(out, &stats)
+			if err != nil {
+				t.Fatalf("invalid json: %s: %s", err, out)
+			}
+
+			if len(stats) != 3 {
+				t.Fatalf("expected 3 streams got %d", len(stats))
+			}
+			for i, name := range []string{"THREE", "TWO", "ONE"} {
+				if stats[i]["name"] != name || stats[i]["messages"] != float64(3-i) {
+					t.Errorf("unexpected row %d: %v", i, stats[i])
+				}
+				if len(stats[i]) != 2 {
+					t.Errorf("expected 2 columns got %v", stats[i])
+				}
+			}
+
+			var names []string
+			out = runNatsCli(t, fmt.Sprintf("--server='%s' stream ls --json --sort name", srv.ClientURL()))
+			err = json.Unmarshal(out, &names)
+			if err != nil {
+				t.Fatalf("invalid json: %s: %s", err, out)
+			}
+			if strings.Join(names, ",") != "ONE,THREE,TWO" {
+				t.Errorf("unexpected names: %v", names)
+			}
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream ls --columns name --columns consumers,leader", srv.ClientURL())))
+			if !expectMatchLine(t, output, "Name", "Consumers", "Leader") {
+				t.Errorf("missing headers: %s", output)
+			}
+			if expectMatchLine(t, output, "Messages") {
+				t.Errorf("unexpected messages column: %s", output)
+			}
+
+			err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream ls --columns name,bogus", srv.ClientURL()))
+			if err == nil {
+				t.Errorf("expected an error for an unknown column")
+			}
+
+			return nil
+		})
+	})
 }
 
 func TestStreamReport(t *testing.T) {
@@ -107,8 +223,67 @@ func TestStreamReport(t *testing.T) {
 		if !expectMatchLine(t, output, name, "0", "0", "0 B", "0", "0") {
 			t.Errorf("missing stream %s from output: %s", name, output)
 		}
+
+		var stats []map[string]any
+		out := runNatsCli(t, fmt.Sprintf("--server='%s' stream report --json --columns name,storage,replicas", srv.ClientURL()))
+		err := json.Unmarshal(out, &stats)
+		if err != nil {
+			t.Fatalf("invalid json: %s: %s", err, out)
+		}
+		if len(stats) != 1 || stats[0]["name"] != name || stats[0]["storage"] != "File" || stats[0]["replicas"] != float64(1) {
+			t.Errorf("unexpected report: %v", stats)
+		}
 		return nil
 	})
+
+	t.Run("--consumer-stats", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			for i, name := range []string{"ONE", "TWO"} {
+				stream, err := mgr.NewStream(name, jsm.Subjects(strings.ToLower(name)), jsm.MemoryStorage())
+				checkErr(t, err, "create failed")
+
+				for j := 0; j < (i+1)*5; j++ {
+					_, err = nc.Request(strings.ToLower(name), []byte("hello"), time.Second)
+					checkErr(t, err, "publish failed")
+				}
+
+				_, err = stream.NewConsumer(jsm.DurableName("IDLE"), jsm.AcknowledgeExplicit())
+				checkErr(t, err, "consumer failed")
+				_, err = stream.NewConsumer(jsm.DurableName("BUSY"), jsm.AcknowledgeExplicit(), jsm.StartAtSequence(uint64(i+3)))
+				checkErr(t, err, "consumer failed")
+			}
+
+			var stats []map[string]any
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream report --consumer-stats --sort unprocessed --json", srv.ClientURL()))
+			err := json.Unmarshal(out, &stats)
+			if err != nil {
+				t.Fatalf("invalid json: %s: %s", err, out)
+			}
+
+			if len(stats) != 2 {
+				t.Fatalf("unexpected report: %v", stats)
+			}
+			if stats[0]["name"] != "ONE" || stats[0]["unprocessed"] != float64(8) || stats[0]["lagged"] != "IDLE" || stats[0]["consumers"] != float64(2) {
+				t.Errorf("unexpected report for ONE: %v", stats[0])
+			}
+			if stats[1]["name"] != "TWO" || stats[1]["unprocessed"] != float64(17) || stats[1]["ack_pending"] != float64(0) {
+				t.Errorf("unexpected report for TWO: %v", stats[1])
+			}
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream report --consumer-stats", srv.ClientURL())))
+			if !expectMatchLine(t, output, "TWO", "2", "10", "0", "17", "IDLE") {
+				t.Errorf("unexpected report: %s", output)
+			}
+
+			// sorting by consumers keeps the default columns
+			output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream report --consumers", srv.ClientURL())))
+			if strings.Contains(output, "Unprocessed") {
+				t.Errorf("consumer statistics shown without --consumer-stats: %s", output)
+			}
+
+			return nil
+		})
+	})
 }
 
 func TestStreamFind(t *testing.T) {
@@ -141,6 +316,41 @@ func TestStreamFind(t *testing.T) {
 		})
 	})
 
+	t.Run("--expression literals", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			_, err := mgr.NewStream("DAY", jsm.MaxAge(24*time.Hour), jsm.Subjects("day.>"))
+			if err != nil {
+				t.Fatalf("unable to create stream: %s", err)
+			}
+
+			_, err = mgr.NewStream("WEEK", jsm.MaxAge(7*24*time.Hour), jsm.Subjects("week.>"), jsm.MaxBytes(2*1024*1024))
+			if err != nil {
+				t.Fatalf("unable to create stream: %s", err)
+			}
+
+			_, err = nc.Request("day.1", []byte("hello"), time.Second)
+			if err != nil {
+				t.Fatalf("publish failed: %s", err)
+			}
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream find --names --expression 'duration(config.max_age) > 24h && state.messages == 0'", srv.ClientURL())))
+			if strings.TrimSpace(output) != "WEEK" {
+				t.Errorf("expected only WEEK: %s", output)
+			}
+
+			output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream find --names --expression 'config.max_bytes == 2MiB || config.name == \"1d\"'", srv.ClientURL())))
+			if strings.TrimSpace(output) != "WEEK" {
+				t.Errorf("expected only WEEK: %s", output)
+			}
+
+			output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream find --names --expression 'duration(config.max_age) <= 1d'", srv.ClientURL())))
+			if strings.TrimSpace(output) != "DAY" {
+				t.Errorf("expected only DAY: %s", output)
+			}
+
+			return nil
+		})
+	})
 }
 
 func TestStreamInfo(t *testing.T) {
@@ -251,6 +461,59 @@ func TestStreamState(t *testing.T) {
 	})
 }
 
+func TestStreamStateBySubject(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+
+		for _, subj := range []string{"ORDERS.a", "ORDERS.b", "ORDERS.a", "ORDERS.c", "ORDERS.a", "ORDERS.b"} {
+			_, err := nc.Request(subj, []byte("test"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		t.Run("json", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream state %s --by-subject --json", srv.ClientURL(), name))
+
+			var states []struct {
+				Subject  string `json:"subject"`
+				Messages uint64 `json:"messages"`
+				FirstSeq uint64 `json:"first_seq"`
+				LastSeq  uint64 `json:"last_seq"`
+			}
+			checkErr(t, json.Unmarshal(out, &states), "invalid json: %s", out)
+
+			if len(states) != 3 {
+				t.Fatalf("expected 3 subjects got %s", out)
+			}
+			for i, expect := range [][3]any{{"ORDERS.a", 3, 5}, {"ORDERS.b", 2, 6}, {"ORDERS.c", 1, 4}} {
+				if states[i].Subject != expect[0] || states[i].Messages != uint64(expect[1].(int)) || states[i].LastSeq != uint64(expect[2].(int)) {
+					t.Fatalf("unexpected state %d: %+v", i, states[i])
+				}
+			}
+			if states[0].FirstSeq != 1 || states[1].FirstSeq != 2 || states[2].FirstSeq != 4 {
+				t.Fatalf("unexpected first sequences: %+v", states)
+			}
+		})
+
+		t.Run("csv", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream state %s --by-subject --csv --filter ORDERS.b --filter ORDERS.c --min-msgs 2", srv.ClientURL(), name))
+			records, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+			checkErr(t, err, "invalid csv: %s", out)
+
+			if len(records) != 2 || strings.Join(records[0], ",") != "subject,messages,first_seq,last_seq,last_activity" || strings.Join(records[1][:4], ",") != "ORDERS.b,2,2,6" {
+				t.Fatalf("unexpected csv: %s", out)
+			}
+		})
+
+		t.Run("table", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream state %s --by-subject", srv.ClientURL(), name))
+			expectMatchLine(t, string(out), "State of 3 Subjects in Stream")
+			expectMatchLine(t, string(out), "ORDERS.a", "3", "1", "5")
+		})
+
+		return nil
+	})
+}
+
 func TestStreamSubjects(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		name := setupStreamTest(t, mgr)
@@ -266,8 +529,87 @@ func TestStreamSubjects(t *testing.T) {
 		if !expectMatchLine(t, output, subject, "1") {
 			t.Errorf("missing stream %s from output: %s", name, output)
 		}
+
+		err = nc.Publish("ORDERS.old", []byte(msg))
+		if err != nil {
+			t.Errorf("unable to publish message to stream %s: %s", name, err)
+		}
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream subjects %s --filter ORDERS.old --names", srv.ClientURL(), name)))
+		if strings.TrimSpace(output) != "ORDERS.old" {
+			t.Errorf("unexpected filtered subjects: %s", output)
+		}
 		return nil
 	})
+
+	t.Run("paging and filters", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			_, err := mgr.NewStream("SUBJECTS", jsm.Subjects("s.>"))
+			checkErr(t, err, "unable to create stream")
+
+			for i := 1; i <= 6; i++ {
+				for j := 0; j < i; j++ {
+					_, err = nc.Request(fmt.Sprintf("s.%d", i), nil, time.Second)
+					checkErr(t, err, "publish failed")
+				}
+			}
+			_, err = nc.Request("s.x.1", nil, time.Second)
+			checkErr(t, err, "publish failed")
+
+			var counts map[string]uint64
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream subjects SUBJECTS --json --filter 's.*' --filter 's.>' --min-msgs 1 --max-msgs 4", srv.ClientURL()))
+			err = json.Unmarshal(out, &counts)
+			checkErr(t, err, "invalid json: %s", out)
+			if len(counts) != 5 || counts["s.4"] != 4 || counts["s.x.1"] != 1 {
+				t.Errorf("unexpected subjects: %v", counts)
+			}
+
+			type page struct {
+				Subjects []struct {
+					Subject  string `json:"subject"`
+					Messages uint64 `json:"messages"`
+				} `json:"subjects"`
+				Next string `json:"next"`
+			}
+
+			var seen []string
+			var token string
+			for i := 0; i < 10; i++ {
+				var p page
+				out = runNatsCli(t, fmt.Sprintf("--server='%s' stream subjects SUBJECTS --json --filter 's.*' --filter 's.x.>' --min-msgs 2 --page-size 2 --page-token '%s'", srv.ClientURL(), token))
+				err = json.Unmarshal(out, &p)
+				checkErr(t, err, "invalid json: %s", out)
+
+				if len(p.Subjects) > 2 {
+					t.Fatalf("page too large: %s", out)
+				}
+				for _, s := range p.Subjects {
+					seen = append(seen, s.Subject)
+				}
+
+				token = p.Next
+				if token == "" {
+					break
+				}
+			}
+
+			if strings.Join(seen, ",") != "s.2,s.3,s.4,s.5,s.6" {
+				t.Errorf("unexpected paged subjects: %v", seen)
+			}
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream subjects SUBJECTS --page-size 3", srv.ClientURL())))
+			if !expectMatchLine(t, output, "Next page token:") || !expectMatchLine(t, output, "s.1", "1") {
+				t.Errorf("unexpected output: %s", output)
+			}
+
+			err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream subjects SUBJECTS --page-size 3 --sort messages", srv.ClientURL()))
+			if err == nil {
+				t.Errorf("expected sorting paged results to fail")
+			}
+
+			return nil
+		})
+	})
 }
 
 func TestStreamEdit(t *testing.T) {
@@ -282,6 +624,58 @@ func TestStreamEdit(t *testing.T) {
 	})
 }
 
+func TestStreamEditSelect(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		for _, name := range []string{"ORDERS_1", "ORDERS_2", "OTHER"} {
+			_, err := mgr.NewStream(name, jsm.Subjects(strings.ToLower(name)), jsm.MaxAge(time.Hour))
+			checkErr(t, err, "unable to create stream")
+		}
+
+		maxAge := func(name string) time.Duration {
+			t.Helper()
+			stream, err := mgr.LoadStream(name)
+			checkErr(t, err, "load failed")
+			return stream.MaxAge()
+		}
+
+		err := runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream edit --select 'ORDERS_*' --max-age 48h --dry-run", srv.ClientURL()))
+		if err == nil {
+			t.Fatalf("expected dry run with differences to fail")
+		}
+		if maxAge("ORDERS_1") != time.Hour {
+			t.Fatalf("dry run edited the stream")
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream edit --select 'ORDERS_*' --max-age 48h --force", srv.ClientURL())))
+		if !expectMatchLine(t, output, "Stream ORDERS_1 differences") || !expectMatchLine(t, output, "Stream ORDERS_2 differences") || expectMatchLine(t, output, "OTHER") {
+			t.Errorf("unexpected diff output: %s", output)
+		}
+		if !expectMatchLine(t, output, "Updated 2 Streams") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		for name, expected := range map[string]time.Duration{"ORDERS_1": 48 * time.Hour, "ORDERS_2": 48 * time.Hour, "OTHER": time.Hour} {
+			if maxAge(name) != expected {
+				t.Errorf("expected %s max age %v got %v", name, expected, maxAge(name))
+			}
+		}
+
+		runNatsCli(t, fmt.Sprintf("--server='%s' stream edit --select-expr 'duration(config.max_age) < 1d' --description TEST --force", srv.ClientURL()))
+		other, err := mgr.LoadStream("OTHER")
+		checkErr(t, err, "load failed")
+		if other.Description() != "TEST" {
+			t.Errorf("expected OTHER to be edited")
+		}
+		orders, err := mgr.LoadStream("ORDERS_1")
+		checkErr(t, err, "load failed")
+		if orders.Description() != "" {
+			t.Errorf("expected ORDERS_1 to be unchanged")
+		}
+
+		return nil
+	})
+}
+
 func TestStreamEditMirrorPromote(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		_, err := mgr.NewStream("TEST", jsm.Subjects("test.>"))
@@ -363,6 +757,74 @@ func TestStreamPurge(t *testing.T) {
 		}
 		return nil
 	})
+
+	t.Run("--dry-run and --older-than", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			stream, err := mgr.NewStream("PURGE", jsm.Subjects("purge.>"), jsm.MemoryStorage())
+			if err != nil {
+				t.Fatalf("unable to create stream: %s", err)
+			}
+
+			publish := func(subj string, count int) {
+				for i := 0; i < count; i++ {
+					_, err := nc.Request(subj, []byte("hello"), time.Second)
+					if err != nil {
+						t.Fatalf("publish failed: %s", err)
+					}
+				}
+			}
+
+			publish("purge.a", 3)
+			publish("purge.b", 2)
+			time.Sleep(2 * time.Second)
+			publish("purge.a", 2)
+
+			type purgePreview struct {
+				Subjects map[string]uint64 `json:"subjects"`
+				Total    uint64            `json:"total"`
+			}
+			var preview purgePreview
+
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream purge PURGE --dry-run --older-than 1s --json", srv.ClientURL()))
+			err = json.Unmarshal(out, &preview)
+			if err != nil {
+				t.Fatalf("invalid json: %s: %s", err, out)
+			}
+			if preview.Total != 5 || preview.Subjects["purge.a"] != 3 || preview.Subjects["purge.b"] != 2 {
+				t.Errorf("unexpected preview: %+v", preview)
+			}
+
+			state, err := stream.State()
+			checkErr(t, err, "state failed")
+			if state.Msgs != 7 {
+				t.Fatalf("dry run purged messages, %d left", state.Msgs)
+			}
+
+			runNatsCli(t, fmt.Sprintf("--server='%s' stream purge PURGE --older-than 1s --subject purge.a --force", srv.ClientURL()))
+			state, err = stream.State()
+			checkErr(t, err, "state failed")
+			if state.Msgs != 4 {
+				t.Fatalf("expected 4 messages after purge got %d", state.Msgs)
+			}
+
+			preview = purgePreview{}
+			out = runNatsCli(t, fmt.Sprintf("--server='%s' stream purge PURGE --dry-run --subject purge.a --keep 1 --json", srv.ClientURL()))
+			err = json.Unmarshal(out, &preview)
+			if err != nil {
+				t.Fatalf("invalid json: %s: %s", err, out)
+			}
+			if preview.Total != 1 || preview.Subjects["purge.a"] != 1 {
+				t.Errorf("unexpected preview: %+v", preview)
+			}
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream purge PURGE --dry-run", srv.ClientURL())))
+			if !expectMatchLine(t, output, "purge.a", "2") || !expectMatchLine(t, output, "purge.b", "2") || !expectMatchLine(t, output, "Total", "4") {
+				t.Errorf("unexpected preview: %s", output)
+			}
+
+			return nil
+		})
+	})
 }
 
 func TestStreamCopy(t *testing.T) {
@@ -380,62 +842,529 @@ func TestStreamCopy(t *testing.T) {
 	})
 }
 
-func TestStreamRMM(t *testing.T) {
+func TestStreamCopyMessages(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
-		name := setupStreamTest(t, mgr)
-
-		err := nc.Publish("ORDERS.new", []byte("TEST MESSAGE"))
-		if err != nil {
-			t.Errorf("failed to pushlish message to stream: %s %s", name, err)
-		}
+		withJSServer(t, func(t *testing.T, target *server.Server, tnc *nats.Conn, tmgr *jsm.Manager) error {
+			name := setupStreamTest(t, mgr)
+
+			publish := func(start, count int) {
+				for i := start; i < start+count; i++ {
+					msg := nats.NewMsg("ORDERS.new")
+					msg.Header.Set("Nats-Msg-Id", fmt.Sprintf("msg-%d", i))
+					msg.Header.Set("Order", fmt.Sprintf("%d", i))
+					msg.Data = []byte(fmt.Sprintf("order %d", i))
+					_, err := nc.RequestMsg(msg, time.Second)
+					if err != nil {
+						t.Fatalf("publish failed: %v", err)
+					}
+				}
+			}
 
-		const maxAttempts = 5
+			dir := t.TempDir()
+			ctxFile := filepath.Join(dir, "target.json")
+			err := os.WriteFile(ctxFile, []byte(fmt.Sprintf(`{"url":%q}`, target.ClientURL())), 0600)
+			if err != nil {
+				t.Fatalf("unable to write context: %s", err)
+			}
+			progressFile := filepath.Join(dir, "progress.json")
 
-		info, err := getStreamInfo(name, mgr)
-		if err != nil {
-			t.Error(err)
-		}
+			copyCmd := fmt.Sprintf("--server='%s' stream copy %s %s --target-context=%s --messages --progress-file=%s --no-progress", srv.ClientURL(), name, name, ctxFile, progressFile)
 
-		for i := 0; i < maxAttempts; i++ {
-			if info.Msgs != 1 {
-				time.Sleep(50 * time.Millisecond)
+			publish(0, 600)
+			output := string(runNatsCli(t, copyCmd))
+			if !expectMatchLine(t, output, fmt.Sprintf("Copied 600 messages from %s to %s", name, name)) {
+				t.Errorf("unexpected output: %s", output)
 			}
-			if i == maxAttempts {
-				t.Errorf("unexpected number of messages in stream: %d but expected 1", info.Msgs)
+
+			// a second copy resumes after the last copied message
+			publish(600, 10)
+			output = string(runNatsCli(t, copyCmd))
+			if !expectMatchLine(t, output, fmt.Sprintf("Copied 10 messages from %s to %s", name, name)) {
+				t.Errorf("unexpected output: %s", output)
 			}
-		}
 
-		runNatsCli(t, fmt.Sprintf("--server='%s' stream rmm %s 1 --force", srv.ClientURL(), name))
+			stream, err := tmgr.LoadStream(name)
+			if err != nil {
+				t.Fatalf("target stream not found: %v", err)
+			}
+			nfo, err := stream.State()
+			if err != nil {
+				t.Fatalf("state failed: %v", err)
+			}
+			if nfo.Msgs != 610 {
+				t.Errorf("expected 610 messages got %d", nfo.Msgs)
+			}
 
-		info, err = getStreamInfo(name, mgr)
-		if err != nil {
-			t.Error(err)
-		}
-		for i := 0; i < maxAttempts; i++ {
-			if info.Msgs != 0 {
-				time.Sleep(50 * time.Millisecond)
+			msg, err := stream.ReadMessage(605)
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
 			}
-			if i == maxAttempts {
-				t.Errorf("unexpected number of messages in stream: %d but expected 1", info.Msgs)
+			if msg.Subject != "ORDERS.new" || string(msg.Data) != "order 604" {
+				t.Errorf("unexpected message %s: %q", msg.Subject, msg.Data)
 			}
-		}
+			hdr, err := nats.DecodeHeadersMsg(msg.Header)
+			if err != nil {
+				t.Fatalf("invalid headers: %v", err)
+			}
+			if hdr.Get("Nats-Msg-Id") != "msg-604" || hdr.Get("Order") != "604" {
+				t.Errorf("headers were not preserved: %v", hdr)
+			}
+
+			return nil
+		})
+
 		return nil
 	})
 }
 
-// View command has to be run with a terminal
-//func TestStreamView(t *testing.T) {}
-
-func TestStreamGet(t *testing.T) {
+func TestStreamCopyMessagesSameContext(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		name := setupStreamTest(t, mgr)
-
-		err := nc.Publish("ORDERS.new", []byte("TEST MESSAGE"))
-		if err != nil {
-			t.Errorf("failed to pushlish message to stream: %s %s", name, err)
+		for i := 0; i < 10; i++ {
+			_, err := nc.Request(fmt.Sprintf("ORDERS.%d", i), []byte(fmt.Sprintf("order %d", i)), time.Second)
+			if err != nil {
+				t.Fatalf("publish failed: %v", err)
+			}
 		}
 
-		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream get %s 1 --json", srv.ClientURL(), name)))
+		// the source stream would capture the copied messages so the copy is refused before creating anything
+		out, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream copy %s COPY --subjects=COPY.* --messages --no-progress", srv.ClientURL(), name))
+		if err == nil {
+			t.Fatalf("expected the copy to fail: %s", out)
+		}
+		if !strings.Contains(string(out), "requires --target-context or --transform") {
+			t.Errorf("unexpected error: %s", out)
+		}
+		known, err := mgr.IsKnownStream("COPY")
+		if err != nil {
+			t.Fatalf("could not check stream: %v", err)
+		}
+		if known {
+			t.Errorf("destination stream should not have been created")
+		}
+
+		out, err = runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream copy %s COPY --transform 'ORDERS.>:ORDERS.>' --messages --no-progress", srv.ClientURL(), name))
+		if err == nil || !strings.Contains(string(out), "overlaps with source subject ORDERS.*") {
+			t.Errorf("expected overlapping transform to fail: %s", out)
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream copy %s COPY --transform 'ORDERS.>:COPY.>' --messages --no-progress", srv.ClientURL(), name)))
+		if !expectMatchLine(t, output, fmt.Sprintf("Copied 10 messages from %s to COPY", name)) {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		stream, err := mgr.LoadStream("COPY")
+		if err != nil {
+			t.Fatalf("target stream not found: %v", err)
+		}
+		if len(stream.Subjects()) != 1 || stream.Subjects()[0] != "COPY.*" {
+			t.Errorf("unexpected subjects: %v", stream.Subjects())
+		}
+		msg, err := stream.ReadMessage(4)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if msg.Subject != "COPY.3" || string(msg.Data) != "order 3" {
+			t.Errorf("unexpected message %s: %q", msg.Subject, msg.Data)
+		}
+
+		return nil
+	})
+}
+
+func TestStreamExport(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+		for i := 1; i <= 10; i++ {
+			msg := nats.NewMsg(fmt.Sprintf("ORDERS.%d", i%2))
+			msg.Header.Set("Order", fmt.Sprintf("%d", i))
+			msg.Data = []byte(fmt.Sprintf(`{"order":%d}`, i))
+			_, err := nc.RequestMsg(msg, time.Second)
+			if err != nil {
+				t.Fatalf("publish failed: %v", err)
+			}
+		}
+
+		dir := t.TempDir()
+		jsonl := filepath.Join(dir, "orders.jsonl")
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream export %s --output=%s --subject=ORDERS.1 --start-seq=3 --end-seq=8 --encoding=json --no-progress", srv.ClientURL(), name, jsonl)))
+		if !expectMatchLine(t, output, fmt.Sprintf("Exported 3 messages from %s to %s", name, jsonl)) {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		body, err := os.ReadFile(jsonl)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("expected 3 lines got %d: %s", len(lines), body)
+		}
+		err = expectMatchJSON(t, lines[0], map[string]any{
+			"seq":     "3",
+			"subject": "ORDERS.1",
+			"headers": map[string]any{"Order": []any{"3"}},
+			"data":    map[string]any{"order": "3"},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+
+		csvFile := filepath.Join(dir, "orders.csv")
+		runNatsCli(t, fmt.Sprintf("--server='%s' stream export %s --output=%s --encoding=string --no-progress", srv.ClientURL(), name, csvFile))
+		body, err = os.ReadFile(csvFile)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		lines = strings.Split(strings.TrimSpace(string(body)), "\n")
+		if len(lines) != 11 || lines[0] != "seq,time,subject,headers,data" {
+			t.Fatalf("unexpected csv: %s", body)
+		}
+		if !regexp.MustCompile(`^10,.+,ORDERS.0,"{""Order"":\[""10""\]}","{""order"":10}"$`).MatchString(lines[10]) {
+			t.Errorf("unexpected csv line: %s", lines[10])
+		}
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream export %s --subject=OTHER --since=1h", srv.ClientURL(), name)))
+		if output != "" {
+			t.Errorf("expected no messages: %s", output)
+		}
+
+		return nil
+	})
+}
+
+func TestStreamImport(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+		for i := 1; i <= 10; i++ {
+			msg := nats.NewMsg(fmt.Sprintf("ORDERS.%d", i%2))
+			msg.Header.Set("Order", fmt.Sprintf("%d", i))
+			msg.Data = []byte{0, byte(i)}
+			_, err := nc.RequestMsg(msg, time.Second)
+			if err != nil {
+				t.Fatalf("publish failed: %v", err)
+			}
+		}
+
+		export := filepath.Join(t.TempDir(), "orders.jsonl")
+		runNatsCli(t, fmt.Sprintf("--server='%s' stream export %s --output=%s --no-progress", srv.ClientURL(), name, export))
+
+		err := mgr.DeleteStream(name)
+		if err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+
+		_, err = mgr.NewStream("OTHER", jsm.Subjects("OTHER.*"))
+		if err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream import OTHER %s --dry-run", srv.ClientURL(), export))
+		if err == nil {
+			t.Errorf("expected dry run against OTHER to fail")
+		}
+
+		target, err := mgr.NewStream("IMPORTED", jsm.Subjects("ORDERS.*"))
+		if err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream import IMPORTED %s --dry-run", srv.ClientURL(), export)))
+		if !expectMatchLine(t, output, "10 records are valid for stream IMPORTED") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream import IMPORTED %s --rate=1000", srv.ClientURL(), export)))
+		if !expectMatchLine(t, output, "Imported 10 messages into IMPORTED, 0 were duplicates") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream import IMPORTED %s", srv.ClientURL(), export)))
+		if !expectMatchLine(t, output, "Imported 10 messages into IMPORTED, 10 were duplicates") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		msg, err := target.ReadMessage(10)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		hdr, err := nats.DecodeHeadersMsg(msg.Header)
+		if err != nil {
+			t.Fatalf("invalid headers: %v", err)
+		}
+		if msg.Subject != "ORDERS.0" || !bytes.Equal(msg.Data, []byte{0, 10}) || hdr.Get("Order") != "10" {
+			t.Errorf("unexpected message %s: %v %v", msg.Subject, msg.Data, hdr)
+		}
+
+		return nil
+	})
+}
+
+func TestStreamImportControlHeaders(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+
+		_, err := nc.Request("ORDERS.1", []byte("first"), time.Second)
+		if err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+		msg := nats.NewMsg("ORDERS.2")
+		msg.Header.Set("Nats-Expected-Last-Sequence", "1")
+		msg.Data = []byte("second")
+		_, err = nc.RequestMsg(msg, time.Second)
+		if err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+
+		export := filepath.Join(t.TempDir(), "orders.jsonl")
+		runNatsCli(t, fmt.Sprintf("--server='%s' stream export %s --output=%s --no-progress", srv.ClientURL(), name, export))
+
+		err = mgr.DeleteStream(name)
+		if err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+
+		// the existing message moves the sequences so the recorded expectation would fail if it was published again
+		target, err := mgr.NewStream("IMPORTED", jsm.Subjects("ORDERS.*"))
+		if err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+		_, err = nc.Request("ORDERS.0", []byte("existing"), time.Second)
+		if err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream import IMPORTED %s", srv.ClientURL(), export)))
+		if !expectMatchLine(t, output, "Imported 2 messages into IMPORTED, 0 were duplicates") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		stored, err := target.ReadMessage(3)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		hdr, err := nats.DecodeHeadersMsg(stored.Header)
+		if err != nil {
+			t.Fatalf("invalid headers: %v", err)
+		}
+		if stored.Subject != "ORDERS.2" || hdr.Get("Nats-Expected-Last-Sequence") != "" {
+			t.Errorf("unexpected message %s: %v", stored.Subject, hdr)
+		}
+
+		return nil
+	})
+}
+
+func TestStreamImportMultipleSources(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		dir := t.TempDir()
+		var exports []string
+
+		// both streams hold messages with the same sequences, their generated message ids must not collide
+		for _, name := range []string{"EAST", "WEST"} {
+			_, err := mgr.NewStream(name, jsm.Subjects(fmt.Sprintf("ORDERS.%s.*", strings.ToLower(name))))
+			if err != nil {
+				t.Fatalf("create failed: %v", err)
+			}
+			for i := 1; i <= 5; i++ {
+				_, err = nc.Request(fmt.Sprintf("ORDERS.%s.%d", strings.ToLower(name), i), []byte("order"), time.Second)
+				if err != nil {
+					t.Fatalf("publish failed: %v", err)
+				}
+			}
+
+			export := filepath.Join(dir, name+".jsonl")
+			runNatsCli(t, fmt.Sprintf("--server='%s' stream export %s --output=%s --no-progress", srv.ClientURL(), name, export))
+			exports = append(exports, export)
+
+			err = mgr.DeleteStream(name)
+			if err != nil {
+				t.Fatalf("delete failed: %v", err)
+			}
+		}
+
+		target, err := mgr.NewStream("IMPORTED", jsm.Subjects("ORDERS.>"))
+		if err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+
+		for _, export := range exports {
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream import IMPORTED %s", srv.ClientURL(), export)))
+			if !expectMatchLine(t, output, "Imported 5 messages into IMPORTED, 0 were duplicates") {
+				t.Errorf("unexpected output: %s", output)
+			}
+		}
+
+		nfo, err := target.State()
+		if err != nil {
+			t.Fatalf("state failed: %v", err)
+		}
+		if nfo.Msgs != 10 {
+			t.Errorf("expected 10 messages got %d", nfo.Msgs)
+		}
+
+		msg, err := target.ReadMessage(6)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		hdr, err := nats.DecodeHeadersMsg(msg.Header)
+		if err != nil {
+			t.Fatalf("invalid headers: %v", err)
+		}
+		if hdr.Get("Nats-Msg-Id") != "WEST-1" {
+			t.Errorf("unexpected message id %q", hdr.Get("Nats-Msg-Id"))
+		}
+
+		return nil
+	})
+}
+
+func TestStreamRMM(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+
+		err := nc.Publish("ORDERS.new", []byte("TEST MESSAGE"))
+		if err != nil {
+			t.Errorf("failed to pushlish message to stream: %s %s", name, err)
+		}
+
+		const maxAttempts = 5
+
+		info, err := getStreamInfo(name, mgr)
+		if err != nil {
+			t.Error(err)
+		}
+
+		for i := 0; i < maxAttempts; i++ {
+			if info.Msgs != 1 {
+				time.Sleep(50 * time.Millisecond)
+			}
+			if i == maxAttempts {
+				t.Errorf("unexpected number of messages in stream: %d but expected 1", info.Msgs)
+			}
+		}
+
+		runNatsCli(t, fmt.Sprintf("--server='%s' stream rmm %s 1 --force", srv.ClientURL(), name))
+
+		info, err = getStreamInfo(name, mgr)
+		if err != nil {
+			t.Error(err)
+		}
+		for i := 0; i < maxAttempts; i++ {
+			if info.Msgs != 0 {
+				time.Sleep(50 * time.Millisecond)
+			}
+			if i == maxAttempts {
+				t.Errorf("unexpected number of messages in stream: %d but expected 1", info.Msgs)
+			}
+		}
+		return nil
+	})
+}
+
+func TestStreamRMMFilter(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		stream, err := mgr.NewStream("RMM", jsm.Subjects("rmm.>"), jsm.MemoryStorage())
+		if err != nil {
+			t.Fatalf("unable to create stream: %s", err)
+		}
+
+		publish := func(subj string, count int) {
+			for i := 0; i < count; i++ {
+				_, err := nc.Request(subj, []byte("hello"), time.Second)
+				if err != nil {
+					t.Fatalf("publish failed: %s", err)
+				}
+			}
+		}
+
+		publish("rmm.bad", 10)
+		publish("rmm.good", 5)
+		time.Sleep(2 * time.Second)
+		publish("rmm.bad", 3)
+
+		out := runNatsCli(t, fmt.Sprintf("--server='%s' stream rmm RMM --subject rmm.bad --older-than 1s --dry-run", srv.ClientURL()))
+		if !expectMatchLine(t, string(out), "Would remove 10 messages from Stream RMM") {
+			t.Errorf("unexpected dry run output: %s", out)
+		}
+
+		state, err := stream.State()
+		checkErr(t, err, "state failed")
+		if state.Msgs != 18 {
+			t.Fatalf("dry run removed messages, %d left", state.Msgs)
+		}
+
+		out = runNatsCli(t, fmt.Sprintf("--server='%s' stream rmm RMM --subject rmm.bad --older-than 1s --no-progress -f", srv.ClientURL()))
+		if !expectMatchLine(t, string(out), "Removed 10 messages from Stream RMM") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		out = runNatsCli(t, fmt.Sprintf("--server='%s' stream rmm RMM --subject rmm.bad --no-erase --no-progress -f", srv.ClientURL()))
+		if !expectMatchLine(t, string(out), "Removed 3 messages from Stream RMM") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		state, err = stream.State()
+		checkErr(t, err, "state failed")
+		if state.Msgs != 5 || state.FirstSeq != 11 {
+			t.Errorf("expected 5 messages starting at 11, got %d starting at %d", state.Msgs, state.FirstSeq)
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream rmm RMM 1 --subject rmm.bad -f", srv.ClientURL()))
+		if err == nil {
+			t.Errorf("expected sequence and --subject to fail")
+		}
+
+		return nil
+	})
+}
+
+// View command has to be run with a terminal
+//func TestStreamView(t *testing.T) {}
+
+func TestStreamViewOrdered(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+
+		for i := 1; i <= 30; i++ {
+			_, err := nc.Request("ORDERS.new", []byte(fmt.Sprintf("order %d", i)), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream view %s --ordered", srv.ClientURL(), name)))
+		expectMatchLine(t, output, "order 1$")
+		expectMatchLine(t, output, "order 30$")
+		expectMatchLine(t, output, "Reached apparent end of data")
+
+		output = string(runNatsCli(t, fmt.Sprintf("--server='%s' stream view %s --ordered --id 29", srv.ClientURL(), name)))
+		if strings.Contains(output, "order 28") {
+			t.Fatalf("expected messages before sequence 29 to be skipped: %s", output)
+		}
+		expectMatchLine(t, output, "order 29$")
+
+		consumers, err := mgr.ConsumerNames(name)
+		checkErr(t, err, "consumer names failed")
+		for _, c := range consumers {
+			cons, err := mgr.LoadConsumer(name, c)
+			checkErr(t, err, "load failed")
+			if cons.IsDurable() {
+				t.Fatalf("expected no durable consumers, found %s", c)
+			}
+		}
+
+		return nil
+	})
+}
+
+func TestStreamGet(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+
+		err := nc.Publish("ORDERS.new", []byte("TEST MESSAGE"))
+		if err != nil {
+			t.Errorf("failed to pushlish message to stream: %s %s", name, err)
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream get %s 1 --json", srv.ClientURL(), name)))
 		err = expectMatchJSON(t, output, map[string]any{
 			"subject": "ORDERS.new",
 			"seq":     1,
@@ -448,6 +1377,83 @@ func TestStreamGet(t *testing.T) {
 
 		return nil
 	})
+
+	for _, direct := range []bool{true, false} {
+		t.Run(fmt.Sprintf("range direct=%t", direct), func(t *testing.T) {
+			withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+				opts := []jsm.StreamOption{jsm.Subjects("range.>")}
+				if direct {
+					opts = append(opts, jsm.AllowDirect())
+				}
+				stream, err := mgr.NewStream("RANGE", opts...)
+				checkErr(t, err, "unable to create stream")
+
+				for i := 1; i <= 600; i++ {
+					msg := nats.NewMsg(fmt.Sprintf("range.%d", i))
+					msg.Data = []byte(fmt.Sprintf("msg %d", i))
+					msg.Header.Add("X-Index", strconv.Itoa(i))
+					_, err = nc.RequestMsg(msg, time.Second)
+					checkErr(t, err, "publish failed")
+				}
+				checkErr(t, stream.DeleteMessage(5), "delete failed")
+
+				fetch := func(args string) []api.StoredMsg {
+					t.Helper()
+					var msgs []api.StoredMsg
+					out := runNatsCli(t, fmt.Sprintf("--server='%s' stream get RANGE %s --json", srv.ClientURL(), args))
+					checkErr(t, json.Unmarshal(out, &msgs), "invalid json: %s", out)
+					return msgs
+				}
+
+				msgs := fetch("--from 3 --to 8")
+				var seqs []string
+				for _, m := range msgs {
+					seqs = append(seqs, strconv.FormatUint(m.Sequence, 10))
+				}
+				if strings.Join(seqs, ",") != "3,4,6,7,8" {
+					t.Fatalf("unexpected sequences: %v", seqs)
+				}
+				if msgs[0].Subject != "range.3" || string(msgs[0].Data) != "msg 3" || !strings.Contains(string(msgs[0].Header), "X-Index: 3") {
+					t.Errorf("unexpected message: %+v", msgs[0])
+				}
+
+				msgs = fetch("--last 3")
+				if len(msgs) != 3 || msgs[0].Sequence != 598 || msgs[2].Sequence != 600 {
+					t.Errorf("unexpected last messages: %+v", msgs)
+				}
+
+				msgs = fetch("--from 10")
+				if len(msgs) != 591 || msgs[590].Sequence != 600 {
+					t.Errorf("expected 591 messages got %d", len(msgs))
+				}
+
+				output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream get RANGE --from 599", srv.ClientURL())))
+				if !expectMatchLine(t, output, "Item: RANGE#599") || !expectMatchLine(t, output, "Item: RANGE#600") || !expectMatchLine(t, output, "X-Index: 600") {
+					t.Errorf("unexpected output: %s", output)
+				}
+
+				// --last counts messages so deleted messages do not shorten the output
+				checkErr(t, stream.DeleteMessage(597), "delete failed")
+				checkErr(t, stream.DeleteMessage(600), "delete failed")
+				msgs = fetch("--last 3")
+				seqs = nil
+				for _, m := range msgs {
+					seqs = append(seqs, strconv.FormatUint(m.Sequence, 10))
+				}
+				if strings.Join(seqs, ",") != "596,598,599" {
+					t.Errorf("unexpected last messages: %v", seqs)
+				}
+
+				// a range holding only deleted messages is empty
+				msgs = fetch("--from 600")
+				if len(msgs) != 0 {
+					t.Errorf("expected no messages got %+v", msgs)
+				}
+
+				return nil
+			})
+		})
+	}
 }
 
 func TestStreamBackup(t *testing.T) {
@@ -464,18 +1470,244 @@ func TestStreamBackup(t *testing.T) {
 	})
 }
 
-func TestStreamRestore(t *testing.T) {
+func TestStreamBackupSchedule(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		for _, name := range []string{"SCHED_A", "SCHED_B", "OTHER"} {
+			_, err := mgr.NewStream(name, jsm.Subjects(strings.ToLower(name)))
+			checkErr(t, err, "stream create failed")
+		}
+
+		events, err := nc.SubscribeSync("backups.done")
+		checkErr(t, err, "subscribe failed")
+		checkErr(t, nc.Flush(), "flush failed")
+
+		tmpDir := t.TempDir()
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream backup 'SCHED_*' %s --schedule 'every 1s' --keep 1 --runs 2 --event-subject backups.done", srv.ClientURL(), tmpDir)))
+		expectMatchLine(t, output, `Next backup of streams matching "SCHED_\*" at`)
+		expectMatchLine(t, output, `Removed old backup .+SCHED_A`)
+
+		for _, name := range []string{"SCHED_A", "SCHED_B"} {
+			entries, err := os.ReadDir(filepath.Join(tmpDir, name))
+			checkErr(t, err, "read dir failed")
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 backup of %s got %d", name, len(entries))
+			}
+			_, err = os.Stat(filepath.Join(tmpDir, name, entries[0].Name(), "backup.json"))
+			if err != nil {
+				t.Fatalf("backup of %s is incomplete", name)
+			}
+		}
+
+		_, err = os.Stat(filepath.Join(tmpDir, "OTHER"))
+		if !os.IsNotExist(err) {
+			t.Fatalf("unmatched stream was backed up")
+		}
+
+		var streams []string
+		for i := 0; i < 4; i++ {
+			msg, err := events.NextMsg(time.Second)
+			checkErr(t, err, "no backup event received")
+
+			var event map[string]any
+			checkErr(t, json.Unmarshal(msg.Data, &event), "invalid event")
+			if event["error"] != nil {
+				t.Fatalf("backup failed: %s", msg.Data)
+			}
+			streams = append(streams, event["stream"].(string))
+		}
+
+		if strings.Join(streams, ",") != "SCHED_A,SCHED_B,SCHED_A,SCHED_B" {
+			t.Fatalf("unexpected events for %v", streams)
+		}
+
+		return nil
+	})
+}
+
+func TestStreamRestore(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+		tmpDir := t.TempDir()
+
+		runNatsCli(t, fmt.Sprintf("--server='%s' stream backup %s %s", srv.ClientURL(), name, tmpDir))
+		mgr.DeleteStream(name)
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream restore %s", srv.ClientURL(), tmpDir)))
+		if !expectMatchLine(t, output, fmt.Sprintf("Starting restore of Stream \"%s\"", name)) ||
+			!expectMatchLine(t, output, fmt.Sprintf("Restored stream \"%s\" in \\d+s", name)) {
+			t.Errorf("Unexecpted output :%s", output)
+		}
+		return nil
+	})
+}
+
+func TestStreamRestoreTransform(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		backup := func(t *testing.T) (string, string) {
+			name := setupStreamTest(t, mgr)
+			for i, subj := range []string{"ORDERS.new", "ORDERS.new", "ORDERS.new", "ORDERS.done", "ORDERS.done"} {
+				msg := nats.NewMsg(subj)
+				msg.Header.Set("Nats-Msg-Id", strconv.Itoa(i))
+				msg.Header.Set("Nats-Expected-Last-Sequence", strconv.Itoa(i))
+				_, err := nc.RequestMsg(msg, time.Second)
+				checkErr(t, err, "publish failed")
+			}
+
+			tmpDir := t.TempDir()
+			runNatsCli(t, fmt.Sprintf("--server='%s' stream backup %s %s --no-progress", srv.ClientURL(), name, tmpDir))
+			checkErr(t, mgr.DeleteStream(name), "delete failed")
+
+			return name, tmpDir
+		}
+
+		t.Run("config", func(t *testing.T) {
+			name, dir := backup(t)
+			runNatsCli(t, fmt.Sprintf("--server='%s' stream restore %s --no-progress --transform 'ORDERS.*:RESTORED.{{wildcard(1)}}'", srv.ClientURL(), dir))
+
+			stream, err := mgr.LoadStream(name)
+			checkErr(t, err, "load failed")
+			if strings.Join(stream.Subjects(), ",") != "RESTORED.*" {
+				t.Fatalf("unexpected subjects %v", stream.Subjects())
+			}
+
+			msg, err := stream.ReadMessage(1)
+			checkErr(t, err, "read failed")
+			if msg.Subject != "ORDERS.new" {
+				t.Fatalf("unexpected subject %s", msg.Subject)
+			}
+
+			checkErr(t, stream.Delete(), "delete failed")
+		})
+
+		t.Run("data", func(t *testing.T) {
+			name, dir := backup(t)
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream restore %s --no-progress --transform 'ORDERS.*:RESTORED.{{wildcard(1)}}' --transform-data", srv.ClientURL(), dir))
+			expectMatchLine(t, string(out), "Republished 5 messages through subject transforms")
+
+			stream, err := mgr.LoadStream(name)
+			checkErr(t, err, "load failed")
+
+			nfo, err := stream.State(api.JSApiStreamInfoRequest{SubjectsFilter: ">"})
+			checkErr(t, err, "state failed")
+			if nfo.Msgs != 5 || nfo.FirstSeq != 6 || len(nfo.Subjects) != 2 || nfo.Subjects["RESTORED.new"] != 3 || nfo.Subjects["RESTORED.done"] != 2 {
+				t.Fatalf("unexpected state %+v", nfo)
+			}
+
+			// control headers are not replayed
+			msg, err := stream.ReadMessage(6)
+			checkErr(t, err, "read failed")
+			hdrs, err := nats.DecodeHeadersMsg(msg.Header)
+			checkErr(t, err, "header decode failed")
+			if hdrs.Get("Nats-Expected-Last-Sequence") != "" || hdrs.Get("Nats-Msg-Id") != "" {
+				t.Fatalf("control headers were republished: %v", hdrs)
+			}
+		})
+
+		t.Run("unmatched", func(t *testing.T) {
+			_, dir := backup(t)
+			out, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream restore %s --no-progress --transform 'OTHER.>:RESTORED.>'", srv.ClientURL(), dir))
+			if err == nil {
+				t.Fatalf("expected restore to fail: %s", out)
+			}
+			expectMatchLine(t, string(out), "subject ORDERS.\\* is not matched by any transform")
+		})
+
+		return nil
+	})
+}
+
+func TestStreamRestoreRemote(t *testing.T) {
 	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
 		name := setupStreamTest(t, mgr)
+		for i := 0; i < 1000; i++ {
+			_, err := nc.Request("ORDERS.new", []byte(strings.Repeat("x", 1024)), time.Second)
+			if err != nil {
+				t.Fatalf("publish failed: %v", err)
+			}
+		}
+
 		tmpDir := t.TempDir()
+		runNatsCli(t, fmt.Sprintf("--server='%s' stream backup %s %s/backup --no-progress", srv.ClientURL(), name, tmpDir))
+
+		// an archive holding backup.json followed by stream.tar.s2
+		archive := bytes.NewBuffer(nil)
+		gz := gzip.NewWriter(archive)
+		tw := tar.NewWriter(gz)
+		for _, f := range []string{"backup.json", "stream.tar.s2"} {
+			body, err := os.ReadFile(filepath.Join(tmpDir, "backup", f))
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			err = tw.WriteHeader(&tar.Header{Name: "backup/" + f, Mode: 0600, Size: int64(len(body))})
+			if err != nil {
+				t.Fatalf("tar failed: %v", err)
+			}
+			_, err = tw.Write(body)
+			if err != nil {
+				t.Fatalf("tar failed: %v", err)
+			}
+		}
+		tw.Close()
+		gz.Close()
+
+		var interrupted atomic.Bool
+		mux := http.NewServeMux()
+		mux.Handle("/dir/", http.StripPrefix("/dir/", http.FileServer(http.Dir(tmpDir))))
+		mux.HandleFunc("/bucket/", func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			http.StripPrefix("/bucket/", http.FileServer(http.Dir(tmpDir))).ServeHTTP(w, r)
+		})
+		mux.HandleFunc("/backup.tgz", func(w http.ResponseWriter, r *http.Request) {
+			// the first download is cut short to force a resume
+			if r.Header.Get("Range") == "" && interrupted.CompareAndSwap(false, true) {
+				w.Header().Set("ETag", `"backup"`)
+				w.Header().Set("Content-Length", fmt.Sprintf("%d", archive.Len()))
+				w.Write(archive.Bytes()[:archive.Len()/2])
+				panic(http.ErrAbortHandler)
+			}
 
-		runNatsCli(t, fmt.Sprintf("--server='%s' stream backup %s %s", srv.ClientURL(), name, tmpDir))
-		mgr.DeleteStream(name)
-		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream restore %s", srv.ClientURL(), tmpDir)))
-		if !expectMatchLine(t, output, fmt.Sprintf("Starting restore of Stream \"%s\"", name)) ||
-			!expectMatchLine(t, output, fmt.Sprintf("Restored stream \"%s\" in \\d+s", name)) {
-			t.Errorf("Unexecpted output :%s", output)
+			w.Header().Set("ETag", `"backup"`)
+			http.ServeContent(w, r, "backup.tgz", time.Now(), bytes.NewReader(archive.Bytes()))
+		})
+		hs := httptest.NewServer(mux)
+		defer hs.Close()
+
+		s3env := map[string]string{"AWS_ENDPOINT_URL": hs.URL, "AWS_ACCESS_KEY_ID": "key", "AWS_SECRET_ACCESS_KEY": "secret"}
+
+		for _, u := range []string{hs.URL + "/dir/backup", hs.URL + "/backup.tgz", "s3://bucket/backup"} {
+			err := mgr.DeleteStream(name)
+			if err != nil {
+				t.Fatalf("delete failed: %v", err)
+			}
+
+			out, err := runNatsCliCore(t, "", s3env, fmt.Sprintf("--server='%s' stream restore %s --no-progress", srv.ClientURL(), u))
+			if err != nil {
+				t.Fatalf("restore from %s failed: %v", u, err)
+			}
+			output := string(out)
+			if !expectMatchLine(t, output, fmt.Sprintf("Restored stream \"%s\" in \\d+s", name)) {
+				t.Errorf("Unexpected output: %s", output)
+			}
+
+			stream, err := mgr.LoadStream(name)
+			if err != nil {
+				t.Fatalf("load failed: %v", err)
+			}
+			nfo, err := stream.State()
+			if err != nil {
+				t.Fatalf("state failed: %v", err)
+			}
+			if nfo.Msgs != 1000 {
+				t.Errorf("expected 1000 messages restored from %s got %d", u, nfo.Msgs)
+			}
+		}
+
+		if !interrupted.Load() {
+			t.Errorf("expected the archive download to be interrupted")
 		}
+
 		return nil
 	})
 }
@@ -555,6 +1787,289 @@ func TestStreamSeal(t *testing.T) {
 		}
 		return nil
 	})
+
+	t.Run("--backup-dir", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			name := setupStreamTest(t, mgr)
+			target := filepath.Join(t.TempDir(), "backup")
+
+			output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream seal %s --backup-dir %s --no-progress --force", srv.ClientURL(), name, target)))
+			if !expectMatchLine(t, output, fmt.Sprintf("Starting backup of Stream \"%s\"", name)) {
+				t.Errorf("backup was not performed: %s", output)
+			}
+
+			for _, f := range []string{"backup.json", "stream.tar.s2"} {
+				_, err := os.Stat(filepath.Join(target, f))
+				if err != nil {
+					t.Errorf("backup file %s not found: %s", f, err)
+				}
+			}
+
+			stream, err := mgr.LoadStream(name)
+			checkErr(t, err, "load failed")
+			if !stream.Sealed() {
+				t.Errorf("stream was not sealed")
+			}
+
+			return nil
+		})
+	})
+
+	t.Run("existing --backup-dir", func(t *testing.T) {
+		withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+			name := setupStreamTest(t, mgr)
+
+			err := runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream seal %s --backup-dir %s --force", srv.ClientURL(), name, t.TempDir()))
+			if err == nil {
+				t.Errorf("expected existing backup directory to fail")
+			}
+
+			stream, err := mgr.LoadStream(name)
+			checkErr(t, err, "load failed")
+			if stream.Sealed() {
+				t.Errorf("stream was sealed")
+			}
+
+			return nil
+		})
+	})
+}
+
+func TestStreamDiff(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		withJSServer(t, func(t *testing.T, target *server.Server, tnc *nats.Conn, tmgr *jsm.Manager) error {
+			_, err := mgr.NewStream("ONE", jsm.Subjects("one.>"), jsm.MemoryStorage(), jsm.MaxAge(time.Hour))
+			checkErr(t, err, "create failed")
+			_, err = mgr.NewStream("TWO", jsm.Subjects("two.>"), jsm.MemoryStorage(), jsm.MaxAge(2*time.Hour))
+			checkErr(t, err, "create failed")
+
+			out, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream diff ONE TWO", srv.ClientURL()))
+			if err == nil {
+				t.Errorf("expected differing streams to fail")
+			}
+			if !expectMatchLine(t, string(out), "--- ONE") || !expectMatchLine(t, string(out), `\+\+\+ TWO`) || !strings.Contains(string(out), `-      "one.>"`) || !strings.Contains(string(out), `+      "two.>"`) {
+				t.Errorf("unexpected diff: %s", out)
+			}
+
+			_, err = tmgr.NewStream("ONE", jsm.Subjects("one.>"), jsm.MemoryStorage(), jsm.MaxAge(time.Hour))
+			checkErr(t, err, "create failed")
+
+			ctxFile := filepath.Join(t.TempDir(), "target.json")
+			err = os.WriteFile(ctxFile, []byte(fmt.Sprintf(`{"url":%q}`, target.ClientURL())), 0600)
+			checkErr(t, err, "unable to write context")
+
+			out = runNatsCli(t, fmt.Sprintf("--server='%s' stream diff ONE --target-context %s", srv.ClientURL(), ctxFile))
+			if !expectMatchLine(t, string(out), "Stream ONE and target/ONE are identical") {
+				t.Errorf("unexpected output: %s", out)
+			}
+
+			_, err = nc.Request("one.x", []byte("hello"), time.Second)
+			checkErr(t, err, "publish failed")
+
+			out, err = runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream diff ONE --target-context %s --state", srv.ClientURL(), ctxFile))
+			if err == nil {
+				t.Errorf("expected differing state to fail")
+			}
+			if !strings.Contains(string(out), `-    "messages": 1,`) {
+				t.Errorf("unexpected diff: %s", out)
+			}
+
+			return nil
+		})
+
+		return nil
+	})
+}
+
+func TestStreamMoveAccount(t *testing.T) {
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "server.conf")
+	err := os.WriteFile(conf, []byte(fmt.Sprintf(`
+listen: 127.0.0.1:-1
+jetstream: {store_dir: %q}
+accounts {
+  A: {
+    jetstream: enabled
+    users: [{user: a, password: a}]
+    exports: [{service: "$JS.API.>"}, {stream: "deliver.b.>"}]
+    imports: [{service: {account: B, subject: "$JS.FC.>"}}]
+  }
+  B: {
+    jetstream: enabled
+    users: [{user: b, password: b}]
+    exports: [{service: "$JS.FC.>"}]
+    imports: [{service: {account: A, subject: "$JS.API.>"}, to: "JS.A.API.>"}, {stream: {account: A, subject: "deliver.b.>"}}]
+  }
+}`, filepath.Join(dir, "store"))), 0600)
+	checkErr(t, err, "could not write server config")
+
+	opts, err := server.ProcessConfigFile(conf)
+	checkErr(t, err, "invalid server config")
+	opts.Port = -1
+
+	srv, err := server.NewServer(opts)
+	checkErr(t, err, "could not start server")
+	go srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatalf("nats server did not start")
+	}
+	defer srv.Shutdown()
+
+	ncA, err := nats.Connect(srv.ClientURL(), nats.UserInfo("a", "a"))
+	checkErr(t, err, "connect failed")
+	defer ncA.Close()
+	mgrA, err := jsm.New(ncA)
+	checkErr(t, err, "manager failed")
+
+	ncB, err := nats.Connect(srv.ClientURL(), nats.UserInfo("b", "b"))
+	checkErr(t, err, "connect failed")
+	defer ncB.Close()
+	mgrB, err := jsm.New(ncB)
+	checkErr(t, err, "manager failed")
+
+	_, err = mgrA.NewStream("ORDERS", jsm.Subjects("orders.>"), jsm.MemoryStorage())
+	checkErr(t, err, "create failed")
+	for i := 0; i < 10; i++ {
+		_, err = ncA.Request("orders.new", []byte("order"), time.Second)
+		checkErr(t, err, "publish failed")
+	}
+
+	ctxFile := filepath.Join(dir, "b.json")
+	err = os.WriteFile(ctxFile, []byte(fmt.Sprintf(`{"url":%q,"user":"b","password":"b"}`, srv.ClientURL())), 0600)
+	checkErr(t, err, "unable to write context")
+
+	checkpoint := filepath.Join(dir, "checkpoint.json")
+	move := fmt.Sprintf("--server='%s' --user a --password a stream move-account ORDERS --target-context %s --api-prefix JS.A.API --deliver-prefix deliver.b --checkpoint %s --wait 10s", srv.ClientURL(), ctxFile, checkpoint)
+
+	t.Run("abort", func(t *testing.T) {
+		out := runNatsCli(t, move+" --no-detach -f")
+		if !expectMatchLine(t, string(out), "Stream ORDERS has caught up with 10 messages") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		known, err := mgrB.IsKnownStream("ORDERS")
+		checkErr(t, err, "known failed")
+		if !known {
+			t.Fatalf("target stream was not created")
+		}
+
+		runNatsCli(t, move+" --abort -f")
+
+		known, err = mgrB.IsKnownStream("ORDERS")
+		checkErr(t, err, "known failed")
+		if known {
+			t.Errorf("target stream was not removed")
+		}
+	})
+
+	t.Run("move", func(t *testing.T) {
+		out := runNatsCli(t, move+" --original remove -f")
+		if !expectMatchLine(t, string(out), "Moved Stream ORDERS to ORDERS") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		target, err := mgrB.LoadStream("ORDERS")
+		checkErr(t, err, "load failed")
+		state, err := target.State()
+		checkErr(t, err, "state failed")
+		if state.Msgs != 10 || len(target.Sources()) != 0 {
+			t.Errorf("expected 10 messages and no sources, got %d messages and %v", state.Msgs, target.Sources())
+		}
+
+		known, err := mgrA.IsKnownStream("ORDERS")
+		checkErr(t, err, "known failed")
+		if known {
+			t.Errorf("original stream was not removed")
+		}
+
+		// a completed move is not repeated
+		out = runNatsCli(t, move+" -f")
+		if !expectMatchLine(t, string(out), "Stream ORDERS was already moved to ORDERS") {
+			t.Errorf("unexpected output: %s", out)
+		}
+	})
+}
+
+func TestStreamRollup(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		stream, err := mgr.NewStream("ROLLUP", jsm.Subjects("rollup.>"), jsm.MemoryStorage())
+		checkErr(t, err, "create failed")
+
+		for _, subj := range []string{"rollup.a", "rollup.a", "rollup.a", "rollup.b", "rollup.b"} {
+			_, err = nc.Request(subj, []byte("hello"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream rollup ROLLUP rollup.a -f", srv.ClientURL()))
+		if err == nil {
+			t.Errorf("expected roll-up to fail on a stream without roll-ups allowed")
+		}
+
+		checkErr(t, stream.UpdateConfiguration(stream.Configuration(), jsm.AllowRollup()), "update failed")
+
+		err = runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream rollup ROLLUP other.a -f", srv.ClientURL()))
+		if err == nil {
+			t.Errorf("expected roll-up to fail for subjects outside the stream")
+		}
+
+		out := runNatsCli(t, fmt.Sprintf("--server='%s' stream rollup ROLLUP rollup.a --payload summary -f", srv.ClientURL()))
+		if !expectMatchLine(t, string(out), "Published roll-up message to rollup.a at sequence 6, Stream ROLLUP now holds 3 messages") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		msg, err := stream.ReadLastMessageForSubject("rollup.a")
+		checkErr(t, err, "read failed")
+		if string(msg.Data) != "summary" {
+			t.Errorf("unexpected roll-up body: %q", msg.Data)
+		}
+
+		out = runNatsCli(t, fmt.Sprintf("--server='%s' stream rollup ROLLUP rollup.b --mode all -f", srv.ClientURL()))
+		if !expectMatchLine(t, string(out), "Stream ROLLUP now holds 1 messages") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		return nil
+	})
+}
+
+func TestStreamRepublishTest(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		_, err := mgr.NewStream("REPUB",
+			jsm.Subjects("in.>"),
+			jsm.MemoryStorage(),
+			jsm.SubjectTransform(&api.SubjectTransformConfig{Source: "in.orders.*", Destination: "orders.{{wildcard(1)}}"}),
+			jsm.Republish(&api.RePublish{Source: "orders.>", Destination: "out.orders.>"}))
+		if err != nil {
+			t.Fatalf("unable to create stream: %s", err)
+		}
+
+		out := runNatsCli(t, fmt.Sprintf("--server='%s' stream republish-test REPUB --subject in.orders.123 --json", srv.ClientURL()))
+
+		var results []map[string]string
+		err = json.Unmarshal(out, &results)
+		if err != nil {
+			t.Fatalf("invalid json: %s: %s", err, out)
+		}
+		if len(results) != 1 || results[0]["stored_subject"] != "orders.123" || results[0]["republished_subject"] != "out.orders.123" {
+			t.Errorf("unexpected results: %v", results)
+		}
+
+		out, err = runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream republish-test REPUB --subject in.orders.123 --subject in.invoices.1 --subject other.1", srv.ClientURL()))
+		if err == nil {
+			t.Errorf("expected unmatched subjects to fail")
+		}
+		if !expectMatchLine(t, string(out), "in.invoices.1", "in.invoices.1", "does not match the republish source") {
+			t.Errorf("unmatched republish source not flagged: %s", out)
+		}
+		if !expectMatchLine(t, string(out), "other.1", "not stored in the stream") {
+			t.Errorf("unstored subject not flagged: %s", out)
+		}
+		if !expectMatchLine(t, string(out), "in.orders.123", "orders.123", "out.orders.123") {
+			t.Errorf("matching subject not shown: %s", out)
+		}
+
+		return nil
+	})
 }
 
 func TestStreamGaps(t *testing.T) {
@@ -569,8 +2084,121 @@ func TestStreamGaps(t *testing.T) {
 	})
 }
 
-// Graph command has to be run with a terminal
-// func TestStreamGraph(t *testing.T) {}
+func TestStreamVerify(t *testing.T) {
+	withJSCluster(t, func(t *testing.T, servers []*server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		stream, err := mgr.NewStream("VERIFY", jsm.Subjects("verify.>"), jsm.Replicas(3))
+		checkErr(t, err, "unable to create stream")
+
+		for i := 0; i < 10; i++ {
+			_, err = nc.Request("verify.test", []byte("hello"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		checkErr(t, stream.DeleteMessage(3), "delete failed")
+		checkErr(t, stream.DeleteMessage(4), "delete failed")
+		checkErr(t, stream.DeleteMessage(8), "delete failed")
+
+		sysCtx := filepath.Join(t.TempDir(), "sys.json")
+		err = os.WriteFile(sysCtx, []byte(fmt.Sprintf(`{"url":%q,"user":"sys","password":"pass"}`, servers[0].ClientURL())), 0600)
+		checkErr(t, err, "context write failed")
+
+		var res struct {
+			Deleted  int         `json:"deleted"`
+			Gaps     [][2]uint64 `json:"unexplained_gaps"`
+			Replicas []struct {
+				Server   string   `json:"server"`
+				Leader   bool     `json:"leader"`
+				Messages uint64   `json:"messages"`
+				Drift    []string `json:"drift"`
+			} `json:"replicas"`
+			ReplicaError string `json:"replica_error"`
+			Consistent   bool   `json:"consistent"`
+		}
+
+		// replicas apply deletes asynchronously so allow them to catch up
+		var out []byte
+		for i := 0; i < 10; i++ {
+			out, err = runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream verify VERIFY --force --json --system-context %s", servers[0].ClientURL(), sysCtx))
+			if err == nil {
+				break
+			}
+			time.Sleep(250 * time.Millisecond)
+		}
+		checkErr(t, err, "verify failed: %s", out)
+
+		err = json.Unmarshal(out, &res)
+		checkErr(t, err, "invalid json: %s", out)
+
+		if !res.Consistent || res.Deleted != 3 || len(res.Gaps) != 0 || res.ReplicaError != "" {
+			t.Fatalf("unexpected result: %s", out)
+		}
+
+		if len(res.Replicas) != 3 {
+			t.Fatalf("expected 3 replicas: %s", out)
+		}
+
+		var leaders int
+		for _, r := range res.Replicas {
+			if r.Leader {
+				leaders++
+			}
+			if r.Messages != 7 || len(r.Drift) > 0 {
+				t.Errorf("unexpected replica state: %+v", r)
+			}
+		}
+		if leaders != 1 {
+			t.Errorf("expected 1 leader: %s", out)
+		}
+
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream verify VERIFY --force --no-replicas --no-progress", servers[0].ClientURL())))
+		if !expectMatchLine(t, output, "Unexplained Gaps", "0") || !expectMatchLine(t, output, "Consistent", "true") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		return nil
+	})
+}
+
+// Graph command has to be run with a terminal unless recording
+func TestStreamGraphRecord(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+
+		err := runNatsCliWithError(t, fmt.Sprintf("--server='%s' stream graph %s", srv.ClientURL(), name))
+		if err == nil {
+			t.Fatalf("expected graph without a terminal to fail")
+		}
+
+		for i := 0; i < 5; i++ {
+			_, err = nc.Request("ORDERS.new", []byte("hello"), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		record := filepath.Join(t.TempDir(), "graph.csv")
+		output := string(runNatsCli(t, fmt.Sprintf("--server='%s' stream graph %s --record %s --duration 2500ms", srv.ClientURL(), name, record)))
+		if !expectMatchLine(t, output, "Recorded 2 samples to") {
+			t.Errorf("unexpected output: %s", output)
+		}
+
+		data, err := os.ReadFile(record)
+		checkErr(t, err, "read failed")
+
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		checkErr(t, err, "invalid csv")
+
+		if len(rows) != 3 {
+			t.Fatalf("expected a header and 2 rows: %s", data)
+		}
+		if strings.Join(rows[0], ",") != "time,messages,bytes,stored_rate,removed_rate,lag" {
+			t.Errorf("unexpected header: %v", rows[0])
+		}
+		if rows[1][1] != "5" || rows[1][5] != "0" {
+			t.Errorf("unexpected row: %v", rows[1])
+		}
+
+		return nil
+	})
+}
 
 func TestStreamStepDown(t *testing.T) {
 	withJSCluster(t, func(t *testing.T, servers []*server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
@@ -626,3 +2254,197 @@ func getStreamInfo(name string, mgr *jsm.Manager) (*api.StreamState, error) {
 	}
 	return &info.State, nil
 }
+
+func TestStreamAddPlacementPreview(t *testing.T) {
+	withJSCluster(t, func(t *testing.T, servers []*server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		sysCtx := filepath.Join(t.TempDir(), "sys.json")
+		err := os.WriteFile(sysCtx, []byte(fmt.Sprintf(`{"url":%q,"user":"sys","password":"pass"}`, servers[0].ClientURL())), 0600)
+		checkErr(t, err, "context write failed")
+
+		t.Run("satisfiable", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream add PREVIEW --subjects preview.> --replicas 3 --defaults --preview-placement --system-context %s", servers[0].ClientURL(), sysCtx))
+			expectMatchLine(t, string(out), "Placement Preview")
+			expectMatchLine(t, string(out), "Stream PREVIEW will likely be placed on s\\d, s\\d, s\\d")
+
+			known, err := mgr.IsKnownStream("PREVIEW")
+			checkErr(t, err, "stream lookup failed")
+			if !known {
+				t.Fatalf("stream was not created")
+			}
+		})
+
+		t.Run("not requested", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream add NOPREVIEW --subjects nopreview.> --replicas 3 --defaults", servers[0].ClientURL()))
+			if strings.Contains(string(out), "Placement Preview") {
+				t.Fatalf("placement was previewed without being requested: %s", out)
+			}
+
+			known, err := mgr.IsKnownStream("NOPREVIEW")
+			checkErr(t, err, "stream lookup failed")
+			if !known {
+				t.Fatalf("stream was not created")
+			}
+		})
+
+		t.Run("disabled", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream add DISABLED --subjects disabled.> --replicas 3 --defaults --no-preview-placement --system-context %s", servers[0].ClientURL(), sysCtx))
+			if strings.Contains(string(out), "Placement Preview") {
+				t.Fatalf("placement was previewed while disabled: %s", out)
+			}
+
+			known, err := mgr.IsKnownStream("DISABLED")
+			checkErr(t, err, "stream lookup failed")
+			if !known {
+				t.Fatalf("stream was not created")
+			}
+		})
+
+		t.Run("unsatisfiable", func(t *testing.T) {
+			out, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream add TAGGED --subjects tagged.> --replicas 3 --tag gpu --defaults --preview-placement --system-context %s", servers[0].ClientURL(), sysCtx))
+			if err == nil {
+				t.Fatalf("expected creation to fail: %s", out)
+			}
+			expectMatchLine(t, string(out), "missing tag gpu")
+			expectMatchLine(t, string(out), "WARNING: only 0 servers match the placement of Stream TAGGED which requires 3 replicas")
+		})
+
+		return nil
+	})
+}
+
+func TestStreamAudit(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		_, err := mgr.NewStream("AUDIT_GOOD", jsm.Subjects("good"), jsm.MaxAge(24*time.Hour))
+		checkErr(t, err, "create failed")
+		_, err = mgr.NewStream("AUDIT_BAD", jsm.Subjects("bad"), jsm.InterestRetention(), jsm.MemoryStorage())
+		checkErr(t, err, "create failed")
+		_, err = mgr.NewStream("OTHER", jsm.Subjects("other"), jsm.InterestRetention())
+		checkErr(t, err, "create failed")
+
+		baseline := filepath.Join(t.TempDir(), "baseline.yaml")
+		err = os.WriteFile(baseline, []byte("streams: [\"AUDIT_*\"]\nmax_max_age: 7d\nforbidden_retention: [interest]\nforbidden_storage: [memory]\n"), 0600)
+		checkErr(t, err, "write failed")
+
+		t.Run("violations", func(t *testing.T) {
+			out, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream audit --baseline %s --json", srv.ClientURL(), baseline))
+			if err == nil {
+				t.Fatalf("expected audit to fail: %s", out)
+			}
+
+			var violations []map[string]string
+			checkErr(t, json.Unmarshal(bytes.TrimSpace(bytes.Split(out, []byte("\nnats: error"))[0]), &violations), "invalid json: %s", out)
+
+			var rules []string
+			for _, v := range violations {
+				if v["stream"] != "AUDIT_BAD" {
+					t.Fatalf("unexpected violation %v", v)
+				}
+				rules = append(rules, v["rule"])
+			}
+			if strings.Join(rules, ",") != "max_max_age,forbidden_retention,forbidden_storage" {
+				t.Fatalf("unexpected violations: %s", out)
+			}
+		})
+
+		t.Run("table", func(t *testing.T) {
+			out, err := runNatsCliCore(t, "", nil, fmt.Sprintf("--server='%s' stream audit --baseline %s", srv.ClientURL(), baseline))
+			if err == nil {
+				t.Fatalf("expected audit to fail: %s", out)
+			}
+			expectMatchLine(t, string(out), "Baseline violations in 1 of 2 streams")
+			expectMatchLine(t, string(out), "AUDIT_BAD", "forbidden_retention", "uses forbidden interest retention")
+			expectMatchLine(t, string(out), "1 streams violate baseline")
+		})
+
+		t.Run("compliant", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream audit 'AUDIT_G*' --baseline %s", srv.ClientURL(), baseline))
+			expectMatchLine(t, string(out), "All 1 streams comply with baseline")
+		})
+
+		return nil
+	})
+}
+
+func TestStreamDedupeReport(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		_, err := mgr.NewStream("DEDUPE", jsm.Subjects("dedupe.>"), jsm.DuplicateWindow(200*time.Millisecond))
+		checkErr(t, err, "create failed")
+
+		publish := func(subj string, id string, producer string) {
+			msg := nats.NewMsg(subj)
+			if id != "" {
+				msg.Header.Set("Nats-Msg-Id", id)
+			}
+			msg.Header.Set("App", producer)
+			_, err := nc.RequestMsg(msg, time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		publish("dedupe.a", "1", "app1")
+		publish("dedupe.a", "1", "app1") // rejected by the server
+		publish("dedupe.b", "2", "app2")
+		publish("dedupe.b", "", "app2")
+		time.Sleep(300 * time.Millisecond)
+		publish("dedupe.a", "1", "app1")
+		time.Sleep(300 * time.Millisecond)
+		publish("dedupe.c", "1", "app1")
+
+		t.Run("json", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream dedupe-report DEDUPE --window 1m --json", srv.ClientURL()))
+
+			var report struct {
+				Messages int `json:"messages"`
+				Groups   []struct {
+					Name              string `json:"name"`
+					Messages          int    `json:"messages"`
+					WithoutID         int    `json:"without_id"`
+					DuplicateIDs      int    `json:"duplicate_ids"`
+					DuplicateMessages int    `json:"duplicate_messages"`
+				} `json:"groups"`
+				Duplicates []struct {
+					ID       string   `json:"id"`
+					Count    int      `json:"count"`
+					Subjects []string `json:"subjects"`
+				} `json:"duplicates"`
+			}
+			checkErr(t, json.Unmarshal(out, &report), "invalid json: %s", out)
+
+			if report.Messages != 5 || len(report.Groups) != 3 || len(report.Duplicates) != 1 {
+				t.Fatalf("unexpected report: %s", out)
+			}
+			if report.Groups[0].Name != "dedupe.a" || report.Groups[0].DuplicateIDs != 1 || report.Groups[0].DuplicateMessages != 1 {
+				t.Fatalf("unexpected group: %+v", report.Groups[0])
+			}
+			if report.Groups[1].Name != "dedupe.c" || report.Groups[1].DuplicateMessages != 1 || report.Groups[2].WithoutID != 1 {
+				t.Fatalf("unexpected groups: %s", out)
+			}
+			if report.Duplicates[0].ID != "1" || report.Duplicates[0].Count != 3 || strings.Join(report.Duplicates[0].Subjects, ",") != "dedupe.a,dedupe.c" {
+				t.Fatalf("unexpected duplicates: %+v", report.Duplicates)
+			}
+		})
+
+		t.Run("producer", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream dedupe-report DEDUPE --window 1m --producer-header App", srv.ClientURL()))
+			expectMatchLine(t, string(out), "Duplicate analysis of 5 messages in Stream DEDUPE")
+			expectMatchLine(t, string(out), "app1", "3", "0", "1", "2")
+			expectMatchLine(t, string(out), "app2", "2", "1", "0", "0")
+			expectMatchLine(t, string(out), "Publishers retried messages with the same ID outside the 200ms duplicate window")
+		})
+
+		t.Run("default window", func(t *testing.T) {
+			out := runNatsCli(t, fmt.Sprintf("--server='%s' stream dedupe-report DEDUPE --json", srv.ClientURL()))
+
+			var report struct {
+				Window          time.Duration `json:"window"`
+				DuplicateWindow time.Duration `json:"duplicate_window"`
+			}
+			checkErr(t, json.Unmarshal(out, &report), "invalid json: %s", out)
+
+			if report.DuplicateWindow != 200*time.Millisecond || report.Window != 10*report.DuplicateWindow {
+				t.Fatalf("expected a window of 10 duplicate windows got %v for %v", report.Window, report.DuplicateWindow)
+			}
+		})
+
+		return nil
+	})
+}
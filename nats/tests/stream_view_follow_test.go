@@ -0,0 +1,125 @@
+// Copyright 2026 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jsm.go"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func TestStreamViewFollow(t *testing.T) {
+	withJSServer(t, func(t *testing.T, srv *server.Server, nc *nats.Conn, mgr *jsm.Manager) error {
+		name := setupStreamTest(t, mgr)
+
+		for i := 1; i <= 15; i++ {
+			_, err := nc.Request("ORDERS.new", []byte(fmt.Sprintf("order %d", i)), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		args := []string{"--server", srv.ClientURL(), "stream", "view", name, "5", "--follow"}
+		var cmd *exec.Cmd
+		if os.Getenv("CI") == "true" {
+			cmd = exec.Command("../nats", args...)
+		} else {
+			cmd = exec.Command("go", append([]string{"run", "../main.go"}, args...)...)
+		}
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		cmd.Stderr = cmd.Stdout
+
+		stdout, err := cmd.StdoutPipe()
+		checkErr(t, err, "pipe failed")
+
+		err = cmd.Start()
+		checkErr(t, err, "start failed")
+		defer syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+
+		lines := make(chan string, 100)
+		go func() {
+			scanner := bufio.NewScanner(stdout)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+			close(lines)
+		}()
+
+		var seen []string
+		waitFor := func(expected string) {
+			t.Helper()
+
+			timeout := time.After(30 * time.Second)
+			for {
+				select {
+				case line, ok := <-lines:
+					if !ok {
+						t.Fatalf("view exited before %q was shown: %s", expected, strings.Join(seen, "\n"))
+					}
+					seen = append(seen, line)
+					if strings.TrimSpace(line) == expected {
+						return
+					}
+				case <-timeout:
+					t.Fatalf("%q was not shown: %s", expected, strings.Join(seen, "\n"))
+				}
+			}
+		}
+
+		// the last page is shown before following new messages
+		waitFor("order 15")
+
+		for i := 16; i <= 17; i++ {
+			_, err := nc.Request("ORDERS.new", []byte(fmt.Sprintf("order %d", i)), time.Second)
+			checkErr(t, err, "publish failed")
+		}
+
+		waitFor("order 16")
+		waitFor("order 17")
+
+		for _, line := range seen {
+			if strings.TrimSpace(line) == "order 10" {
+				t.Errorf("messages before the last page were shown: %s", strings.Join(seen, "\n"))
+			}
+		}
+
+		// interrupting the view is a clean exit
+		err = syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+		checkErr(t, err, "interrupt failed")
+
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+
+		select {
+		case err := <-done:
+			// go run reports an interrupt as a failure even when the command exits cleanly
+			if err != nil && os.Getenv("CI") == "true" {
+				t.Errorf("view did not exit cleanly: %v", err)
+			}
+		case <-time.After(30 * time.Second):
+			t.Errorf("view did not exit after an interrupt")
+		}
+
+		return nil
+	})
+}